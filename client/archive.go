@@ -0,0 +1,176 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ArchiveMemberHeader заголовок с путем файла внутри архива при UploadArchiveContents
+const ArchiveMemberHeader = "X-Archive-Member"
+
+// UploadArchiveContents загружает файлы из архива .tar.gz, не распаковывая его на диск:
+// содержимое каждого файла потоком идет из tar.Reader в запрос. Путь файла в архиве
+// передается в заголовках X-Archive-Member и X-Relative-Path, поэтому сервер сохраняет
+// структуру директорий архива. Директории, ссылки, пустые файлы и пути за пределами
+// архива (абсолютные или с ..) пропускаются. Файлы отправляются по очереди, так как архив
+// читается последовательно, и без повторов: прочитанный фрагмент архива нельзя отправить
+// заново. При FailFast первая ошибка прерывает загрузку, иначе загружаются все файлы
+// и возвращаются ошибки каждого
+func (c *HTTPClient) UploadArchiveContents(ctx context.Context, archivePath, serverURL string, progressCallback ProgressCallback) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	if c.config.CompressUpload && len(c.config.EncryptionKey) > 0 {
+		return fmt.Errorf("сжатие не поддерживается вместе с шифрованием")
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия архива: %w", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("ошибка распаковки архива: %w", err)
+	}
+	defer gz.Close()
+
+	if err := c.acquireSlot(ctx); err != nil {
+		return err
+	}
+	defer func() { <-c.sem }()
+
+	logger := c.logger().With("archive", archivePath, "url", serverURL)
+	logger.Info("Начало загрузки из архива")
+	startTime := time.Now()
+
+	progressCallback = c.serializeProgress(progressCallback)
+	archive := tar.NewReader(gz)
+	uploaded := 0
+	var uploadErrors []error
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка чтения архива: %w", err)
+		}
+
+		member, ok := archiveMemberPath(header.Name)
+		switch {
+		case header.Typeflag != tar.TypeReg:
+			if header.Typeflag != tar.TypeDir {
+				logger.Warn("Пропускаем элемент архива, не являющийся файлом", "member", header.Name)
+			}
+			continue
+		case !ok:
+			logger.Warn("Пропускаем файл с путем за пределами архива", "member", header.Name)
+			continue
+		case header.Size == 0:
+			logger.Warn("Пропускаем пустой файл", "member", member)
+			continue
+		}
+
+		if err := c.uploadArchiveMember(ctx, archive, member, header.Size, serverURL, progressCallback); err != nil {
+			err = fmt.Errorf("ошибка загрузки файла %s из архива: %w", member, err)
+			if c.config.FailFast {
+				return err
+			}
+			uploadErrors = append(uploadErrors, err)
+			continue
+		}
+		uploaded++
+	}
+
+	if uploaded == 0 && len(uploadErrors) == 0 {
+		return fmt.Errorf("в архиве нет файлов для загрузки")
+	}
+	if err := errors.Join(uploadErrors...); err != nil {
+		return err
+	}
+	logger.Info("Архив загружен", "files", uploaded, "duration", time.Since(startTime).Round(time.Millisecond))
+	return nil
+}
+
+// uploadArchiveMember отправляет текущий файл архива размером size
+func (c *HTTPClient) uploadArchiveMember(ctx context.Context, r io.Reader, member string, size int64, serverURL string, progressCallback ProgressCallback) error {
+	task := uploadTask{filePath: member, remoteName: path.Base(member)}.
+		withHeader(ArchiveMemberHeader, member).
+		withHeader(RelativePathHeader, member)
+	if len(c.config.EncryptionKey) == 0 {
+		task = task.withHeader(FileSizeHeader, strconv.FormatInt(size, 10))
+	}
+	task = c.withCorrelationID(task)
+	logger := c.logger().With("member", member, "url", serverURL, "correlation_id", c.correlationID(task))
+	startTime := time.Now()
+
+	// После ответа сервера с ошибкой sendStream не ждет горутину записи, поэтому чтение
+	// запрещается до перехода к следующему файлу архива
+	src := &guardedReader{r: r}
+	defer src.close()
+
+	progressCallback, finish := c.trackProgress(member, progressCallback)
+	ctx, span := c.startUploadSpan(ctx, task, serverURL, 0)
+	sessionID, err := c.sendStream(ctx, src, size, task, serverURL, ThrottleProgress(c.config.ProgressInterval, progressCallback))
+	endUploadSpan(span, err)
+	if err != nil {
+		finish(err)
+		logger.Error("Ошибка загрузки", "error", err)
+		return err
+	}
+	finish(nil)
+
+	logger.Info("Загрузка завершена",
+		"size", formatBytes(size),
+		"duration", time.Since(startTime).Round(time.Millisecond),
+		"session_id", sessionID)
+	return nil
+}
+
+// guardedReader reader, чтение из которого после close завершается ошибкой
+type guardedReader struct {
+	mu     sync.Mutex
+	r      io.Reader
+	closed bool
+}
+
+func (g *guardedReader) Read(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return 0, io.ErrClosedPipe
+	}
+	return g.r.Read(p)
+}
+
+// close запрещает чтение, дожидаясь завершения текущего вызова Read
+func (g *guardedReader) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+}
+
+// archiveMemberPath возвращает очищенный путь файла в архиве или false,
+// если путь абсолютный или выходит за пределы архива
+func archiveMemberPath(name string) (string, bool) {
+	cleaned := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"httpBinaryClient/server"
+)
+
+// writeTarGz создает архив .tar.gz с элементами headers; содержимое файла — его имя
+func writeTarGz(t *testing.T, headers []*tar.Header) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "data.tar.gz")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Ошибка создания архива: %v", err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, header := range headers {
+		content := ""
+		if header.Typeflag == tar.TypeReg && header.Size != 0 {
+			content = header.Name
+			header.Size = int64(len(content))
+		}
+		header.Mode = 0644
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("Ошибка записи архива: %v", err)
+		}
+		io.WriteString(tw, content)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Ошибка записи архива: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Ошибка записи архива: %v", err)
+	}
+	return archivePath
+}
+
+func TestUploadArchiveContents(t *testing.T) {
+	archivePath := writeTarGz(t, []*tar.Header{
+		{Name: "data/", Typeflag: tar.TypeDir},
+		{Name: "data/a.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "data/sub/b.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "./root.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "data/link.txt", Typeflag: tar.TypeSymlink, Linkname: "a.txt"},
+		{Name: "../evil.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "empty.txt", Typeflag: tar.TypeReg},
+	})
+
+	uploadDir := t.TempDir()
+	handler := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir: uploadDir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	var mu sync.Mutex
+	var members []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		members = append(members, r.Header.Get(ArchiveMemberHeader))
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	config := DefaultConfig()
+	config.ProgressInterval = 0
+	var progress []int64
+	err := NewHTTPClientWithConfig(config).UploadArchiveContents(context.Background(), archivePath, ts.URL+"/upload",
+		func(bytesTransferred, totalBytes int64, percentage float64) {
+			if percentage == 100 {
+				progress = append(progress, totalBytes)
+			}
+		})
+	if err != nil {
+		t.Fatalf("Ошибка загрузки архива: %v", err)
+	}
+
+	expected := []string{"data/a.txt", "data/sub/b.txt", "root.txt"}
+	if strings.Join(members, ",") != strings.Join(expected, ",") {
+		t.Errorf("Ожидались файлы %v, получены %v", expected, members)
+	}
+	for _, member := range expected {
+		content, err := os.ReadFile(filepath.Join(uploadDir, filepath.FromSlash(member)))
+		if err != nil || !strings.HasSuffix(string(content), member) {
+			t.Errorf("Файл %s не сохранен со структурой архива: %q, %v", member, content, err)
+		}
+	}
+	if len(progress) != len(expected) || progress[0] != int64(len("data/a.txt")) {
+		t.Errorf("Прогресс должен сообщать о каждом файле с его размером: %v", progress)
+	}
+}
+
+func TestUploadArchiveContents_Errors(t *testing.T) {
+	archivePath := writeTarGz(t, []*tar.Header{
+		{Name: "a.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "b.txt", Typeflag: tar.TypeReg, Size: -1},
+		{Name: "c.txt", Typeflag: tar.TypeReg, Size: -1},
+	})
+
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		member := r.Header.Get(ArchiveMemberHeader)
+		// Отказ до чтения тела: клиент переходит к следующему файлу, не дождавшись отправки
+		if member == "a.txt" {
+			http.Error(w, "отказ", http.StatusBadRequest)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		received = append(received, member)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	config := DefaultConfig()
+	config.FailFast = false
+	err := NewHTTPClientWithConfig(config).UploadArchiveContents(context.Background(), archivePath, ts.URL, nil)
+	if err == nil || !strings.Contains(err.Error(), "a.txt") {
+		t.Errorf("Ожидалась ошибка загрузки a.txt, получено %v", err)
+	}
+	sort.Strings(received)
+	if strings.Join(received, ",") != "b.txt,c.txt" {
+		t.Errorf("Без FailFast остальные файлы должны загружаться: %v", received)
+	}
+
+	// С FailFast первая ошибка прерывает загрузку
+	received = nil
+	config.FailFast = true
+	if err := NewHTTPClientWithConfig(config).UploadArchiveContents(context.Background(), archivePath, ts.URL, nil); err == nil {
+		t.Error("Ожидалась ошибка загрузки")
+	}
+	if len(received) != 0 {
+		t.Errorf("После ошибки загружены файлы %v", received)
+	}
+
+	// Файл не является архивом gzip
+	notArchive := filepath.Join(t.TempDir(), "plain.txt")
+	os.WriteFile(notArchive, []byte("plain"), 0644)
+	if err := NewHTTPClientWithConfig(config).UploadArchiveContents(context.Background(), notArchive, ts.URL, nil); err == nil {
+		t.Error("Ожидалась ошибка для файла, не являющегося архивом")
+	}
+	if err := NewHTTPClientWithConfig(config).UploadArchiveContents(context.Background(), writeTarGz(t, nil), ts.URL, nil); err == nil {
+		t.Error("Ожидалась ошибка для пустого архива")
+	}
+}
+
+func TestArchiveMemberPath(t *testing.T) {
+	tests := map[string]string{
+		"data/a.txt":   "data/a.txt",
+		"./a.txt":      "a.txt",
+		"a/../b.txt":   "b.txt",
+		`win\path.txt`: "win/path.txt",
+		"../a.txt":     "",
+		"/etc/passwd":  "",
+		".":            "",
+	}
+	for name, expected := range tests {
+		got, ok := archiveMemberPath(name)
+		if ok != (expected != "") || got != expected {
+			t.Errorf("Для %q ожидалось %q, получено %q (%v)", name, expected, got, ok)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Поддерживаемые типы аутентификации
+const (
+	AuthTypeBearer = "bearer"
+	AuthTypeAPIKey = "apikey"
+	AuthTypeBasic  = "basic"
+)
+
+// APIKeyHeader заголовок для передачи API-ключа
+const APIKeyHeader = "X-API-Key"
+
+// AuthConfig параметры аутентификации запросов
+type AuthConfig struct {
+	Type     string // bearer, apikey или basic
+	Token    string // Токен для bearer и apikey
+	Username string // Имя пользователя для basic
+	Password string // Пароль для basic
+}
+
+// authTransport добавляет данные аутентификации в каждый запрос
+type authTransport struct {
+	base http.RoundTripper
+	auth AuthConfig
+}
+
+// RoundTrip реализует http.RoundTripper
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper не должен изменять исходный запрос
+	req = req.Clone(req.Context())
+
+	switch strings.ToLower(t.auth.Type) {
+	case AuthTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+t.auth.Token)
+	case AuthTypeAPIKey:
+		req.Header.Set(APIKeyHeader, t.auth.Token)
+	case AuthTypeBasic:
+		req.SetBasicAuth(t.auth.Username, t.auth.Password)
+	default:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("неизвестный тип аутентификации: %s", t.auth.Type)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// WithAuth возвращает новый клиент, добавляющий аутентификацию в каждый запрос.
+// Новый клиент использует общую конфигурацию и ограничение параллелизма с исходным
+func (c *HTTPClient) WithAuth(auth AuthConfig) *HTTPClient {
+	base := c.client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	httpClient := *c.client
+	httpClient.Transport = &authTransport{base: base, auth: auth}
+
+	return &HTTPClient{
+		client:  &httpClient,
+		config:  c.config,
+		sem:     c.sem,
+		buffers: c.buffers,
+		sized:   c.sized,
+		limiter: c.limiter,
+		initErr: c.initErr,
+		stats:   c.stats,
+
+		progressMu: c.progressMu,
+		events:     c.events,
+		limits:     c.limits,
+		transfers:  c.transfers,
+
+		interceptors: append([]Interceptor(nil), c.interceptors...),
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithAuth_Headers(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "auth.bin")
+	if err := os.WriteFile(testFile, []byte("auth"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		received = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		auth  AuthConfig
+		check func(r *http.Request) bool
+	}{
+		{
+			AuthConfig{Type: AuthTypeBearer, Token: "secret"},
+			func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer secret" },
+		},
+		{
+			AuthConfig{Type: AuthTypeAPIKey, Token: "secret"},
+			func(r *http.Request) bool { return r.Header.Get(APIKeyHeader) == "secret" },
+		},
+		{
+			AuthConfig{Type: AuthTypeBasic, Username: "user", Password: "pass"},
+			func(r *http.Request) bool {
+				user, pass, ok := r.BasicAuth()
+				return ok && user == "user" && pass == "pass"
+			},
+		},
+	}
+
+	base := NewHTTPClient(10 * time.Second)
+	for _, test := range tests {
+		httpClient := base.WithAuth(test.auth)
+		if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+			t.Fatalf("Ошибка загрузки с аутентификацией %s: %v", test.auth.Type, err)
+		}
+		if !test.check(received) {
+			t.Errorf("Неверные заголовки аутентификации для типа %s: %v", test.auth.Type, received.Header)
+		}
+	}
+
+	// Исходный клиент не должен отправлять аутентификацию
+	if err := base.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if received.Header.Get("Authorization") != "" {
+		t.Error("Исходный клиент не должен изменяться методом WithAuth")
+	}
+}
@@ -1,14 +1,18 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"net/http"
+	"log/slog"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"httpBinaryClient/server"
 )
 
 func BenchmarkUploadFile(b *testing.B) {
@@ -17,7 +21,7 @@ func BenchmarkUploadFile(b *testing.B) {
 	defer os.Remove(testFile)
 
 	// Создаем простой HTTP сервер для тестирования
-	server := createTestServer(b)
+	server, storage := createTestServer(b)
 	defer server.Close()
 
 	// Тестируем разные конфигурации
@@ -66,6 +70,9 @@ func BenchmarkUploadFile(b *testing.B) {
 					b.Fatalf("Upload failed: %v", err)
 				}
 			}
+
+			b.StopTimer()
+			checkReceived(b, storage, testFile)
 		})
 	}
 }
@@ -84,7 +91,7 @@ func BenchmarkParallelUploads(b *testing.B) {
 			}
 
 			// Создаем простой HTTP сервер для тестирования
-			server := createTestServer(b)
+			server, storage := createTestServer(b)
 			defer server.Close()
 
 			config := &ClientConfig{
@@ -104,6 +111,111 @@ func BenchmarkParallelUploads(b *testing.B) {
 					b.Fatalf("Parallel upload failed: %v", err)
 				}
 			}
+
+			b.StopTimer()
+			for _, testFile := range testFiles {
+				checkReceived(b, storage, testFile)
+			}
+		})
+	}
+}
+
+// BenchmarkAdaptiveBuffer сравнивает фиксированный буфер 4KB с адаптивным, растущим
+// от 4KB, при загрузке 16MB через loopback
+func BenchmarkAdaptiveBuffer(b *testing.B) {
+	testFile := createTestFile(b, 16*1024*1024)
+	defer os.Remove(testFile)
+
+	server, storage := createTestServer(b)
+	defer server.Close()
+
+	for _, adaptive := range []bool{false, true} {
+		name := "Fixed4KB"
+		if adaptive {
+			name = "Adaptive"
+		}
+		b.Run(name, func(b *testing.B) {
+			client := NewHTTPClientWithConfig(&ClientConfig{
+				BufferSize:     4 * 1024,
+				AdaptiveBuffer: adaptive,
+				MaxConcurrency: 1,
+				Timeout:        30 * time.Minute,
+			})
+			ctx := context.Background()
+
+			b.SetBytes(16 * 1024 * 1024)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := client.UploadFile(ctx, testFile, server.URL+"/upload", nil); err != nil {
+					b.Fatalf("Upload failed: %v", err)
+				}
+			}
+
+			b.StopTimer()
+			checkReceived(b, storage, testFile)
+		})
+	}
+}
+
+// BenchmarkUploadDirectory загружает директорию из 100 файлов размером от 1KB до 1MB
+// и сравнивает UploadDirectory с UploadMultipleFiles для того же списка файлов,
+// показывая накладные расходы обхода директории. Одна итерация занимает заметное время,
+// поэтому удобно запускать с -benchtime=1x
+func BenchmarkUploadDirectory(b *testing.B) {
+	sizes := []int{1024, 4 * 1024, 16 * 1024, 32 * 1024, 64 * 1024, 128 * 1024, 256 * 1024, 512 * 1024, 768 * 1024, 1024 * 1024}
+	dir := b.TempDir()
+	var files []string
+	var totalBytes int64
+	for i := 0; i < 10; i++ {
+		for _, size := range sizes {
+			path := filepath.Join(dir, fmt.Sprintf("file_%02d_%d.bin", i, size))
+			if err := os.Rename(createTestFile(b, size), path); err != nil {
+				b.Fatalf("Failed to move test file: %v", err)
+			}
+			files = append(files, path)
+			totalBytes += int64(size)
+		}
+	}
+
+	server, storage := createTestServer(b)
+	defer server.Close()
+
+	uploads := []struct {
+		name   string
+		upload func(ctx context.Context, client *HTTPClient) error
+	}{
+		{"UploadDirectory", func(ctx context.Context, client *HTTPClient) error {
+			return client.UploadDirectory(ctx, dir, server.URL+"/upload", nil)
+		}},
+		{"UploadMultipleFiles", func(ctx context.Context, client *HTTPClient) error {
+			return client.UploadMultipleFiles(ctx, files, server.URL+"/upload", nil)
+		}},
+	}
+
+	for _, u := range uploads {
+		b.Run(u.name, func(b *testing.B) {
+			client := NewHTTPClientWithConfig(&ClientConfig{
+				BufferSize:     64 * 1024,
+				MaxConcurrency: 8,
+				Timeout:        30 * time.Minute,
+			})
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := u.upload(ctx, client); err != nil {
+					b.Fatalf("Upload failed: %v", err)
+				}
+			}
+			b.StopTimer()
+
+			seconds := b.Elapsed().Seconds() / float64(b.N)
+			b.ReportMetric(float64(totalBytes), "bytes/op")
+			b.ReportMetric(float64(len(files))/seconds, "files/s")
+			b.ReportMetric(float64(totalBytes)/(1024*1024)/seconds, "MB/s")
+			for _, file := range files {
+				checkReceived(b, storage, file)
+			}
 		})
 	}
 }
@@ -130,22 +242,63 @@ func createTestFile(b *testing.B, size int) string {
 	return file.Name()
 }
 
-// createTestServer создает простой HTTP сервер для тестирования
-func createTestServer(b *testing.B) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "POST" {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+// createTestServer запускает сервер загрузки с хранилищем в памяти,
+// чтобы бенчмарки проверяли принятые байты
+func createTestServer(b *testing.B) (*httptest.Server, *server.MemoryStorageBackend) {
+	storage := server.NewMemoryStorageBackend()
+	srv := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend: storage,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	return httptest.NewServer(srv.Handler()), storage
+}
 
-		// Просто читаем и отбрасываем данные
-		_, err := io.Copy(io.Discard, r.Body)
-		if err != nil {
-			http.Error(w, "Failed to read body", http.StatusInternalServerError)
-			return
-		}
+// checkReceived проверяет, что сервер получил файл целиком
+func checkReceived(b *testing.B, storage *server.MemoryStorageBackend, filePath string) {
+	b.Helper()
+
+	expected, err := os.ReadFile(filePath)
+	if err != nil {
+		b.Fatalf("Failed to read test file: %v", err)
+	}
+	received, ok := storage.Contents(filepath.Base(filePath))
+	if !ok || !bytes.Equal(received, expected) {
+		b.Fatalf("Server received %d bytes of %s, expected %d", len(received), filePath, len(expected))
+	}
+}
+
+// BenchmarkBufferAllocation сравнивает выделение буфера на каждую загрузку
+// с получением его из пула при 8 параллельных загрузках
+func BenchmarkBufferAllocation(b *testing.B) {
+	client := NewHTTPClientWithConfig(DefaultConfig())
+
+	b.Run("Make", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buffer := make([]byte, client.config.BufferSize)
+				bufferSink(buffer)
+			}
+		})
+	})
+
+	b.Run("Pool", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buffer := client.getBuffer()
+				bufferSink(*buffer)
+				client.putBuffer(buffer)
+			}
+		})
+	})
+}
 
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	}))
+// bufferSink не дает компилятору убрать выделение буфера
+//
+//go:noinline
+func bufferSink(buffer []byte) {
+	buffer[0] = 1
 }
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// newBufferPool создает пул буферов чтения файла размером size
+func newBufferPool(size int) *sync.Pool {
+	return &sync.Pool{
+		New: func() any {
+			buffer := make([]byte, size)
+			return &buffer
+		},
+	}
+}
+
+// getBuffer возвращает буфер размером ClientConfig.BufferSize из пула.
+// Буфер нужно вернуть через putBuffer после использования
+func (c *HTTPClient) getBuffer() *[]byte {
+	size := c.config.BufferSize
+	if c.buffers == nil {
+		buffer := make([]byte, size)
+		return &buffer
+	}
+
+	buffer := c.buffers.Get().(*[]byte)
+	// Пул может вернуть буфер другого размера, если конфигурация изменилась
+	if cap(*buffer) < size {
+		*buffer = make([]byte, size)
+	}
+	*buffer = (*buffer)[:size]
+	return buffer
+}
+
+// putBuffer возвращает буфер в пул
+func (c *HTTPClient) putBuffer(buffer *[]byte) {
+	if c.buffers != nil {
+		c.buffers.Put(buffer)
+	}
+}
+
+// Параметры адаптивного буфера (ClientConfig.AdaptiveBuffer)
+const (
+	minAdaptiveBufferSize   = 4 * 1024
+	defaultMaxBufferSize    = 8 * 1024 * 1024
+	adaptiveGrowThreshold   = 0.1  // Байт в наносекунду (100 MB/s), выше которых буфер удваивается
+	adaptiveShrinkThreshold = 0.01 // Байт в наносекунду (10 MB/s), ниже которых буфер сокращается
+	adaptiveSlowIntervals   = 2    // Число медленных замеров подряд до сокращения буфера
+)
+
+// adaptiveBuffer подбирает размер буфера чтения по наблюдаемой скорости передачи:
+// на быстром канале крупный буфер уменьшает число системных вызовов и записей в pipe,
+// на медленном малый буфер экономит память
+type adaptiveBuffer struct {
+	min, max int
+	size     int
+	slow     int // Медленные замеры подряд
+}
+
+// newAdaptiveBuffer создает адаптивный буфер начального размера initial (не меньше 4KB),
+// растущий до max (0 — 8MB)
+func newAdaptiveBuffer(initial, max int) *adaptiveBuffer {
+	if initial < minAdaptiveBufferSize {
+		initial = minAdaptiveBufferSize
+	}
+	if max <= 0 {
+		max = defaultMaxBufferSize
+	}
+	if max < initial {
+		max = initial
+	}
+	return &adaptiveBuffer{min: initial, max: max, size: initial}
+}
+
+// observe учитывает передачу n байт за elapsed и возвращает размер буфера для следующего чтения.
+// Буфер растет только после полностью заполненного чтения: неполное чтение означает,
+// что источник не успевает и больший буфер не поможет
+func (a *adaptiveBuffer) observe(n int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		elapsed = time.Nanosecond
+	}
+	rate := float64(n) / float64(elapsed.Nanoseconds())
+
+	switch {
+	case rate >= adaptiveGrowThreshold:
+		a.slow = 0
+		if n == a.size && a.size < a.max {
+			a.size *= 2
+			if a.size > a.max {
+				a.size = a.max
+			}
+		}
+	case rate < adaptiveShrinkThreshold:
+		a.slow++
+		if a.slow >= adaptiveSlowIntervals {
+			a.size = a.min
+			a.slow = 0
+		}
+	default:
+		a.slow = 0
+	}
+	return a.size
+}
+
+// getBufferSize возвращает буфер размером size. Буферы размера BufferSize берутся
+// из основного пула, остальные — из пулов по размерам, заполняемых адаптивным буфером
+func (c *HTTPClient) getBufferSize(size int) *[]byte {
+	if size == c.config.BufferSize {
+		return c.getBuffer()
+	}
+	if c.sized == nil {
+		buffer := make([]byte, size)
+		return &buffer
+	}
+	pool, _ := c.sized.LoadOrStore(size, newBufferPool(size))
+	return pool.(*sync.Pool).Get().(*[]byte)
+}
+
+// putBufferSize возвращает буфер, полученный через getBufferSize, в пул его размера
+func (c *HTTPClient) putBufferSize(buffer *[]byte) {
+	size := len(*buffer)
+	if size == c.config.BufferSize {
+		c.putBuffer(buffer)
+		return
+	}
+	if c.sized == nil {
+		return
+	}
+	if pool, ok := c.sized.Load(size); ok {
+		pool.(*sync.Pool).Put(buffer)
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBuffer_Size(t *testing.T) {
+	config := DefaultConfig()
+	config.BufferSize = 1024
+	httpClient := NewHTTPClientWithConfig(config)
+
+	buffer := httpClient.getBuffer()
+	if len(*buffer) != 1024 {
+		t.Fatalf("Неверный размер буфера: %d", len(*buffer))
+	}
+	httpClient.putBuffer(buffer)
+
+	// Буфер из пула должен соответствовать текущему размеру из конфигурации
+	config.BufferSize = 4096
+	buffer = httpClient.getBuffer()
+	if len(*buffer) != 4096 {
+		t.Errorf("Буфер не пересоздан под новый размер: %d", len(*buffer))
+	}
+
+	config.BufferSize = 512
+	httpClient.putBuffer(buffer)
+	buffer = httpClient.getBuffer()
+	if len(*buffer) != 512 {
+		t.Errorf("Буфер не усечен до размера из конфигурации: %d", len(*buffer))
+	}
+}
+
+func TestAdaptiveBuffer_Observe(t *testing.T) {
+	buffer := newAdaptiveBuffer(1024, 32*1024)
+	if buffer.size != minAdaptiveBufferSize {
+		t.Fatalf("Начальный размер должен быть не меньше 4KB: %d", buffer.size)
+	}
+
+	fast := func(n int) time.Duration { return time.Duration(float64(n)/adaptiveGrowThreshold) / 2 }
+	slow := func(n int) time.Duration { return time.Duration(float64(n)/adaptiveShrinkThreshold) * 2 }
+
+	// Быстрые полные чтения удваивают буфер до максимума
+	for _, want := range []int{8 * 1024, 16 * 1024, 32 * 1024, 32 * 1024} {
+		if got := buffer.observe(buffer.size, fast(buffer.size)); got != want {
+			t.Fatalf("Ожидался размер %d, получено %d", want, got)
+		}
+	}
+
+	// Неполное чтение не увеличивает буфер, а один медленный замер не уменьшает
+	buffer = newAdaptiveBuffer(4096, 0)
+	if got := buffer.observe(100, fast(100)); got != 4096 {
+		t.Errorf("Неполное чтение не должно увеличивать буфер: %d", got)
+	}
+	buffer.observe(4096, fast(4096))
+	if got := buffer.observe(8192, slow(8192)); got != 8192 {
+		t.Errorf("Один медленный замер не должен уменьшать буфер: %d", got)
+	}
+	if got := buffer.observe(8192, slow(8192)); got != 4096 {
+		t.Errorf("Два медленных замера подряд должны вернуть начальный размер: %d", got)
+	}
+	if buffer.max != defaultMaxBufferSize {
+		t.Errorf("Максимальный размер по умолчанию: %d", buffer.max)
+	}
+}
+
+func TestGetBufferSize_Pools(t *testing.T) {
+	config := DefaultConfig()
+	config.BufferSize = 1024
+	httpClient := NewHTTPClientWithConfig(config)
+
+	buffer := httpClient.getBufferSize(8192)
+	if len(*buffer) != 8192 {
+		t.Fatalf("Неверный размер буфера: %d", len(*buffer))
+	}
+	httpClient.putBufferSize(buffer)
+	if _, ok := httpClient.sized.Load(8192); !ok {
+		t.Error("Буфер увеличенного размера не возвращен в свой пул")
+	}
+	if buffer := httpClient.getBufferSize(1024); len(*buffer) != 1024 {
+		t.Errorf("Буфер основного размера: %d", len(*buffer))
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ContentCRC32CHeader заголовок с контрольной суммой CRC32C содержимого файла
+// (4 байта big-endian в base64), которую сервер сверяет с принятыми данными
+const ContentCRC32CHeader = "X-Content-CRC32C"
+
+// crc32cTable таблица полинома Кастаньоли
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// fileCRC32C вычисляет CRC32C содержимого file в формате заголовка X-Content-CRC32C
+// и возвращает позицию чтения в начало файла
+func fileCRC32C(file io.ReadSeeker) (string, error) {
+	hash := crc32.New(crc32cTable)
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], hash.Sum32())
+	return base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// contentSHA256 вычисляет SHA-256 содержимого file в hex и возвращает позицию чтения в начало файла
+func contentSHA256(file io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// unchangedOnServer проверяет запросом GET /files/{filename}/checksum, что на сервере уже
+// есть файл задания с SHA-256 sum. Сервер сверяет If-None-Match, только приняв тело запроса,
+// поэтому неизмененный файл проверяется заранее и не передается вовсе. Имя на сервере
+// вычисляется так же, как при загрузке без шаблона пути; при любой ошибке проверки,
+// например если сервер сохраняет файлы по UploadPathTemplate, возвращается false,
+// и файл загружается с If-None-Match
+func (c *HTTPClient) unchangedOnServer(ctx context.Context, serverURL string, task uploadTask, sum string) bool {
+	name := task.headers.Get(RelativePathHeader)
+	if name == "" {
+		name = task.formFileName()
+	}
+	if task.remotePath != "" {
+		name = path.Join(filepath.ToSlash(task.remotePath), name)
+	}
+
+	remote, err := c.FileChecksum(ctx, serverURL, name, "sha256")
+	if err != nil {
+		c.logger().Debug("Не удалось проверить файл на сервере до загрузки", "file", task.filePath, "error", err)
+		return false
+	}
+	return strings.EqualFold(remote, sum)
+}
+
+// ErrChecksumMismatch возвращается, если контрольная сумма файла, вычисленная сервером
+// при приеме, не совпала с локальной (ClientConfig.VerifyServerChecksum)
+var ErrChecksumMismatch = errors.New("контрольная сумма файла на сервере не совпадает с локальной")
+
+// serverChecksumAlgos алгоритмы сумм, которые сервер возвращает в ответе на загрузку
+var serverChecksumAlgos = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha256": sha256.New,
+}
+
+// uploadResult поля JSON-ответа сервера на загрузку, которые проверяет клиент
+type uploadResult struct {
+	Checksum    string `json:"checksum"`
+	Algorithm   string `json:"checksum_algorithm"`
+	ContentType string `json:"content_type"`
+}
+
+// verifyUploadResponse проверяет JSON-ответ сервера на загрузку: контрольную сумму
+// (VerifyServerChecksum) и тип содержимого (UploadOptions.ExpectedContentType).
+// Ответ без JSON не содержит этих полей, и проверки пропускаются
+func (c *HTTPClient) verifyUploadResponse(resp *http.Response, task uploadTask) *UploadError {
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil
+	}
+	var response uploadResult
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil
+	}
+
+	if task.expectedContentType != "" {
+		if err := checkContentType(response.ContentType, task.expectedContentType); err != nil {
+			return err
+		}
+	}
+	if task.verifyChecksum {
+		return c.verifyServerChecksum(response, task.filePath)
+	}
+	return nil
+}
+
+// verifyServerChecksum сверяет контрольную сумму из ответа сервера с суммой файла filePath.
+// Если сервер сумму не вернул или использовал неизвестный алгоритм, проверка пропускается
+func (c *HTTPClient) verifyServerChecksum(response uploadResult, filePath string) *UploadError {
+	if response.Checksum == "" {
+		return nil
+	}
+	newHash, ok := serverChecksumAlgos[response.Algorithm]
+	if !ok {
+		c.logger().Warn("Неизвестный алгоритм контрольной суммы сервера, проверка пропущена",
+			"file", filePath, "algorithm", response.Algorithm)
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return newUploadError("ошибка открытия файла", err)
+	}
+	defer file.Close()
+	hasher := newHash()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return newUploadError("ошибка вычисления контрольной суммы", fmt.Errorf("ошибка чтения файла: %w", err))
+	}
+
+	if local := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(local, response.Checksum) {
+		return newUploadError("ошибка проверки файла",
+			fmt.Errorf("%w: %s сервера %s, локальная %s", ErrChecksumMismatch, response.Algorithm, response.Checksum, local))
+	}
+	return nil
+}
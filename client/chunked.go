@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChunkedUpload загружает большой файл частями по chunkSize байт параллельно
+// (не больше MaxConcurrency частей одновременно). Каждая часть отправляется
+// на POST /upload/chunk?session={id}&index={i}&total={n} с повторными попытками,
+// после чего POST /upload/finalize?session={id} собирает файл на сервере.
+// serverURL — URL загрузки; путь заменяется на эндпоинты частей
+func (c *HTTPClient) ChunkedUpload(ctx context.Context, filePath, serverURL string, chunkSize int64, progressCallback ProgressCallback) error {
+	if chunkSize <= 0 {
+		return fmt.Errorf("размер части должен быть положительным, получено %d", chunkSize)
+	}
+	if err := c.validateUploadFile(filePath); err != nil {
+		return err
+	}
+	if err := c.checkServerLimits(filePath, serverURL); err != nil {
+		return err
+	}
+	if len(c.config.EncryptionKey) > 0 || c.config.CompressUpload {
+		return fmt.Errorf("загрузка по частям не поддерживает сжатие и шифрование")
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("ошибка получения информации о файле: %w", err)
+	}
+	fileSize := fileInfo.Size()
+	total := int((fileSize + chunkSize - 1) / chunkSize)
+
+	session, err := newChunkSessionID()
+	if err != nil {
+		return fmt.Errorf("ошибка создания сессии: %w", err)
+	}
+
+	logger := c.logger().With("file", filePath, "url", serverURL, "chunk_session", session)
+	logger.Info("Начало загрузки по частям", "chunks", total, "chunk_size", chunkSize)
+	startTime := time.Now()
+
+	// Callback прогресса вызывается из нескольких горутин, поэтому сериализуется
+	progressCallback, finish := c.trackProgress(filePath, c.serializeProgress(progressCallback))
+	progressCallback = ThrottleProgress(c.config.ProgressInterval, progressCallback)
+	var sent atomic.Int64
+	onProgress := func(n int64) {
+		bytesSent := sent.Add(n)
+		if progressCallback != nil {
+			progressCallback(bytesSent, fileSize, float64(bytesSent)/float64(fileSize)*100)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+chunks:
+	for i := 0; i < total; i++ {
+		// Слот семафора ограничивает количество одновременно отправляемых частей
+		if c.acquireSlot(ctx) != nil {
+			break chunks
+		}
+
+		offset := int64(i) * chunkSize
+		size := chunkSize
+		if offset+size > fileSize {
+			size = fileSize - offset
+		}
+
+		wg.Add(1)
+		go func(index int, section *io.SectionReader) {
+			defer wg.Done()
+			defer func() { <-c.sem }()
+
+			if err := c.uploadChunk(ctx, serverURL, session, index, total, section, onProgress); err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("ошибка загрузки части %d: %w", index, err)
+					cancel()
+				})
+			}
+		}(i, io.NewSectionReader(file, offset, size))
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		logger.Error("Ошибка загрузки", "error", firstErr)
+		finish(firstErr)
+		return firstErr
+	}
+
+	if err := c.finalizeChunks(ctx, serverURL, session, filepath.Base(filePath)); err != nil {
+		logger.Error("Ошибка сборки файла", "error", err)
+		finish(err)
+		return err
+	}
+
+	logger.Info("Загрузка завершена", "duration", time.Since(startTime).Round(time.Millisecond))
+	finish(nil)
+	return nil
+}
+
+// uploadChunk отправляет одну часть с повторными попытками
+func (c *HTTPClient) uploadChunk(ctx context.Context, serverURL, session string, index, total int, section *io.SectionReader, onProgress func(int64)) error {
+	chunkURL, err := endpointURL(serverURL, "/upload/chunk")
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("session", session)
+	query.Set("index", strconv.Itoa(index))
+	query.Set("total", strconv.Itoa(total))
+	chunkURL.RawQuery = query.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.retryDelay(lastErr)):
+			}
+		}
+
+		// Отправленные в неудачной попытке байты вычитаются из прогресса
+		body := &chunkProgressReader{ctx: ctx, client: c, r: io.NewSectionReader(section, 0, section.Size()), onProgress: onProgress}
+		lastErr = c.postChunk(ctx, chunkURL.String(), body, section.Size())
+		if lastErr == nil {
+			return nil
+		}
+		onProgress(-body.n)
+		if !c.shouldRetry(attempt+1, lastErr) || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// postChunk выполняет запрос с содержимым части
+func (c *HTTPClient) postChunk(ctx context.Context, chunkURL string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, chunkURL, body)
+	if err != nil {
+		return newUploadError("ошибка создания HTTP запроса", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.doIntercepted(req)
+	if err != nil {
+		return newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// finalizeChunks просит сервер собрать файл из принятых частей
+func (c *HTTPClient) finalizeChunks(ctx context.Context, serverURL, session, filename string) error {
+	finalizeURL, err := endpointURL(serverURL, "/upload/finalize")
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("session", session)
+	query.Set("filename", filename)
+	finalizeURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, finalizeURL.String(), nil)
+	if err != nil {
+		return newUploadError("ошибка создания HTTP запроса", err)
+	}
+	c.setMetadataHeaders(req)
+
+	resp, err := c.doIntercepted(req)
+	if err != nil {
+		return newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// newChunkSessionID возвращает случайный идентификатор сессии загрузки по частям
+func newChunkSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// chunkProgressReader сообщает о прочитанных байтах части и соблюдает
+// ограничение скорости клиента
+type chunkProgressReader struct {
+	ctx        context.Context
+	client     *HTTPClient
+	r          io.Reader
+	n          int64
+	onProgress func(int64)
+}
+
+func (c *chunkProgressReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.n += int64(n)
+		c.onProgress(int64(n))
+		if throttleErr := c.client.throttle(c.ctx, int64(n)); throttleErr != nil {
+			return n, throttleErr
+		}
+	}
+	return n, err
+}
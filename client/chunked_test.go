@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"httpBinaryClient/server"
+)
+
+func TestChunkedUpload(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	ts := httptest.NewServer(server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend:  backend,
+		ChunkDir: t.TempDir(),
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler())
+	defer ts.Close()
+
+	// Размер не кратен размеру части: последняя часть короче
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	content = append(content, "tail"...)
+	testFile := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(testFile, content, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.MaxConcurrency = 3
+	config.ProgressInterval = 0
+	httpClient := NewHTTPClientWithConfig(config)
+
+	var mu sync.Mutex
+	var lastProgress int64
+	err := httpClient.ChunkedUpload(context.Background(), testFile, ts.URL+"/upload", 1024, func(bytesTransferred, totalBytes int64, percentage float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		lastProgress = bytesTransferred
+	})
+	if err != nil {
+		t.Fatalf("Ошибка загрузки по частям: %v", err)
+	}
+
+	received, ok := backend.Contents("large.bin")
+	if !ok || !bytes.Equal(received, content) {
+		t.Errorf("Собранный файл не совпадает с исходным: получено %d байт из %d", len(received), len(content))
+	}
+	if lastProgress != int64(len(content)) {
+		t.Errorf("Прогресс %d не совпадает с размером файла %d", lastProgress, len(content))
+	}
+}
+
+func TestChunkedUpload_Interceptors(t *testing.T) {
+	handler := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend:  server.NewMemoryStorageBackend(),
+		ChunkDir: t.TempDir(),
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	// Запросы без заголовка перехватчика отклоняются
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" {
+			http.Error(w, "нет заголовка X-Tenant", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(testFile, bytes.Repeat([]byte("x"), 2500), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	recorder := &memoryRecorder{}
+	httpClient := NewHTTPClientWithConfig(DefaultConfig())
+	httpClient.AddInterceptor(&HeaderInjectInterceptor{Headers: map[string]string{"X-Tenant": "acme"}})
+	httpClient.AddInterceptor(NewMetricsInterceptor(recorder))
+	if err := httpClient.ChunkedUpload(context.Background(), testFile, ts.URL+"/upload", 1024, nil); err != nil {
+		t.Fatalf("Ошибка загрузки по частям: %v", err)
+	}
+
+	// Три части и сборка файла
+	if len(recorder.statuses) != 4 {
+		t.Errorf("Ожидалось 4 запроса в метриках, получено %v", recorder.statuses)
+	}
+}
+
+func TestChunkedUpload_Errors(t *testing.T) {
+	ts := httptest.NewServer(server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend:          server.NewMemoryStorageBackend(),
+		ChunkDir:         t.TempDir(),
+		MaxFileSizeBytes: 100,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler())
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(testFile, make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	httpClient := NewHTTPClientWithConfig(DefaultConfig())
+
+	if err := httpClient.ChunkedUpload(context.Background(), testFile, ts.URL, 0, nil); err == nil {
+		t.Error("Ожидалась ошибка для нулевого размера части")
+	}
+
+	// Часть больше лимита сервера: 413 не повторяется
+	err := httpClient.ChunkedUpload(context.Background(), testFile, ts.URL, 500, nil)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка для части больше лимита")
+	}
+	if !isPermanentError(err) {
+		t.Errorf("Ожидалась постоянная ошибка, получено %v", err)
+	}
+}
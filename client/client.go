@@ -1,20 +1,33 @@
 package client
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"log/slog"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ProgressCallback функция для отслеживания прогресса передачи
+// ProgressCallback функция для отслеживания прогресса передачи. Клиент не вызывает
+// callback одновременно из разных загрузок: вызовы всех загрузок клиента сериализуются,
+// поэтому callback, общий для параллельных загрузок, может менять общие данные без
+// блокировок. Долгий callback задерживает остальные загрузки
 type ProgressCallback func(bytesTransferred, totalBytes int64, percentage float64)
 
 // ClientConfig конфигурация для оптимизации клиента
@@ -24,8 +37,139 @@ type ClientConfig struct {
 	Timeout        time.Duration // Таймаут для HTTP-клиента
 	RetryAttempts  int           // Количество попыток при ошибке
 	RetryDelay     time.Duration // Задержка между попытками
+	MaxRetryDelay  time.Duration // Наибольшее ожидание по заголовку Retry-After ответов 429 и 503 (0 — без ограничения)
+
+	// MaxConnsPerHost наибольшее число соединений с одним сервером, включая занятые запросами
+	// (0 — MaxConcurrency, а с FallbackURLs — MaxConcurrency/(1+len(FallbackURLs)), но не меньше 1).
+	// Без резервных серверов должно совпадать с MaxConcurrency: меньшее значение заставляет
+	// параллельные загрузки ждать соединения, а при большем лишние соединения не используются.
+	// MaxIdleConnsPerHost число простаивающих соединений с сервером, сохраняемых для
+	// следующих запросов (0 — MaxConcurrency/2). MaxIdleConns ограничивает простаивающие
+	// соединения со всеми серверами (0 — 100, отрицательное значение отключает keep-alive:
+	// каждый запрос открывает новое соединение). IdleConnTimeout время, через которое
+	// простаивающее соединение закрывается (0 — 90s)
+	MaxConnsPerHost     int
+	MaxIdleConnsPerHost int
+	MaxIdleConns        int
+	IdleConnTimeout     time.Duration
+
+	// RetryCondition решает, повторять ли загрузку после неудачной попытки с номером
+	// attempt (начиная с 1). Ограничение RetryAttempts действует всегда.
+	// nil — повторяются все ошибки, кроме ответов 4xx (см. isPermanentError)
+	RetryCondition func(attempt int, err error) bool
+
+	// FailFast отменяет остальные загрузки пакета при первой ошибке. Если false,
+	// каждая загрузка выполняется до конца и возвращаются результаты по всем файлам
+	FailFast bool
+
+	// SkipDuplicates пропускает в пакетной загрузке файлы, уже вошедшие в пакет под другим
+	// путем (например, через символическую ссылку). Файлы сравниваются по реальному пути,
+	// размеру и времени изменения; у пропущенного файла в UploadResult выставлен Skipped
+	SkipDuplicates bool
+
+	// Таймауты отдельных этапов запроса: в отличие от Timeout, ограничивающего всю загрузку,
+	// они прерывают зависшее соединение, не дожидаясь окончания общего таймаута (0 — без ограничения)
+	ConnectTimeout        time.Duration // Установка TCP-соединения
+	TLSHandshakeTimeout   time.Duration // TLS-рукопожатие
+	ResponseHeaderTimeout time.Duration // Ожидание заголовков ответа после отправки всего тела запроса
+
+	// TCP keep-alive не дает NAT и межсетевым экранам закрыть соединение, простаивающее
+	// во время долгой загрузки по медленному каналу. TCPKeepAlive время простоя до первой
+	// проверки (отрицательное значение отключает keep-alive), TCPKeepAliveInterval интервал
+	// между проверками (поддерживается на Linux, 0 — интервал системы)
+	TCPKeepAlive         time.Duration
+	TCPKeepAliveInterval time.Duration
+
+	// ProgressStatePath файл, в который UploadFileWithProgress после каждого обновления прогресса
+	// записывает ProgressState. После перезапуска клиента UploadFile того же неизмененного файла
+	// сообщает о прерванной загрузке, а время ее начала сохраняется; файл удаляется после успешной
+	// загрузки. Сервер принимает файл целиком, поэтому передача все равно начинается сначала
+	ProgressStatePath string
+
+	// FallbackURLs резервные адреса загрузки. Если основной сервер недоступен или ответил 5xx,
+	// в той же попытке файл по очереди отправляется на резервные адреса; ответ 4xx считается
+	// окончательным и на резервные серверы не отправляется. Действует для UploadFile и пакетной загрузки
+	FallbackURLs []string
+
+	// SendFileSizeHeader отправляет размер файла в заголовке X-File-Size: в multipart-запросе
+	// Content-Length неизвестен заранее, а сервер использует заголовок для прогресса и проверки
+	// размера. Не действует для stdin и при шифровании
+	SendFileSizeHeader bool
+
+	// VerifyServerChecksum сверяет контрольную сумму, которую сервер с ComputeHashOnUpload
+	// возвращает в ответе на загрузку, с суммой локального файла. При расхождении попытка
+	// завершается ошибкой ErrChecksumMismatch и повторяется. Не действует для stdin и при шифровании
+	VerifyServerChecksum bool
+
+	// ConditionalUpload сначала запрашивает GET /files/{filename}/checksum и не отправляет файл,
+	// если на сервере уже есть файл с тем же именем и SHA-256. Если проверить не удалось, сумма
+	// передается в заголовке If-None-Match: при совпадении сервер отвечает 304 Not Modified
+	// и не перезаписывает файл. Оба случая считаются успешной загрузкой, а в UploadResult
+	// выставляется Skipped. Позволяет повторно запускать пакетную загрузку того же набора
+	// файлов. Не действует при шифровании
+	ConditionalUpload bool
+
+	// VerifyChecksum отправляет CRC32C файла в заголовке X-Content-CRC32C; сервер с включенной
+	// проверкой отклоняет поврежденный при передаче файл статусом 422. Не действует для stdin
+	VerifyChecksum bool
+
+	// AdaptiveBuffer подбирает размер буфера чтения по скорости передачи: начиная с BufferSize
+	// (не меньше 4KB), буфер удваивается на быстром канале до MaxBufferSize (0 — 8MB)
+	// и возвращается к начальному размеру, если скорость упала
+	AdaptiveBuffer bool
+	MaxBufferSize  int
+
+	CompressUpload   bool // Сжимать содержимое файла gzip перед отправкой
+	CompressionLevel int  // Уровень сжатия gzip (0 — уровень по умолчанию)
+
+	StabilizeDuration time.Duration // Время без изменений файла перед загрузкой в режиме наблюдения
+	ProgressInterval  time.Duration // Минимальный интервал между вызовами callback прогресса (0 — без ограничения)
+	ProgressFormat    string        // Формат прогресса UploadFileWithProgress: bar (по умолчанию), human или json
+	ProgressOutput    io.Writer     // Куда UploadFileWithProgress пишет прогресс в форматах bar и json (nil — os.Stdout)
+	ETAWarmupPeriod   time.Duration // Время от начала передачи, в течение которого UploadFileWithProgress не оценивает оставшееся время
+	EMAAlpha          float64       // Коэффициент сглаживания скорости для оценки оставшегося времени, от 0 до 1 (0 — 0.1)
+	LimitCacheTTL     time.Duration // Время хранения ограничений из FetchServerLimits для проверки размера до загрузки (0 — не кэшировать)
+	TracingEnabled    bool          // Создавать span OpenTelemetry для каждой попытки загрузки
+	ProxyURL          string        // URL прокси: http://, https:// или socks5:// (учетные данные можно указать в URL)
+	SocketPath        string        // Путь Unix-сокета сервера; если задан, соединения идут через него, а хост URL не используется
+	Transport         string        // Транспорт клиента из grpc.NewClient: http (по умолчанию) или grpc
+	EncryptionKey     []byte        // Ключ AES-256-GCM (32 байта) для шифрования содержимого перед отправкой
+
+	MaxUploadBytesPerSec int64 // Ограничение суммарной скорости отправки всех загрузок клиента (0 — без ограничения)
+
+	CustomHeaders  map[string]string // Заголовки, добавляемые в каждый запрос (кроме Content-Type)
+	PathHeaderName string            // Заголовок для UploadOptions.RemotePath; должен совпадать с настройкой сервера (пусто — X-Upload-Path)
+	Metadata       map[string]string // Метаданные загрузки, передаваемые в заголовках X-Meta-{Key}
+
+	// CorrelationIDHeader заголовок с идентификатором корреляции (пусто — X-Correlation-ID).
+	// Если идентификатор не задан в CustomHeaders, клиент создает UUID для каждой загрузки
+	// и передает его во всех ее попытках; сервер записывает его в свой лог загрузки
+	CorrelationIDHeader string
+
+	TLSCertFile string // Сертификат клиента в формате PEM для mTLS
+	TLSKeyFile  string // Закрытый ключ сертификата клиента
+
+	// TLSCACertFile сертификат CA в формате PEM, которым проверяется сертификат сервера
+	// вместо системных корневых сертификатов, например для самоподписанного сертификата
+	TLSCACertFile string
+
+	// TLSInsecureSkipVerify отключает проверку сертификата сервера. Только для разработки:
+	// соединение становится уязвимым для перехвата, поэтому клиент предупреждает об этом в логе
+	TLSInsecureSkipVerify bool
+
+	DryRun                        bool  // Только проверить файлы и оценить время передачи, не отправляя запросы
+	EstimatedBandwidthBytesPerSec int64 // Пропускная способность для оценки в режиме DryRun (0 — 10 MB/s)
+
+	Logger *slog.Logger // Логгер клиента (nil — slog.Default())
+
+	// Metrics получает длительность, размер и ошибку каждой попытки загрузки
+	// (nil — метрики не собираются), например PrometheusMetricsSink
+	Metrics MetricsSink
 }
 
+// defaultStabilizeDuration время стабилизации файла по умолчанию
+const defaultStabilizeDuration = 500 * time.Millisecond
+
 // DefaultConfig возвращает конфигурацию по умолчанию
 func DefaultConfig() *ClientConfig {
 	return &ClientConfig{
@@ -34,14 +178,139 @@ func DefaultConfig() *ClientConfig {
 		Timeout:        30 * time.Minute,
 		RetryAttempts:  3,
 		RetryDelay:     time.Second,
+		MaxRetryDelay:  time.Minute,
+		FailFast:       true,
+
+		ConnectTimeout:        10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		TCPKeepAlive:          30 * time.Second,
+		TCPKeepAliveInterval:  10 * time.Second,
+		MaxIdleConns:          defaultMaxIdleConns,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+
+		MaxBufferSize: defaultMaxBufferSize,
+
+		StabilizeDuration: defaultStabilizeDuration,
+		ProgressInterval:  defaultProgressInterval,
+		ETAWarmupPeriod:   defaultETAWarmupPeriod,
+		EMAAlpha:          defaultEMAAlpha,
+		LimitCacheTTL:     defaultLimitCacheTTL,
+	}
+}
+
+// RelativePathHeader заголовок с относительным путем файла для сохранения структуры директорий
+const RelativePathHeader = "X-Relative-Path"
+
+// DefaultPathHeaderName заголовок с поддиректорией загрузки на сервере (UploadOptions.RemotePath),
+// если ClientConfig.PathHeaderName не задан
+const DefaultPathHeaderName = "X-Upload-Path"
+
+// FileSizeHeader заголовок с размером файла в байтах (ClientConfig.SendFileSizeHeader)
+const FileSizeHeader = "X-File-Size"
+
+// SessionIDHeader заголовок ответа сервера с идентификатором сессии загрузки
+const SessionIDHeader = "X-Upload-Session-ID"
+
+// FilterConfig шаблоны для отбора файлов при загрузке директории (синтаксис filepath.Match).
+// Шаблоны сопоставляются с именем файла; исключения имеют приоритет над включениями
+type FilterConfig struct {
+	IncludePatterns []string // Если пусто, включаются все файлы
+	ExcludePatterns []string
+}
+
+// validate проверяет корректность всех шаблонов
+func (f FilterConfig) validate() error {
+	for _, pattern := range append(append([]string{}, f.IncludePatterns...), f.ExcludePatterns...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("некорректный шаблон %q: %w", pattern, err)
+		}
 	}
+	return nil
+}
+
+// matches определяет, подходит ли файл под фильтр
+func (f FilterConfig) matches(name string) bool {
+	for _, pattern := range f.ExcludePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return false
+		}
+	}
+
+	if len(f.IncludePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range f.IncludePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadTask описывает один файл в пакетной загрузке
+type uploadTask struct {
+	filePath   string
+	remoteName string            // Имя файла на сервере (по умолчанию имя локального файла)
+	headers    http.Header       // Дополнительные заголовки запроса
+	fields     map[string]string // Поля формы, отправляемые перед файлом
+
+	verifyChecksum      bool   // Сверить сумму из ответа сервера с локальной (VerifyServerChecksum)
+	expectedContentType string // Тип содержимого, который должен определить сервер (UploadOptions)
+	remotePath          string // Поддиректория загрузки на сервере (UploadOptions)
+
+	// skipped выставляется, если сервер ответил 304 Not Modified (ConditionalUpload).
+	// Указатель разделяется копиями задания, поэтому результат виден вызывающему
+	skipped *bool
+}
+
+// formFileName возвращает имя файла для поля формы
+func (t uploadTask) formFileName() string {
+	if t.remoteName != "" {
+		return t.remoteName
+	}
+	return filepath.Base(t.filePath)
+}
+
+// withHeader возвращает копию задания с заголовком запроса name
+func (t uploadTask) withHeader(name, value string) uploadTask {
+	t.headers = t.headers.Clone()
+	if t.headers == nil {
+		t.headers = make(http.Header)
+	}
+	t.headers.Set(name, value)
+	return t
+}
+
+// UploadResult результат загрузки одного файла в пакете
+type UploadResult struct {
+	JobID      string // Идентификатор задания в UploadQueue
+	LocalPath  string
+	RemoteName string
+	SessionID  string // Идентификатор сессии на сервере из последней попытки (пусто, если сервер его не вернул)
+	ServerURL  string // Адрес, принявший файл: основной или один из FallbackURLs (пусто при ошибке)
+	Duration   time.Duration
+	Skipped    bool  // Файл не отправлен как дубликат другого файла пакета (SkipDuplicates) или не изменился на сервере (ConditionalUpload)
+	Err        error // nil при успешной загрузке
 }
 
 // HTTPClient HTTP-клиент для потоковой передачи файлов
 type HTTPClient struct {
-	client *http.Client
-	config *ClientConfig
-	sem    chan struct{} // Семафор для ограничения параллельных загрузок
+	client  *http.Client
+	config  *ClientConfig
+	sem     chan struct{} // Семафор для ограничения параллельных загрузок
+	buffers *sync.Pool    // Пул буферов чтения файла
+	sized   *sync.Map     // Пулы буферов других размеров для AdaptiveBuffer: размер -> *sync.Pool
+	limiter *tokenBucket  // Ограничение скорости отправки (nil — без ограничения)
+	initErr error         // Ошибка конфигурации, возвращаемая при каждой загрузке
+	stats   *connStats    // Счетчики пула соединений для TransportStats
+
+	progressMu *sync.Mutex     // Сериализует вызовы callback прогресса всех загрузок клиента
+	events     *progressEvents // Канал ProgressChan
+	limits     *limitsCache    // Ограничения серверов из FetchServerLimits
+	transfers  *transferStats  // Статистика загрузок для Stats
+
+	interceptors []Interceptor // Перехватчики запросов загрузки (AddInterceptor)
 }
 
 // NewHTTPClient создает новый HTTP-клиент
@@ -50,8 +319,16 @@ func NewHTTPClient(timeout time.Duration) *HTTPClient {
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		config: DefaultConfig(),
-		sem:    make(chan struct{}, runtime.NumCPU()),
+		config:  DefaultConfig(),
+		sem:     make(chan struct{}, runtime.NumCPU()),
+		buffers: newBufferPool(DefaultConfig().BufferSize),
+		sized:   &sync.Map{},
+		stats:   &connStats{},
+
+		progressMu: &sync.Mutex{},
+		events:     &progressEvents{},
+		limits:     &limitsCache{},
+		transfers:  &transferStats{},
 	}
 }
 
@@ -62,76 +339,359 @@ func NewHTTPClientWithConfig(config *ClientConfig) *HTTPClient {
 	}
 
 	// Оптимизируем HTTP-клиент для высоких нагрузок
+	dialer := &net.Dialer{Timeout: config.ConnectTimeout}
+	configureKeepAlive(dialer, config.TCPKeepAlive, config.TCPKeepAliveInterval)
+	maxConns, maxIdle := connsPerHost(config)
+	maxIdleTotal, idleTimeout := idleConns(config)
 	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableCompression:  true, // Отключаем сжатие для бинарных данных
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          maxIdleTotal,
+		MaxConnsPerHost:       maxConns,
+		MaxIdleConnsPerHost:   maxIdle,
+		IdleConnTimeout:       idleTimeout,
+		DisableKeepAlives:     config.MaxIdleConns < 0,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		DisableCompression:    true, // Отключаем сжатие для бинарных данных
+	}
+
+	var initErr error
+	if config.ProxyURL != "" && config.SocketPath != "" {
+		initErr = fmt.Errorf("Unix-сокет нельзя использовать вместе с прокси")
+	}
+	if initErr == nil && config.ProxyURL != "" {
+		initErr = configureProxy(transport, dialer, config.ProxyURL)
+	}
+	if initErr == nil && config.SocketPath != "" {
+		configureUnixSocket(transport, dialer, config.SocketPath)
+	}
+	if initErr == nil {
+		transport.TLSClientConfig, initErr = config.TLSConfig()
+	}
+	if config.TLSInsecureSkipVerify {
+		logger := config.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("Проверка сертификата сервера отключена (TLSInsecureSkipVerify), используйте только для разработки")
+	}
+
+	// Соединения учитываются после настройки прокси и Unix-сокета, заменяющих DialContext
+	stats := &connStats{}
+	transport.DialContext = stats.dialContext(transport.DialContext)
+
+	var limiter *tokenBucket
+	if config.MaxUploadBytesPerSec > 0 {
+		limiter = newTokenBucket(config.MaxUploadBytesPerSec)
+	}
+
+	var roundTripper http.RoundTripper = &statsTransport{base: transport, stats: stats}
+	if len(config.CustomHeaders) > 0 {
+		if err := validateCustomHeaders(config.CustomHeaders); err != nil && initErr == nil {
+			initErr = err
+		}
+		roundTripper = &headerTransport{base: roundTripper, headers: config.CustomHeaders}
+	}
+	if err := validateMetadata(config.Metadata); err != nil && initErr == nil {
+		initErr = err
 	}
 
 	return &HTTPClient{
 		client: &http.Client{
 			Timeout:   config.Timeout,
-			Transport: transport,
+			Transport: roundTripper,
 		},
-		config: config,
-		sem:    make(chan struct{}, config.MaxConcurrency),
+		config:  config,
+		sem:     make(chan struct{}, config.MaxConcurrency),
+		buffers: newBufferPool(config.BufferSize),
+		sized:   &sync.Map{},
+		limiter: limiter,
+		initErr: initErr,
+		stats:   stats,
+
+		progressMu: &sync.Mutex{},
+		events:     &progressEvents{},
+		limits:     &limitsCache{},
+		transfers:  &transferStats{},
 	}
 }
 
+// Значения по умолчанию для простаивающих соединений
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// idleConns возвращает ограничения простаивающих соединений со всеми серверами
+// с учетом значений по умолчанию. При отключенном keep-alive (MaxIdleConns < 0)
+// соединения не сохраняются и ограничение не используется
+func idleConns(config *ClientConfig) (maxIdle int, timeout time.Duration) {
+	maxIdle, timeout = config.MaxIdleConns, config.IdleConnTimeout
+	if maxIdle == 0 {
+		maxIdle = defaultMaxIdleConns
+	}
+	if timeout <= 0 {
+		timeout = defaultIdleConnTimeout
+	}
+	return max(maxIdle, 0), timeout
+}
+
+// connsPerHost возвращает ограничения соединений с одним сервером с учетом значений по умолчанию.
+// Основной и резервные серверы используют общий транспорт, поэтому с FallbackURLs соединения
+// по умолчанию делятся между ними поровну и ни один сервер не занимает их все
+func connsPerHost(config *ClientConfig) (maxConns, maxIdle int) {
+	maxConns, maxIdle = config.MaxConnsPerHost, config.MaxIdleConnsPerHost
+	if maxConns <= 0 {
+		maxConns = max(config.MaxConcurrency/(1+len(config.FallbackURLs)), 1)
+	}
+	if maxIdle <= 0 {
+		maxIdle = max(config.MaxConcurrency/2, 1)
+	}
+	return maxConns, maxIdle
+}
+
 // UploadFile выполняет потоковую загрузку файла на сервер
 func (c *HTTPClient) UploadFile(ctx context.Context, filePath, serverURL string, progressCallback ProgressCallback) error {
+	return c.UploadFileWithOptions(ctx, filePath, serverURL, UploadOptions{}, progressCallback)
+}
+
+// upload выполняет загрузку файла с повторными попытками и возвращает
+// идентификатор сессии на сервере и адрес, принявший файл
+func (c *HTTPClient) upload(ctx context.Context, task uploadTask, serverURL string, progressCallback ProgressCallback) (string, string, error) {
 	// Получаем семафор для ограничения параллельных загрузок
-	select {
-	case c.sem <- struct{}{}:
-		defer func() { <-c.sem }()
-	case <-ctx.Done():
-		return ctx.Err()
+	if err := c.acquireSlot(ctx); err != nil {
+		return "", "", err
+	}
+	defer func() { <-c.sem }()
+
+	return c.uploadWithRetry(ctx, task, serverURL, c.serializeProgress(progressCallback))
+}
+
+// uploadWithRetry выполняет загрузку файла с повторными попытками и возвращает
+// идентификатор сессии последней попытки и адрес, принявший файл (основной или
+// один из FallbackURLs). Вызывающий должен удерживать слот семафора
+func (c *HTTPClient) uploadWithRetry(ctx context.Context, task uploadTask, serverURL string, progressCallback ProgressCallback) (string, string, error) {
+	progressCallback, finish := c.trackProgress(task.filePath, progressCallback)
+	sessionID, acceptedURL, err := c.retryUpload(ctx, task, serverURL, progressCallback)
+	finish(err)
+	return sessionID, acceptedURL, err
+}
+
+// retryUpload выполняет попытки загрузки для uploadWithRetry
+func (c *HTTPClient) retryUpload(ctx context.Context, task uploadTask, serverURL string, progressCallback ProgressCallback) (string, string, error) {
+	task = c.withCorrelationID(task)
+	if task.skipped == nil {
+		task.skipped = new(bool)
+	}
+	logger := c.logger().With("file", task.filePath, "url", serverURL, "correlation_id", c.correlationID(task))
+
+	if c.config.DryRun {
+		_, err := c.dryRun(task, logger)
+		return "", "", err
+	}
+
+	// Ошибки локального файла не исправятся повторной попыткой
+	if err := c.validateUploadFile(task.filePath); err != nil {
+		logger.Error("Ошибка загрузки", "error", err)
+		return "", "", err
 	}
+	// Файл, который сервер заведомо отклонит, не отправляется
+	if err := c.checkServerLimits(task.filePath, serverURL); err != nil {
+		logger.Error("Ошибка загрузки", "error", err)
+		return "", "", err
+	}
+
+	logger.Info("Начало загрузки")
+	startTime := time.Now()
+	progressCallback = ThrottleProgress(c.config.ProgressInterval, progressCallback)
 
 	var lastErr error
+	var sessionID string
+	attempts := 0
 	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(c.config.RetryDelay):
+				return sessionID, "", ctx.Err()
+			case <-time.After(c.retryDelay(lastErr)):
 			}
 		}
 
-		err := c.uploadFileOnce(ctx, filePath, serverURL, progressCallback)
+		attempts++
+		id, acceptedURL, err := c.uploadFileOnce(ctx, task, serverURL, attempt, progressCallback)
+		if id != "" {
+			sessionID = id
+		}
+		if err == nil && *task.skipped {
+			logger.Info("Файл на сервере не изменился, загрузка пропущена", "server_url", acceptedURL)
+			return sessionID, acceptedURL, nil
+		}
 		if err == nil {
-			return nil
+			logger.Info("Загрузка завершена", "duration", time.Since(startTime).Round(time.Millisecond), "session_id", sessionID, "server_url", acceptedURL)
+			return sessionID, acceptedURL, nil
 		}
 
 		lastErr = err
 		// Не повторяем попытки для определенных ошибок
-		if isPermanentError(err) {
+		if !c.shouldRetry(attempts, err) {
 			break
 		}
+		if attempt < c.config.RetryAttempts {
+			logger.Warn("Попытка загрузки не удалась, повторяем", "attempt", attempts, "error", err)
+		}
 	}
 
-	return fmt.Errorf("загрузка не удалась после %d попыток, последняя ошибка: %w", c.config.RetryAttempts+1, lastErr)
+	err := fmt.Errorf("загрузка не удалась после %d попыток, последняя ошибка: %w", attempts, lastErr)
+	logger.Error("Ошибка загрузки", "error", err)
+	return sessionID, "", err
 }
 
-// uploadFileOnce выполняет одну попытку загрузки файла
-func (c *HTTPClient) uploadFileOnce(ctx context.Context, filePath, serverURL string, progressCallback ProgressCallback) error {
-	// Открываем файл для чтения
-	file, err := os.Open(filePath)
+// validateUploadFile проверяет файл и настройки перед началом загрузки
+func (c *HTTPClient) validateUploadFile(filePath string) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+
+	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return fmt.Errorf("ошибка открытия файла: %w", err)
 	}
+	if fileInfo.IsDir() {
+		return fmt.Errorf("ошибка открытия файла: %s является директорией", filePath)
+	}
+	if fileInfo.Size() == 0 {
+		return fmt.Errorf("файл пустой")
+	}
+
+	if len(c.config.EncryptionKey) > 0 {
+		if len(c.config.EncryptionKey) != EncryptionKeySize {
+			return fmt.Errorf("ключ шифрования должен быть длиной %d байт, получено %d", EncryptionKeySize, len(c.config.EncryptionKey))
+		}
+		// Сервер распаковывает gzip, поэтому сжатие поверх шифротекста бесполезно,
+		// а сжатие под шифрованием потребовало бы распаковки при скачивании
+		if c.config.CompressUpload {
+			return fmt.Errorf("сжатие не поддерживается вместе с шифрованием")
+		}
+		// Сервер сверяет сумму с сохраняемым шифротекстом, а он зависит от случайного nonce
+		if c.config.VerifyChecksum {
+			return fmt.Errorf("проверка контрольной суммы не поддерживается вместе с шифрованием")
+		}
+	}
+
+	_, err = c.compressionLevel()
+	return err
+}
+
+// uploadFileOnce выполняет одну попытку загрузки файла: на serverURL, а при сетевой
+// ошибке или ответе 5xx — по очереди на каждый из FallbackURLs. Возвращает идентификатор
+// сессии из заголовка X-Upload-Session-ID ответа и адрес, принявший файл
+func (c *HTTPClient) uploadFileOnce(ctx context.Context, task uploadTask, serverURL string, attempt int, progressCallback ProgressCallback) (string, string, *UploadError) {
+	task = task.withContextOptions(ctx).withIdempotencyKey()
+
+	var sessionID string
+	var lastErr *UploadError
+	for i, targetURL := range append([]string{serverURL}, c.config.FallbackURLs...) {
+		if i > 0 {
+			c.logger().Warn("Сервер недоступен, пробуем резервный", "file", task.filePath, "url", targetURL, "error", lastErr)
+		}
+
+		spanCtx, span := c.startUploadSpan(ctx, task, targetURL, attempt)
+		id, err := c.sendFile(spanCtx, task, targetURL, span, progressCallback)
+		endUploadSpan(span, err)
+		if id != "" {
+			sessionID = id
+		}
+		if err == nil {
+			return sessionID, targetURL, nil
+		}
+
+		lastErr = err
+		// Ответ 4xx повторится на любом сервере, а после отмены контекста пробовать дальше бессмысленно
+		if isPermanentError(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return sessionID, "", lastErr
+}
+
+// sendFile передает файл на сервер в одном HTTP-запросе и возвращает
+// идентификатор сессии, если сервер успел ответить
+func (c *HTTPClient) sendFile(ctx context.Context, task uploadTask, serverURL string, span trace.Span, progressCallback ProgressCallback) (string, *UploadError) {
+	// Открываем файл для чтения
+	file, err := os.Open(task.filePath)
+	if err != nil {
+		return "", newUploadError("ошибка открытия файла", err)
+	}
 	defer file.Close()
 
 	// Получаем информацию о файле
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return fmt.Errorf("ошибка получения информации о файле: %w", err)
+		return "", newUploadError("ошибка получения информации о файле", err)
 	}
 
 	fileSize := fileInfo.Size()
 	if fileSize == 0 {
-		return fmt.Errorf("файл пустой")
+		return "", newUploadError("файл пустой", nil)
+	}
+	span.SetAttributes(attribute.Int64("file.size", fileSize))
+
+	if c.config.VerifyChecksum {
+		sum, err := fileCRC32C(file)
+		if err != nil {
+			return "", newUploadError("ошибка вычисления контрольной суммы", err)
+		}
+		task = task.withHeader(ContentCRC32CHeader, sum)
+	}
+	// Сервер хранит шифротекст, который не совпадет с суммой исходного файла
+	if c.config.ConditionalUpload && len(c.config.EncryptionKey) == 0 {
+		sum, err := contentSHA256(file)
+		if err != nil {
+			return "", newUploadError("ошибка вычисления контрольной суммы", err)
+		}
+		if c.unchangedOnServer(ctx, serverURL, task, sum) {
+			if task.skipped != nil {
+				*task.skipped = true
+			}
+			c.observeAttempt(task.filePath, 0, 0, true, nil)
+			return "", nil
+		}
+		task = task.withHeader("If-None-Match", `"`+sum+`"`)
+	}
+	// Размер шифротекста заранее не известен, поэтому заголовок отправляется только без шифрования
+	if c.config.SendFileSizeHeader && len(c.config.EncryptionKey) == 0 {
+		task = task.withHeader(FileSizeHeader, strconv.FormatInt(fileSize, 10))
+	}
+	// Сервер хранит и хеширует шифротекст, поэтому с шифрованием суммы несравнимы
+	task.verifyChecksum = c.config.VerifyServerChecksum && len(c.config.EncryptionKey) == 0
+
+	return c.sendStream(ctx, file, fileSize, task, serverURL, progressCallback)
+}
+
+// sendStream передает содержимое src в одном multipart-запросе. Если размер
+// fileSize неизвестен (0), прогресс сообщает только количество отправленных байт
+func (c *HTTPClient) sendStream(ctx context.Context, src io.Reader, fileSize int64, task uploadTask, serverURL string, progressCallback ProgressCallback) (string, *UploadError) {
+	start := time.Now()
+	sent := &countingWriter{}
+	sessionID, uploadErr := c.sendMultipart(ctx, src, fileSize, task, serverURL, sent, progressCallback)
+
+	// Горутина записи может еще работать, если сервер ответил, не дочитав тело,
+	// поэтому счетчик читается атомарно
+	var err error
+	if uploadErr != nil {
+		err = uploadErr
+	}
+	skipped := task.skipped != nil && *task.skipped
+	c.observeAttempt(task.filePath, sent.n.Load(), time.Since(start), skipped, err)
+	return sessionID, uploadErr
+}
+
+// sendMultipart выполняет запрос sendStream; отправленные байты содержимого
+// учитываются в sent
+func (c *HTTPClient) sendMultipart(ctx context.Context, src io.Reader, fileSize int64, task uploadTask, serverURL string, sent *countingWriter, progressCallback ProgressCallback) (string, *UploadError) {
+	level, err := c.compressionLevel()
+	if err != nil {
+		return "", newUploadError("ошибка настройки сжатия", err)
 	}
 
 	// Создаем pipe для потоковой передачи
@@ -149,16 +709,49 @@ func (c *HTTPClient) uploadFileOnce(ctx context.Context, filePath, serverURL str
 		defer pw.Close()
 		defer multipartWriter.Close()
 
+		if err := writeFields(multipartWriter, task.fields); err != nil {
+			done <- err
+			return
+		}
+
 		// Создаем поле для файла
-		part, err := multipartWriter.CreateFormFile("file", filepath.Base(filePath))
+		part, err := multipartWriter.CreateFormFile("file", task.formFileName())
 		if err != nil {
 			done <- fmt.Errorf("ошибка создания поля формы: %w", err)
 			return
 		}
 
-		// Используем конфигурируемый размер буфера
-		buffer := make([]byte, c.config.BufferSize)
-		var bytesTransferred int64
+		// При сжатии или шифровании данные файла проходят через кодировщик,
+		// а прогресс отражает количество отправленных закодированных байт
+		sent.w = part
+		var dst io.Writer = sent
+		var encoder io.WriteCloser
+		switch {
+		case len(c.config.EncryptionKey) > 0:
+			encoder, err = newEncryptWriter(sent, c.config.EncryptionKey)
+			if err != nil {
+				done <- fmt.Errorf("ошибка настройки шифрования: %w", err)
+				return
+			}
+		case c.config.CompressUpload:
+			encoder, _ = gzip.NewWriterLevel(sent, level)
+		}
+		if encoder != nil {
+			dst = encoder
+		}
+
+		// Буфер конфигурируемого размера берется из пула, чтобы параллельные
+		// загрузки не создавали новый буфер на каждую попытку
+		var adaptive *adaptiveBuffer
+		bufferSize := c.config.BufferSize
+		if c.config.AdaptiveBuffer {
+			adaptive = newAdaptiveBuffer(c.config.BufferSize, c.config.MaxBufferSize)
+			bufferSize = adaptive.size
+		}
+		bufferPtr := c.getBufferSize(bufferSize)
+		defer func() { c.putBufferSize(bufferPtr) }()
+		buffer := *bufferPtr
+		var bytesRead int64
 
 		for {
 			select {
@@ -166,24 +759,57 @@ func (c *HTTPClient) uploadFileOnce(ctx context.Context, filePath, serverURL str
 				done <- ctx.Err()
 				return
 			default:
-				n, err := file.Read(buffer)
+				chunkStart := time.Now()
+				n, err := src.Read(buffer)
 				if n > 0 {
-					_, writeErr := part.Write(buffer[:n])
+					sentBefore := sent.n.Load()
+					_, writeErr := dst.Write(buffer[:n])
 					if writeErr != nil {
 						done <- fmt.Errorf("ошибка записи в pipe: %w", writeErr)
 						return
 					}
 
-					bytesTransferred += int64(n)
+					// Скорость чтения и отправки части определяет размер буфера для следующей
+					if adaptive != nil {
+						if size := adaptive.observe(n, time.Since(chunkStart)); size != len(buffer) {
+							c.putBufferSize(bufferPtr)
+							bufferPtr = c.getBufferSize(size)
+							buffer = *bufferPtr
+						}
+					}
+
+					// Ограничиваем скорость по фактически отправленным (закодированным) байтам
+					if throttleErr := c.throttle(ctx, sent.n.Load()-sentBefore); throttleErr != nil {
+						done <- throttleErr
+						return
+					}
+
+					bytesRead += int64(n)
 
 					// Вызываем callback для отображения прогресса
 					if progressCallback != nil {
-						percentage := float64(bytesTransferred) / float64(fileSize) * 100
-						progressCallback(bytesTransferred, fileSize, percentage)
+						var percentage float64
+						if fileSize > 0 {
+							percentage = float64(bytesRead) / float64(fileSize) * 100
+						}
+						progressCallback(sent.n.Load(), fileSize, percentage)
 					}
 				}
 
 				if err == io.EOF {
+					if encoder != nil {
+						if closeErr := encoder.Close(); closeErr != nil {
+							done <- fmt.Errorf("ошибка записи в pipe: %w", closeErr)
+							return
+						}
+						if progressCallback != nil {
+							var percentage float64
+							if fileSize > 0 {
+								percentage = 100
+							}
+							progressCallback(sent.n.Load(), fileSize, percentage)
+						}
+					}
 					done <- nil // Успешное завершение
 					return
 				}
@@ -198,85 +824,141 @@ func (c *HTTPClient) uploadFileOnce(ctx context.Context, filePath, serverURL str
 	// Создаем HTTP запрос
 	req, err := http.NewRequestWithContext(ctx, "POST", serverURL, pr)
 	if err != nil {
-		return fmt.Errorf("ошибка создания HTTP запроса: %w", err)
+		return "", newUploadError("ошибка создания HTTP запроса", err)
 	}
 
+	for key, values := range task.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	c.setMetadataHeaders(req)
+	if task.remotePath != "" {
+		req.Header.Set(c.pathHeaderName(), task.remotePath)
+	}
 	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
+	c.injectTraceContext(ctx, req)
+	if c.config.CompressUpload {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
-	// Выполняем запрос
-	resp, err := c.client.Do(req)
+	// Выполняем запрос через перехватчики
+	resp, err := c.doIntercepted(req)
 	if err != nil {
-		return fmt.Errorf("ошибка выполнения HTTP запроса: %w", err)
+		return "", newUploadError("ошибка выполнения HTTP запроса", err)
 	}
 	defer resp.Body.Close()
+	sessionID := resp.Header.Get(SessionIDHeader)
 
-	// Ждем завершения горутины записи
-	writeErr := <-done
-	if writeErr != nil {
-		return writeErr
+	// Файл с тем же содержимым уже есть на сервере (If-None-Match)
+	if resp.StatusCode == http.StatusNotModified && task.headers.Get("If-None-Match") != "" {
+		if task.skipped != nil {
+			*task.skipped = true
+		}
+		return sessionID, nil
 	}
 
-	// Проверяем статус ответа
+	// Проверяем статус ответа до ожидания горутины: сервер мог
+	// отклонить запрос, не дочитав тело
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("сервер вернул ошибку: %s, статус: %d, тело: %s", resp.Status, resp.StatusCode, string(body))
+		return sessionID, responseError(resp)
 	}
 
-	return nil
-}
-
-// isPermanentError определяет, является ли ошибка постоянной (не требует retry)
-func isPermanentError(err error) bool {
-	if err == nil {
-		return false
+	// Ждем завершения горутины записи
+	if writeErr := <-done; writeErr != nil {
+		return sessionID, newUploadError("ошибка передачи файла", writeErr)
 	}
 
-	errStr := err.Error()
-	// Ошибки, которые не стоит повторять
-	permanentErrors := []string{
-		"ошибка открытия файла",
-		"файл пустой",
-		"ошибка создания поля формы",
-		"ошибка чтения файла",
-		"ошибка записи в pipe",
+	if task.verifyChecksum || task.expectedContentType != "" {
+		return sessionID, c.verifyUploadResponse(resp, task)
 	}
+	return sessionID, nil
+}
 
-	for _, permanentErr := range permanentErrors {
-		if strings.Contains(errStr, permanentErr) {
-			return true
-		}
+// compressionLevel возвращает уровень сжатия gzip с учетом значения по умолчанию
+func (c *HTTPClient) compressionLevel() (int, error) {
+	level := c.config.CompressionLevel
+	if level == 0 {
+		return gzip.DefaultCompression, nil
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return 0, fmt.Errorf("некорректный уровень сжатия: %d", level)
 	}
+	return level, nil
+}
 
-	return false
+// countingWriter подсчитывает количество записанных байт. Счетчик атомарный:
+// его читают и после ответа сервера, пока горутина записи может еще писать
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(int64(n))
+	return n, err
 }
 
 // UploadFileWithProgress выполняет загрузку файла с автоматическим отображением прогресса
+// в ProgressOutput (nil — os.Stdout), см. UploadFileWithProgressWriter
 func (c *HTTPClient) UploadFileWithProgress(ctx context.Context, filePath, serverURL string) error {
-	var mu sync.Mutex
-	var lastUpdate time.Time
+	output := c.config.ProgressOutput
+	if output == nil {
+		output = os.Stdout
+	}
+	return c.UploadFileWithProgressWriter(ctx, filePath, serverURL, output)
+}
 
-	progressCallback := func(bytesTransferred, totalBytes int64, percentage float64) {
-		mu.Lock()
-		defer mu.Unlock()
+// UploadFileWithProgressWriter выполняет загрузку файла, выводя прогресс в w: например,
+// в файл журнала, компонент интерфейса или буфер теста. По умолчанию выводится полоса
+// прогресса, в формате json — строка JSON на обновление (см. ProgressFormat); в формате
+// human прогресс записывается в лог на уровне debug, и w не используется
+func (c *HTTPClient) UploadFileWithProgressWriter(ctx context.Context, filePath, serverURL string, w io.Writer) error {
+	logger := c.logger().With("file", filePath)
 
-		// Обновляем прогресс не чаще чем раз в секунду
-		if time.Since(lastUpdate) >= time.Second {
-			fmt.Printf("\rПрогресс: %.2f%% (%s / %s)",
-				percentage,
-				formatBytes(bytesTransferred),
-				formatBytes(totalBytes))
-			lastUpdate = time.Now()
+	// Частота вызовов ограничивается ClientConfig.ProgressInterval в UploadFile
+	var progressCallback ProgressCallback
+	switch c.config.ProgressFormat {
+	case "", ProgressFormatBar:
+		progressCallback = barProgress(w, c.config.ETAWarmupPeriod, c.config.EMAAlpha)
+	case ProgressFormatHuman:
+		var mu sync.Mutex
+		meter := newSpeedMeter(time.Now(), c.config.ETAWarmupPeriod, c.config.EMAAlpha)
+		progressCallback = func(bytesTransferred, totalBytes int64, percentage float64) {
+			mu.Lock()
+			now := time.Now()
+			meter.add(now, bytesTransferred)
+			speed := meter.speed()
+			eta := "вычисляется..."
+			if remaining, ok := meter.eta(now, bytesTransferred, totalBytes); ok {
+				eta = remaining.Round(time.Second).String()
+			}
+			mu.Unlock()
+
+			logger.Debug("Прогресс",
+				"percentage", fmt.Sprintf("%.2f", percentage),
+				"transferred", formatBytes(bytesTransferred),
+				"total", formatBytes(totalBytes),
+				"speed", formatBytes(int64(speed))+"/s",
+				"eta", eta)
 		}
+	case ProgressFormatJSON:
+		progressCallback = jsonProgress(w, filepath.Base(filePath), c.config.ETAWarmupPeriod, c.config.EMAAlpha, c.TransportStats)
+	default:
+		return fmt.Errorf("неизвестный формат прогресса: %s", c.config.ProgressFormat)
 	}
 
-	err := c.UploadFile(ctx, filePath, serverURL, progressCallback)
-	if err != nil {
-		fmt.Printf("\nОшибка: %v\n", err)
-		return err
-	}
+	// Начало, завершение и ошибки загрузки записываются в лог в UploadFile
+	return c.UploadFile(ctx, filePath, serverURL, c.persistProgress(filePath, progressCallback))
+}
 
-	fmt.Printf("\nЗагрузка завершена успешно!\n")
-	return nil
+// logger возвращает логгер клиента
+func (c *HTTPClient) logger() *slog.Logger {
+	if c.config.Logger != nil {
+		return c.config.Logger
+	}
+	return slog.Default()
 }
 
 // formatBytes форматирует байты в читаемый вид
@@ -295,21 +977,48 @@ func formatBytes(bytes int64) string {
 
 // UploadMultipleFiles загружает несколько файлов параллельно
 func (c *HTTPClient) UploadMultipleFiles(ctx context.Context, files []string, serverURL string, progressCallback ProgressCallback) error {
-	if len(files) == 0 {
-		return fmt.Errorf("список файлов пуст")
+	tasks := make([]uploadTask, 0, len(files))
+	for _, filePath := range files {
+		tasks = append(tasks, uploadTask{filePath: filePath})
+	}
+
+	_, err := c.uploadTasks(ctx, tasks, serverURL, progressCallback)
+	return err
+}
+
+// uploadTasks загружает набор файлов параллельно и возвращает результат по каждому файлу
+func (c *HTTPClient) uploadTasks(ctx context.Context, tasks []uploadTask, serverURL string, progressCallback ProgressCallback) ([]UploadResult, error) {
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("список файлов пуст")
+	}
+	if c.config.DryRun {
+		return c.dryRunTasks(tasks, serverURL)
 	}
 
 	var wg sync.WaitGroup
-	errors := make(chan error, len(files))
+	results := make([]UploadResult, len(tasks))
 
-	// Создаем контекст с отменой для всех горутин
+	// Создаем контекст с отменой для всех горутин; при FailFast первая ошибка отменяет остальные загрузки
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	var duplicates *duplicateFilter
+	if c.config.SkipDuplicates {
+		duplicates = &duplicateFilter{}
+	}
+
 	// Запускаем загрузку каждого файла в отдельной горутине
-	for _, filePath := range files {
+	for i, task := range tasks {
+		if duplicates != nil {
+			if first, ok := duplicates.firstSeen(task.filePath); ok {
+				c.logger().Info("Файл уже есть в пакете, загрузка пропущена", "file", task.filePath, "first", first)
+				results[i] = UploadResult{LocalPath: task.filePath, RemoteName: task.formFileName(), Skipped: true}
+				continue
+			}
+		}
+
 		wg.Add(1)
-		go func(file string) {
+		go func(i int, task uploadTask) {
 			defer wg.Done()
 
 			// Создаем отдельный callback для каждого файла
@@ -319,47 +1028,148 @@ func (c *HTTPClient) UploadMultipleFiles(ctx context.Context, files []string, se
 				}
 			}
 
-			err := c.UploadFile(ctx, file, serverURL, fileProgressCallback)
-			if err != nil {
-				select {
-				case errors <- fmt.Errorf("ошибка загрузки файла %s: %w", file, err):
-				case <-ctx.Done():
-				}
+			startTime := time.Now()
+			task.skipped = new(bool)
+			sessionID, acceptedURL, err := c.upload(ctx, task, serverURL, fileProgressCallback)
+			if err != nil && c.config.FailFast {
+				cancel()
+			}
+			results[i] = UploadResult{
+				LocalPath:  task.filePath,
+				RemoteName: task.formFileName(),
+				SessionID:  sessionID,
+				ServerURL:  acceptedURL,
+				Duration:   time.Since(startTime),
+				Skipped:    *task.skipped,
+				Err:        err,
 			}
-		}(filePath)
+		}(i, task)
 	}
 
 	// Ждем завершения всех загрузок
 	wg.Wait()
-	close(errors)
 
 	// Собираем все ошибки
 	var allErrors []string
-	for err := range errors {
-		allErrors = append(allErrors, err.Error())
+	for _, result := range results {
+		if result.Err != nil {
+			allErrors = append(allErrors, fmt.Sprintf("ошибка загрузки файла %s: %v", result.LocalPath, result.Err))
+		}
 	}
 
 	if len(allErrors) > 0 {
-		return fmt.Errorf("ошибки при загрузке файлов: %s", strings.Join(allErrors, "; "))
+		return results, fmt.Errorf("ошибки при загрузке файлов: %s", strings.Join(allErrors, "; "))
 	}
 
-	return nil
+	return results, nil
 }
 
 // UploadDirectory загружает все файлы из директории
 func (c *HTTPClient) UploadDirectory(ctx context.Context, dirPath, serverURL string, progressCallback ProgressCallback) error {
+	return c.UploadDirectoryWithFilter(ctx, dirPath, serverURL, FilterConfig{}, progressCallback)
+}
+
+// UploadDirectoryWithFilter загружает файлы из директории, отобранные по шаблонам фильтра
+func (c *HTTPClient) UploadDirectoryWithFilter(ctx context.Context, dirPath, serverURL string, filter FilterConfig, progressCallback ProgressCallback) error {
+	files, err := directoryFiles(dirPath, filter)
+	if err != nil {
+		return err
+	}
+
+	return c.UploadMultipleFiles(ctx, files, serverURL, progressCallback)
+}
+
+// UploadDirectoryBatch загружает файлы из директории последовательными пакетами по batchSize
+// файлов в порядке имен: файлы пакета загружаются параллельно, а следующий пакет начинается
+// только после завершения всех загрузок предыдущего. Подходит для серверов с блокировкой
+// на уровне директории и случаев, когда важен порядок файлов. При FailFast ошибка пакета
+// прерывает загрузку, иначе загружаются все пакеты и возвращаются ошибки каждого
+func (c *HTTPClient) UploadDirectoryBatch(ctx context.Context, dirPath, serverURL string, batchSize int, progressCallback ProgressCallback) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("размер пакета должен быть положительным, получено %d", batchSize)
+	}
+
+	files, err := directoryFiles(dirPath, FilterConfig{})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("список файлов пуст")
+	}
+
+	batches := (len(files) + batchSize - 1) / batchSize
+	var batchErrors []error
+	for batch := 0; batch < batches; batch++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batchFiles := files[batch*batchSize : min((batch+1)*batchSize, len(files))]
+		c.logger().Info("Загрузка пакета", "dir", dirPath, "batch", batch+1, "batches", batches, "files", len(batchFiles))
+		if err := c.UploadMultipleFiles(ctx, batchFiles, serverURL, progressCallback); err != nil {
+			err = fmt.Errorf("пакет %d из %d: %w", batch+1, batches, err)
+			if c.config.FailFast {
+				return err
+			}
+			batchErrors = append(batchErrors, err)
+		}
+	}
+	return errors.Join(batchErrors...)
+}
+
+// directoryFiles возвращает файлы директории, отобранные по шаблонам фильтра, в порядке имен
+func directoryFiles(dirPath string, filter FilterConfig) ([]string, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
-		return fmt.Errorf("ошибка чтения директории: %w", err)
+		return nil, fmt.Errorf("ошибка чтения директории: %w", err)
 	}
 
 	var files []string
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() && filter.matches(entry.Name()) {
 			filePath := filepath.Join(dirPath, entry.Name())
 			files = append(files, filePath)
 		}
 	}
+	return files, nil
+}
 
-	return c.UploadMultipleFiles(ctx, files, serverURL, progressCallback)
+// UploadDirectoryRecursive загружает все файлы из дерева директорий,
+// передавая серверу относительный путь каждого файла в заголовке X-Relative-Path
+func (c *HTTPClient) UploadDirectoryRecursive(ctx context.Context, dirPath, serverURL string, progressCallback ProgressCallback) error {
+	var tasks []uploadTask
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Символические ссылки пропускаем, чтобы не выйти за пределы дерева
+		if d.Type()&fs.ModeSymlink != 0 {
+			c.logger().Warn("Пропускаем символическую ссылку", "path", path)
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
+		}
+
+		headers := make(http.Header)
+		headers.Set(RelativePathHeader, filepath.ToSlash(relPath))
+		tasks = append(tasks, uploadTask{filePath: path, headers: headers})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка обхода директории: %w", err)
+	}
+
+	_, err = c.uploadTasks(ctx, tasks, serverURL, progressCallback)
+	return err
 }
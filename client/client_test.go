@@ -1,12 +1,26 @@
 package client
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"httpBinaryClient/server"
 )
 
 func TestUploadFile_FileNotFound(t *testing.T) {
@@ -111,3 +125,555 @@ func TestUploadFile_Integration(t *testing.T) {
 		t.Error("Progress callback не был вызван")
 	}
 }
+
+func TestUploadDirectoryRecursive_RelativePaths(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"root.bin":             "root",
+		"subdir/file.bin":      "file",
+		"subdir/deep/deep.bin": "deep",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Ошибка создания директории: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(tempDir, "root.bin"), filepath.Join(tempDir, "link.bin")); err != nil {
+		t.Fatalf("Ошибка создания символической ссылки: %v", err)
+	}
+
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		received = append(received, r.Header.Get(RelativePathHeader))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := NewHTTPClient(10 * time.Second)
+	if err := httpClient.UploadDirectoryRecursive(context.Background(), tempDir, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки директории: %v", err)
+	}
+
+	sort.Strings(received)
+	expected := []string{"root.bin", "subdir/deep/deep.bin", "subdir/file.bin"}
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Errorf("Ожидались пути %v, получены %v", expected, received)
+	}
+}
+
+func TestUploadDirectoryBatch(t *testing.T) {
+	tempDir := t.TempDir()
+	names := []string{"a.bin", "b.bin", "c.bin", "d.bin", "e.bin"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var received []string
+	var active, maxActive int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+
+		_, header, err := r.FormFile("file")
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		if err == nil {
+			received = append(received, header.Filename)
+		}
+		mu.Unlock()
+		if err != nil || header.Filename == "b.bin" {
+			http.Error(w, "отказ", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.MaxConcurrency = 8
+	config.FailFast = false
+	httpClient := NewHTTPClientWithConfig(config)
+
+	err := httpClient.UploadDirectoryBatch(context.Background(), tempDir, server.URL, 2, nil)
+	if err == nil || !strings.Contains(err.Error(), "пакет 1 из 3") {
+		t.Errorf("Ожидалась ошибка первого пакета, получено %v", err)
+	}
+	if maxActive > 2 {
+		t.Errorf("Одновременно загружалось %d файлов при размере пакета 2", maxActive)
+	}
+
+	// Без FailFast загружаются все пакеты, причем по порядку
+	if len(received) != len(names) {
+		t.Fatalf("Ожидалось %d запросов, получено %v", len(names), received)
+	}
+	for i := range received {
+		if batch := sort.SearchStrings(names, received[i]) / 2; batch != i/2 {
+			t.Errorf("Файл %s пакета %d получен %d-м", received[i], batch+1, i+1)
+		}
+	}
+
+	// С FailFast ошибка пакета прерывает загрузку
+	received = nil
+	config.FailFast = true
+	if err := httpClient.UploadDirectoryBatch(context.Background(), tempDir, server.URL, 2, nil); err == nil {
+		t.Error("Ожидалась ошибка загрузки")
+	}
+	if len(received) > 2 {
+		t.Errorf("После ошибки первого пакета загружены файлы %v", received)
+	}
+
+	if err := httpClient.UploadDirectoryBatch(context.Background(), tempDir, server.URL, 0, nil); err == nil {
+		t.Error("Ожидалась ошибка для нулевого размера пакета")
+	}
+}
+
+func TestFilterConfig_Matches(t *testing.T) {
+	filter := FilterConfig{
+		IncludePatterns: []string{"*.bin", "*.log"},
+		ExcludePatterns: []string{"debug*.log", ".DS_Store"},
+	}
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"data.bin", true},
+		{"app.log", true},
+		{"debug_1.log", false},
+		{".DS_Store", false},
+		{"notes.txt", false},
+	}
+
+	for _, test := range tests {
+		if result := filter.matches(test.name); result != test.expected {
+			t.Errorf("Для %s ожидалось %v, получено %v", test.name, test.expected, result)
+		}
+	}
+
+	if !(FilterConfig{}).matches("anything.tmp") {
+		t.Error("Пустой фильтр должен пропускать все файлы")
+	}
+	if err := (FilterConfig{ExcludePatterns: []string{"[a-"}}).validate(); err == nil {
+		t.Error("Ожидалась ошибка для некорректного шаблона")
+	}
+}
+
+func TestUploadFile_Compressed(t *testing.T) {
+	content := []byte(strings.Repeat("сжимаемые данные ", 4096))
+	testFile := filepath.Join(t.TempDir(), "compress.txt")
+	if err := os.WriteFile(testFile, content, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var received []byte
+	var encoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received, _ = io.ReadAll(gz)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.CompressUpload = true
+	config.CompressionLevel = gzip.BestCompression
+	httpClient := NewHTTPClientWithConfig(config)
+
+	var lastSent, lastTotal int64
+	var lastPercentage float64
+	progressCallback := func(bytesTransferred, totalBytes int64, percentage float64) {
+		lastSent, lastTotal, lastPercentage = bytesTransferred, totalBytes, percentage
+	}
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, progressCallback); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	if encoding != "gzip" {
+		t.Errorf("Ожидался заголовок Content-Encoding: gzip, получен %q", encoding)
+	}
+	if string(received) != string(content) {
+		t.Error("Распакованные данные не совпадают с исходными")
+	}
+	if lastTotal != int64(len(content)) || lastSent >= lastTotal || lastPercentage != 100 {
+		t.Errorf("Неверный прогресс: отправлено %d из %d (%.2f%%)", lastSent, lastTotal, lastPercentage)
+	}
+}
+
+func TestUploadMultipleFiles_FailFast(t *testing.T) {
+	// Файл bad.bin отклоняется сразу, остальные принимаются с задержкой
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil || header.Filename == "bad.bin" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	var tasks []uploadTask
+	for _, name := range []string{"bad.bin", "good1.bin", "good2.bin"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+		tasks = append(tasks, uploadTask{filePath: path})
+	}
+
+	for _, failFast := range []bool{true, false} {
+		config := DefaultConfig()
+		config.RetryAttempts = 0
+		config.MaxConcurrency = len(tasks)
+		config.FailFast = failFast
+		httpClient := NewHTTPClientWithConfig(config)
+
+		results, err := httpClient.uploadTasks(context.Background(), tasks, server.URL, nil)
+		if err == nil {
+			t.Fatalf("FailFast=%v: ожидалась ошибка пакета", failFast)
+		}
+		if len(results) != len(tasks) {
+			t.Fatalf("FailFast=%v: ожидалось %d результатов, получено %d", failFast, len(tasks), len(results))
+		}
+		for _, result := range results[1:] {
+			// При FailFast загрузки отменяются ошибкой bad.bin, иначе завершаются успешно
+			if (result.Err != nil) != failFast {
+				t.Errorf("FailFast=%v: неожиданный результат %s: %v", failFast, result.LocalPath, result.Err)
+			}
+		}
+	}
+}
+
+func TestNewHTTPClientWithConfig_TransportTimeouts(t *testing.T) {
+	config := DefaultConfig()
+	transport := NewHTTPClientWithConfig(config).client.Transport.(*statsTransport).base
+
+	if transport.TLSHandshakeTimeout != 10*time.Second || transport.ResponseHeaderTimeout != 30*time.Second {
+		t.Errorf("Неверные таймауты транспорта: TLS %v, заголовки %v", transport.TLSHandshakeTimeout, transport.ResponseHeaderTimeout)
+	}
+	if transport.DialContext == nil {
+		t.Error("Не задан DialContext с таймаутом соединения")
+	}
+}
+
+func TestNewHTTPClientWithConfig_ConnsPerHost(t *testing.T) {
+	tests := []struct {
+		name                      string
+		maxConcurrency            int
+		fallbacks                 []string
+		maxConns, maxIdle         int
+		wantMaxConns, wantMaxIdle int
+	}{
+		{"по умолчанию", 8, nil, 0, 0, 8, 4},
+		{"один поток", 1, nil, 0, 0, 1, 1},
+		{"заданы явно", 8, nil, 16, 2, 16, 2},
+		{"резервные серверы", 9, []string{"http://b", "http://c"}, 0, 0, 3, 4},
+		{"серверов больше потоков", 2, []string{"http://b", "http://c"}, 0, 0, 1, 1},
+		{"резервные серверы и явное значение", 8, []string{"http://b"}, 8, 0, 8, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.MaxConcurrency = tt.maxConcurrency
+			config.FallbackURLs = tt.fallbacks
+			config.MaxConnsPerHost = tt.maxConns
+			config.MaxIdleConnsPerHost = tt.maxIdle
+			transport := NewHTTPClientWithConfig(config).client.Transport.(*statsTransport).base
+
+			if transport.MaxConnsPerHost != tt.wantMaxConns || transport.MaxIdleConnsPerHost != tt.wantMaxIdle {
+				t.Errorf("Соединения с сервером: %d (простаивающих %d), ожидалось %d (%d)",
+					transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost, tt.wantMaxConns, tt.wantMaxIdle)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientWithConfig_IdleConns(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxIdle         int
+		timeout         time.Duration
+		wantMaxIdle     int
+		wantTimeout     time.Duration
+		wantNoKeepAlive bool
+	}{
+		{"по умолчанию", 0, 0, 100, 90 * time.Second, false},
+		{"заданы явно", 10, 5 * time.Second, 10, 5 * time.Second, false},
+		{"keep-alive отключен", -1, 0, 0, 90 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.MaxIdleConns = tt.maxIdle
+			config.IdleConnTimeout = tt.timeout
+			transport := NewHTTPClientWithConfig(config).client.Transport.(*statsTransport).base
+
+			if transport.MaxIdleConns != tt.wantMaxIdle || transport.IdleConnTimeout != tt.wantTimeout || transport.DisableKeepAlives != tt.wantNoKeepAlive {
+				t.Errorf("Простаивающих соединений %d, таймаут %v, keep-alive отключен %v; ожидалось %d, %v, %v",
+					transport.MaxIdleConns, transport.IdleConnTimeout, transport.DisableKeepAlives, tt.wantMaxIdle, tt.wantTimeout, tt.wantNoKeepAlive)
+			}
+		})
+	}
+}
+
+func TestUploadFile_ResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		// Сервер принял тело, но не отвечает
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	testFile := filepath.Join(t.TempDir(), "hang.bin")
+	os.WriteFile(testFile, []byte("data"), 0644)
+
+	config := DefaultConfig()
+	config.RetryAttempts = 0
+	config.ResponseHeaderTimeout = 100 * time.Millisecond
+	start := time.Now()
+	err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, server.URL, nil)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка таймаута заголовков ответа")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Таймаут сработал слишком поздно: %v", elapsed)
+	}
+}
+
+func TestUploadFile_TLSHandshakeTimeout(t *testing.T) {
+	// Принимает TCP-соединения, но не отвечает на TLS-рукопожатие
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	testFile := filepath.Join(t.TempDir(), "tls.bin")
+	os.WriteFile(testFile, []byte("data"), 0644)
+
+	config := DefaultConfig()
+	config.RetryAttempts = 0
+	config.TLSHandshakeTimeout = 100 * time.Millisecond
+	start := time.Now()
+	err = NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, "https://"+listener.Addr().String()+"/upload", nil)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка таймаута TLS-рукопожатия")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Таймаут сработал слишком поздно: %v", elapsed)
+	}
+}
+
+func TestUploadFile_Failover(t *testing.T) {
+	// Первый сервер не принимает соединения: адрес освобождается сразу после запуска
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL + "/upload"
+	down.Close()
+
+	var mu sync.Mutex
+	received := map[string]int{}
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received[header.Filename]++
+		mu.Unlock()
+		if header.Filename == "rejected.bin" {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}))
+	defer backup.Close()
+
+	dir := t.TempDir()
+	var tasks []uploadTask
+	for _, name := range []string{"failover.bin", "rejected.bin"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+		tasks = append(tasks, uploadTask{filePath: path})
+	}
+
+	config := DefaultConfig()
+	config.RetryAttempts = 2
+	config.RetryDelay = time.Millisecond
+	config.FailFast = false
+	config.FallbackURLs = []string{backup.URL + "/upload"}
+	results, err := NewHTTPClientWithConfig(config).uploadTasks(context.Background(), tasks, downURL, nil)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка для отклоненного файла")
+	}
+
+	if results[0].Err != nil || results[0].ServerURL != backup.URL+"/upload" {
+		t.Errorf("Файл не загружен на резервный сервер: %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].ServerURL != "" {
+		t.Errorf("Ожидалась ошибка 413: %+v", results[1])
+	}
+	// Ответ 4xx резервного сервера окончательный: повторных попыток нет
+	if received["failover.bin"] != 1 || received["rejected.bin"] != 1 {
+		t.Errorf("Неверное число запросов к резервному серверу: %v", received)
+	}
+}
+
+func TestUploadFile_NoFailoverOnClientError(t *testing.T) {
+	var primary, backup atomic.Int32
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primary.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer primaryServer.Close()
+	backupServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backup.Add(1)
+	}))
+	defer backupServer.Close()
+
+	testFile := filepath.Join(t.TempDir(), "forbidden.bin")
+	if err := os.WriteFile(testFile, []byte("forbidden"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.FallbackURLs = []string{backupServer.URL}
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, primaryServer.URL, nil); err == nil {
+		t.Fatal("Ожидалась ошибка 403")
+	}
+	if primary.Load() != 1 || backup.Load() != 0 {
+		t.Errorf("Ответ 4xx не должен передаваться резервному серверу: основной %d, резервный %d", primary.Load(), backup.Load())
+	}
+}
+
+func TestUploadDownload_Integration(t *testing.T) {
+	uploadDir := t.TempDir()
+	uploads := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir: uploadDir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	mux := http.NewServeMux()
+	mux.Handle("/upload", uploads)
+	mux.Handle("/download/", http.StripPrefix("/download/", http.FileServer(http.Dir(uploadDir))))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	// Имя с пробелом сервер сохраняет под очищенным именем
+	original := make([]byte, 1024*1024)
+	rand.New(rand.NewSource(1)).Read(original)
+	testFile := filepath.Join(t.TempDir(), "report 2025.bin")
+	if err := os.WriteFile(testFile, original, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	httpClient := NewHTTPClient(30 * time.Second)
+	if err := httpClient.UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	downloaded := filepath.Join(t.TempDir(), "downloaded.bin")
+	if _, err := httpClient.DownloadFile(context.Background(), ts.URL+"/download/report_2025.bin", downloaded); err != nil {
+		t.Fatalf("Ошибка скачивания: %v", err)
+	}
+
+	content, err := os.ReadFile(downloaded)
+	if err != nil {
+		t.Fatalf("Ошибка чтения скачанного файла: %v", err)
+	}
+	if got, want := sha256.Sum256(content), sha256.Sum256(original); got != want {
+		t.Errorf("SHA-256 скачанного файла %x не совпадает с исходным %x", got, want)
+	}
+}
+
+func TestUploadMultipleFiles_RaceCondition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	var files []string
+	for i := 0; i < 3; i++ {
+		path := filepath.Join(dir, "file"+strconv.Itoa(i)+".bin")
+		if err := os.WriteFile(path, make([]byte, 32*1024), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+		files = append(files, path)
+	}
+
+	config := DefaultConfig()
+	config.BufferSize = 4 * 1024
+	config.MaxConcurrency = 8
+	httpClient := NewHTTPClientWithConfig(config)
+
+	// Общий callback без синхронизации: клиент сериализует вызовы, и детектор
+	// гонок (go test -race) не должен находить конфликтов
+	calls := 0
+	var lastBytes int64
+	progress := func(bytesTransferred, totalBytes int64, percentage float64) {
+		calls++
+		lastBytes = bytesTransferred
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- httpClient.UploadMultipleFiles(context.Background(), files, server.URL, progress)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Ошибка загрузки: %v", err)
+		}
+	}
+	if calls < 20*len(files) || lastBytes == 0 {
+		t.Errorf("Неожиданные вызовы callback: %d, последний прогресс %d байт", calls, lastBytes)
+	}
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrContentTypeMismatch возвращается, если тип содержимого, определенный сервером,
+// не совпал с UploadOptions.ExpectedContentType. Повтор не поможет, поэтому ошибка постоянная
+var ErrContentTypeMismatch = errors.New("тип содержимого файла не совпадает с ожидаемым")
+
+// checkContentType сравнивает тип из ответа сервера с ожидаемым без учета параметров
+// и регистра. Пустой тип означает, что сервер его не определял, и проверка пропускается
+func checkContentType(actual, expected string) *UploadError {
+	if actual == "" {
+		return nil
+	}
+	if mediaType(actual) != mediaType(expected) {
+		return newUploadError("ошибка проверки файла",
+			fmt.Errorf("%w: сервер определил %s, ожидался %s", ErrContentTypeMismatch, actual, expected))
+	}
+	return nil
+}
+
+// mediaType возвращает тип без параметров в нижнем регистре
+func mediaType(contentType string) string {
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		return parsed
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
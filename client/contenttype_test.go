@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadFileWithOptions_ExpectedContentType(t *testing.T) {
+	handler := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend:           server.NewMemoryStorageBackend(),
+		DetectContentType: true,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	textFile := filepath.Join(dir, "notes.bin")
+	binaryFile := filepath.Join(dir, "data.bin")
+	if err := os.WriteFile(textFile, []byte("просто текст"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	if err := os.WriteFile(binaryFile, []byte{0x00, 0x01, 0x02, 0xff}, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.RetryAttempts = 2
+	config.RetryDelay = time.Millisecond
+	httpClient := NewHTTPClientWithConfig(config)
+	ctx := context.Background()
+
+	// Текст вместо двоичного файла: ошибка без повторов
+	err := httpClient.UploadFileWithOptions(ctx, textFile, ts.URL+"/upload", UploadOptions{ExpectedContentType: "application/octet-stream"}, nil)
+	if !errors.Is(err, ErrContentTypeMismatch) {
+		t.Fatalf("Ожидалась ошибка ErrContentTypeMismatch, получено %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Несовпадение типа не должно повторяться: %d запросов", n)
+	}
+
+	if err := httpClient.UploadFileWithOptions(ctx, binaryFile, ts.URL+"/upload", UploadOptions{ExpectedContentType: "application/octet-stream"}, nil); err != nil {
+		t.Errorf("Ошибка загрузки двоичного файла: %v", err)
+	}
+
+	// Параметры типа (charset) не сравниваются
+	if err := httpClient.UploadFileWithOptions(ctx, textFile, ts.URL+"/upload", UploadOptions{ExpectedContentType: "Text/Plain"}, nil); err != nil {
+		t.Errorf("Ошибка загрузки текстового файла: %v", err)
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	tests := []struct {
+		actual, expected string
+		wantErr          bool
+	}{
+		{"image/png", "image/png", false},
+		{"text/plain; charset=utf-8", "text/plain", false},
+		{"", "application/octet-stream", false}, // сервер тип не определял
+		{"text/plain; charset=utf-8", "application/octet-stream", true},
+	}
+	for _, tt := range tests {
+		if err := checkContentType(tt.actual, tt.expected); (err != nil) != tt.wantErr {
+			t.Errorf("checkContentType(%q, %q) = %v", tt.actual, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// DefaultCorrelationIDHeader заголовок с идентификатором корреляции загрузки,
+// если ClientConfig.CorrelationIDHeader не задан
+const DefaultCorrelationIDHeader = "X-Correlation-ID"
+
+// correlationIDHeader возвращает заголовок идентификатора корреляции с учетом значения по умолчанию
+func (c *HTTPClient) correlationIDHeader() string {
+	if c.config.CorrelationIDHeader != "" {
+		return c.config.CorrelationIDHeader
+	}
+	return DefaultCorrelationIDHeader
+}
+
+// withCorrelationID возвращает задание с новым идентификатором корреляции. Идентификатор,
+// заданный в заголовках задания или в CustomHeaders, сохраняется. Задание получает
+// идентификатор один раз, поэтому он одинаков во всех попытках и на всех FallbackURLs
+func (c *HTTPClient) withCorrelationID(task uploadTask) uploadTask {
+	header := c.correlationIDHeader()
+	if task.headers.Get(header) != "" {
+		return task
+	}
+	for key := range c.config.CustomHeaders {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(header) {
+			return task
+		}
+	}
+
+	id, err := newCorrelationID()
+	if err != nil {
+		// Без идентификатора загрузка возможна, он нужен только для поиска в логах
+		return task
+	}
+	return task.withHeader(header, id)
+}
+
+// correlationID возвращает идентификатор корреляции задания для логов
+func (c *HTTPClient) correlationID(task uploadTask) string {
+	header := c.correlationIDHeader()
+	if id := task.headers.Get(header); id != "" {
+		return id
+	}
+	for key, value := range c.config.CustomHeaders {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(header) {
+			return value
+		}
+	}
+	return ""
+}
+
+// newCorrelationID возвращает случайный UUID версии 4
+func newCorrelationID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	id[6] = id[6]&0x0f | 0x40 // Версия 4
+	id[8] = id[8]&0x3f | 0x80 // Вариант RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUploadFile_CorrelationID(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		ids = append(ids, r.Header.Get(DefaultCorrelationIDHeader))
+		attempt := len(ids)
+		mu.Unlock()
+		// Первая попытка каждой загрузки завершается ошибкой сервера
+		if attempt%2 == 1 {
+			http.Error(w, "недоступен", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(testFile, []byte("data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+	httpClient := NewHTTPClientWithConfig(config)
+	for i := 0; i < 2; i++ {
+		if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+			t.Fatalf("Ошибка загрузки: %v", err)
+		}
+	}
+
+	if len(ids) != 4 || ids[0] == "" {
+		t.Fatalf("Ожидалось 4 запроса с идентификатором, получено %q", ids)
+	}
+	if ids[0] != ids[1] || ids[2] != ids[3] {
+		t.Errorf("Повторная попытка должна передавать тот же идентификатор: %q", ids)
+	}
+	if ids[0] == ids[2] {
+		t.Errorf("Разные загрузки должны получать разные идентификаторы: %q", ids)
+	}
+
+	// Идентификатор из CustomHeaders не заменяется
+	ids = nil
+	config = DefaultConfig()
+	config.CustomHeaders = map[string]string{"x-correlation-id": "batch-7"}
+	config.RetryDelay = time.Millisecond
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "batch-7" || ids[1] != "batch-7" {
+		t.Errorf("Ожидался идентификатор batch-7, получено %q", ids)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultEstimatedBandwidth пропускная способность для оценки времени передачи по умолчанию (10 MB/s)
+const defaultEstimatedBandwidth = 10 * 1024 * 1024
+
+// dryRunEstimate оценка передачи одного файла в режиме DryRun
+type dryRunEstimate struct {
+	size     int64
+	checksum string
+	duration time.Duration
+}
+
+// estimateUpload проверяет файл, вычисляет его SHA-256 и оценивает время передачи
+func (c *HTTPClient) estimateUpload(filePath string) (dryRunEstimate, error) {
+	if err := c.validateUploadFile(filePath); err != nil {
+		return dryRunEstimate{}, err
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return dryRunEstimate{}, fmt.Errorf("ошибка получения информации о файле: %w", err)
+	}
+
+	checksum, err := fileSHA256(filePath)
+	if err != nil {
+		return dryRunEstimate{}, err
+	}
+
+	return dryRunEstimate{
+		size:     fileInfo.Size(),
+		checksum: checksum,
+		duration: c.estimateDuration(fileInfo.Size()),
+	}, nil
+}
+
+// estimateDuration оценивает время передачи size байт с учетом MaxUploadBytesPerSec
+func (c *HTTPClient) estimateDuration(size int64) time.Duration {
+	bandwidth := c.config.EstimatedBandwidthBytesPerSec
+	if bandwidth <= 0 {
+		bandwidth = defaultEstimatedBandwidth
+	}
+	if limit := c.config.MaxUploadBytesPerSec; limit > 0 && limit < bandwidth {
+		bandwidth = limit
+	}
+	return time.Duration(float64(size) / float64(bandwidth) * float64(time.Second))
+}
+
+// dryRun выводит оценку передачи файла вместо загрузки
+func (c *HTTPClient) dryRun(task uploadTask, logger *slog.Logger) (dryRunEstimate, error) {
+	estimate, err := c.estimateUpload(task.filePath)
+	if err != nil {
+		logger.Error("Ошибка проверки файла", "error", err)
+		return dryRunEstimate{}, err
+	}
+
+	logger.Info("Пробный запуск: файл не отправлен",
+		"remote_name", task.formFileName(),
+		"size", formatBytes(estimate.size),
+		"sha256", estimate.checksum,
+		"estimated", estimate.duration.Round(time.Millisecond))
+	return estimate, nil
+}
+
+// dryRunTasks проверяет набор файлов и выводит суммарную оценку передачи.
+// Параллельные загрузки делят один канал, поэтому общее время
+// оценивается по суммарному размеру
+func (c *HTTPClient) dryRunTasks(tasks []uploadTask, serverURL string) ([]UploadResult, error) {
+	results := make([]UploadResult, len(tasks))
+	var totalSize int64
+	var allErrors []string
+
+	for i, task := range tasks {
+		startTime := time.Now()
+		estimate, err := c.dryRun(task, c.logger().With("file", task.filePath, "url", serverURL))
+		results[i] = UploadResult{
+			LocalPath:  task.filePath,
+			RemoteName: task.formFileName(),
+			Duration:   time.Since(startTime),
+			Err:        err,
+		}
+		if err != nil {
+			allErrors = append(allErrors, fmt.Sprintf("ошибка проверки файла %s: %v", task.filePath, err))
+			continue
+		}
+		totalSize += estimate.size
+	}
+
+	c.logger().Info("Пробный запуск: итоговая оценка",
+		"files", len(tasks)-len(allErrors),
+		"size", formatBytes(totalSize),
+		"estimated", c.estimateDuration(totalSize).Round(time.Millisecond))
+
+	if len(allErrors) > 0 {
+		return results, fmt.Errorf("ошибки при проверке файлов: %s", strings.Join(allErrors, "; "))
+	}
+	return results, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDryRun_NoRequests(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "a.bin"), filepath.Join(dir, "b.bin")}
+	for _, file := range files {
+		if err := os.WriteFile(file, make([]byte, 1024), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.DryRun = true
+	config.EstimatedBandwidthBytesPerSec = 1024
+	httpClient := NewHTTPClientWithConfig(config)
+
+	if err := httpClient.UploadFile(context.Background(), files[0], server.URL, nil); err != nil {
+		t.Fatalf("Ошибка пробной загрузки: %v", err)
+	}
+	if err := httpClient.UploadMultipleFiles(context.Background(), files, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка пробной пакетной загрузки: %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("В режиме DryRun отправлено запросов: %d", requests.Load())
+	}
+
+	// Ошибки файлов обнаруживаются и в пробном режиме
+	missing := append(files, filepath.Join(dir, "missing.bin"))
+	if err := httpClient.UploadMultipleFiles(context.Background(), missing, server.URL, nil); err == nil {
+		t.Error("Ожидалась ошибка для несуществующего файла")
+	}
+}
+
+func TestEstimateDuration(t *testing.T) {
+	config := DefaultConfig()
+	config.EstimatedBandwidthBytesPerSec = 1024 * 1024
+	httpClient := NewHTTPClientWithConfig(config)
+
+	if got := httpClient.estimateDuration(2 * 1024 * 1024); got != 2*time.Second {
+		t.Errorf("Неверная оценка: %v", got)
+	}
+
+	config.EstimatedBandwidthBytesPerSec = 0
+	if got := httpClient.estimateDuration(defaultEstimatedBandwidth); got != time.Second {
+		t.Errorf("Неверная оценка для пропускной способности по умолчанию: %v", got)
+	}
+
+	// Ограничение скорости отправки ниже оценки пропускной способности
+	config.MaxUploadBytesPerSec = 1024 * 1024
+	if got := httpClient.estimateDuration(2 * 1024 * 1024); got != 2*time.Second {
+		t.Errorf("Неверная оценка с ограничением скорости: %v", got)
+	}
+}
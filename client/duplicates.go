@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// duplicateKey возвращает отпечаток файла для SkipDuplicates: FNV-64a от
+// абсолютного пути без символических ссылок, размера и времени изменения.
+// Содержимое не читается, поэтому одинаковые по содержимому, но разные файлы
+// дубликатами не считаются. ok равно false, если файл недоступен
+func duplicateKey(filePath string) (key uint64, ok bool) {
+	resolved, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return 0, false
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return 0, false
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return 0, false
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(resolved))
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], uint64(info.Size()))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(info.ModTime().UnixNano()))
+	hash.Write(buf[:])
+	return hash.Sum64(), true
+}
+
+// duplicateFilter отмечает файлы пакета, уже поставленные в загрузку
+type duplicateFilter struct {
+	seen sync.Map // Отпечаток файла -> путь первого вхождения
+}
+
+// firstSeen возвращает путь первого вхождения файла в пакет и true, если файл
+// уже встречался. Недоступные файлы дубликатами не считаются: ошибку сообщит загрузка
+func (f *duplicateFilter) firstSeen(filePath string) (string, bool) {
+	key, ok := duplicateKey(filePath)
+	if !ok {
+		return "", false
+	}
+	first, loaded := f.seen.LoadOrStore(key, filePath)
+	return first.(string), loaded
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUploadTasks_SkipDuplicates(t *testing.T) {
+	var uploads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	original := filepath.Join(dir, "data.bin")
+	other := filepath.Join(dir, "other.bin")
+	link := filepath.Join(dir, "link.bin")
+	if err := os.WriteFile(original, []byte("data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	if err := os.WriteFile(other, []byte("data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	if err := os.Symlink(original, link); err != nil {
+		t.Skipf("Символические ссылки не поддерживаются: %v", err)
+	}
+
+	files := []string{original, link, filepath.Join(dir, ".", "data.bin"), other}
+	tasks := make([]uploadTask, 0, len(files))
+	for _, file := range files {
+		tasks = append(tasks, uploadTask{filePath: file})
+	}
+
+	config := DefaultConfig()
+	config.SkipDuplicates = true
+	results, err := NewHTTPClientWithConfig(config).uploadTasks(context.Background(), tasks, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Файл с тем же содержимым, но другим путем дубликатом не считается
+	skipped := []bool{false, true, true, false}
+	for i, result := range results {
+		if result.Skipped != skipped[i] {
+			t.Errorf("%s: ожидался Skipped=%v", result.LocalPath, skipped[i])
+		}
+	}
+	if got := uploads.Load(); got != 2 {
+		t.Errorf("Ожидалось 2 загрузки, получено %d", got)
+	}
+
+	// Без SkipDuplicates отправляются все файлы
+	uploads.Store(0)
+	if err := NewHTTPClient(0).UploadMultipleFiles(context.Background(), files, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if got := uploads.Load(); got != int32(len(files)) {
+		t.Errorf("Ожидалось %d загрузок, получено %d", len(files), got)
+	}
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptionKeySize размер ключа AES-256 в байтах
+const EncryptionKeySize = 32
+
+// encryptionChunkSize размер блока открытого текста, шифруемого отдельно.
+// GCM не является потоковым режимом, поэтому файл делится на блоки,
+// каждый из которых аутентифицируется своим тегом
+const encryptionChunkSize = 64 * 1024
+
+// Параметры scrypt для DeriveKey
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// DeriveKey получает 32-байтный ключ AES-256 из пароля и соли с помощью scrypt
+func DeriveKey(passphrase, salt string) []byte {
+	// scrypt возвращает ошибку только при некорректных параметрах, а они постоянны
+	key, _ := scrypt.Key([]byte(passphrase), []byte(salt), scryptN, scryptR, scryptP, EncryptionKeySize)
+	return key
+}
+
+// newGCM создает AES-256-GCM с проверкой длины ключа
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("ключ шифрования должен быть длиной %d байт, получено %d", EncryptionKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания шифра: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce возвращает nonce блока: последние 8 байт базового nonce
+// складываются по модулю 2 с номером блока
+func chunkNonce(base []byte, counter uint64) []byte {
+	nonce := make([]byte, len(base))
+	copy(nonce, base)
+	tail := nonce[len(nonce)-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)^counter)
+	return nonce
+}
+
+// chunkAAD дополнительные данные блока: последний блок помечается отдельно,
+// чтобы обрезка шифротекста по границе блока обнаруживалась при расшифровке
+func chunkAAD(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptWriter шифрует поток блоками AES-256-GCM. Формат: случайный
+// 12-байтный nonce, затем блоки шифротекста по encryptionChunkSize байт
+// открытого текста с тегом. Последний блок всегда короче полного
+// (возможно, пустой) и записывается при Close
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+	buf     []byte
+}
+
+// newEncryptWriter создает шифрующий writer и записывает nonce в начало потока
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("ошибка генерации nonce: %w", err)
+	}
+	if _, err := w.Write(nonce); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:     w,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, 0, encryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[len(e.buf):encryptionChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close шифрует и записывает последний блок
+func (e *encryptWriter) Close() error {
+	return e.flush(true)
+}
+
+// flush шифрует накопленный открытый текст и записывает блок
+func (e *encryptWriter) flush(final bool) error {
+	sealed := e.aead.Seal(e.buf[:0], chunkNonce(e.nonce, e.counter), e.buf, chunkAAD(final))
+	e.counter++
+	e.buf = e.buf[:0]
+
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// decryptReader расшифровывает поток, записанный encryptWriter
+type decryptReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint64
+	buf     []byte
+	plain   []byte // Расшифрованные, но еще не прочитанные данные
+	done    bool
+}
+
+// newDecryptReader создает расшифровывающий reader, читая nonce из начала потока
+func newDecryptReader(r io.Reader, key []byte) (*decryptReader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, fmt.Errorf("ошибка чтения nonce: %w", err)
+	}
+
+	return &decryptReader{
+		r:     r,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, encryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		n, err := io.ReadFull(d.r, d.buf)
+		switch err {
+		case nil:
+			// Полный блок никогда не бывает последним
+		case io.ErrUnexpectedEOF:
+			d.done = true
+		case io.EOF:
+			return 0, fmt.Errorf("зашифрованные данные обрезаны")
+		default:
+			return 0, err
+		}
+
+		plain, err := d.aead.Open(d.buf[:0], chunkNonce(d.nonce, d.counter), d.buf[:n], chunkAAD(d.done))
+		if err != nil {
+			return 0, fmt.Errorf("ошибка расшифровки блока %d: %w", d.counter, err)
+		}
+		d.counter++
+		d.plain = plain
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// DownloadFile скачивает файл по URL и сохраняет его в destPath. Если задан
+// ClientConfig.EncryptionKey, содержимое расшифровывается; файл в destPath
+// появляется только после успешной проверки всех блоков. Возвращает метаданные
+// загрузки из GET {fileURL}/meta или nil, если сервер их не хранит
+func (c *HTTPClient) DownloadFile(ctx context.Context, fileURL, destPath string) (map[string]string, error) {
+	if c.initErr != nil {
+		return nil, c.initErr
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var src io.Reader = resp.Body
+	if len(c.config.EncryptionKey) > 0 {
+		src, err = newDecryptReader(resp.Body, c.config.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Пишем во временный файл рядом с целевым, чтобы не оставить
+	// частично расшифрованные данные при ошибке
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".download-*")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	buffer := c.getBuffer()
+	defer c.putBuffer(buffer)
+	if _, err := io.CopyBuffer(tmp, src, *buffer); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("ошибка получения файла: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка записи файла: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), destPath); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+
+	c.logger().Info("Файл скачан", "url", fileURL, "path", destPath)
+	return c.fetchMetadata(ctx, fileURL)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEncryptDecrypt_RoundTrip(t *testing.T) {
+	key := DeriveKey("passphrase", "salt")
+	if len(key) != EncryptionKeySize {
+		t.Fatalf("Неверная длина ключа: %d", len(key))
+	}
+
+	// Размеры вокруг границы блока проверяют обработку последнего блока
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, 3*encryptionChunkSize + 7} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+
+		var encrypted bytes.Buffer
+		w, err := newEncryptWriter(&encrypted, key)
+		if err != nil {
+			t.Fatalf("Ошибка создания шифратора: %v", err)
+		}
+		w.Write(plain)
+		if err := w.Close(); err != nil {
+			t.Fatalf("Ошибка завершения шифрования: %v", err)
+		}
+
+		r, err := newDecryptReader(bytes.NewReader(encrypted.Bytes()), key)
+		if err != nil {
+			t.Fatalf("Ошибка создания дешифратора: %v", err)
+		}
+		decrypted, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Ошибка расшифровки %d байт: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plain) {
+			t.Errorf("Расшифрованные данные размером %d не совпадают с исходными", size)
+		}
+
+		// Отбрасывание последнего блока должно обнаруживаться
+		if size >= encryptionChunkSize {
+			truncated := encrypted.Bytes()[:12+encryptionChunkSize+16]
+			r, _ := newDecryptReader(bytes.NewReader(truncated), key)
+			if _, err := io.ReadAll(r); err == nil {
+				t.Errorf("Ожидалась ошибка для обрезанных данных размером %d", size)
+			}
+		}
+	}
+}
+
+func TestEncryptedUploadAndDownload(t *testing.T) {
+	plain := make([]byte, 200*1024)
+	rand.Read(plain)
+	testFile := filepath.Join(t.TempDir(), "secret.bin")
+	if err := os.WriteFile(testFile, plain, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	// Сервер хранит тело файла как есть и отдает его по GET
+	var stored []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write(stored)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stored, _ = io.ReadAll(file)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Timeout = 10 * time.Second
+	config.EncryptionKey = DeriveKey("passphrase", "salt")
+	httpClient := NewHTTPClientWithConfig(config)
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if bytes.Contains(stored, plain[:1024]) {
+		t.Fatal("Сервер получил открытый текст")
+	}
+
+	destPath := filepath.Join(t.TempDir(), "restored.bin")
+	if _, err := httpClient.DownloadFile(context.Background(), server.URL, destPath); err != nil {
+		t.Fatalf("Ошибка скачивания: %v", err)
+	}
+	restored, _ := os.ReadFile(destPath)
+	if !bytes.Equal(restored, plain) {
+		t.Error("Скачанный файл не совпадает с исходным")
+	}
+
+	// С неверным ключом файл не должен появиться
+	config2 := *config
+	config2.EncryptionKey = DeriveKey("wrong", "salt")
+	wrongPath := filepath.Join(t.TempDir(), "wrong.bin")
+	if _, err := NewHTTPClientWithConfig(&config2).DownloadFile(context.Background(), server.URL, wrongPath); err == nil {
+		t.Error("Ожидалась ошибка расшифровки с неверным ключом")
+	}
+	if _, err := os.Stat(wrongPath); !os.IsNotExist(err) {
+		t.Error("Файл не должен создаваться при ошибке расшифровки")
+	}
+}
+
+func TestEncryptionKey_Validation(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "key.bin")
+	os.WriteFile(testFile, []byte("data"), 0644)
+
+	config := DefaultConfig()
+	config.EncryptionKey = []byte("short")
+	err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, "http://127.0.0.1:1", nil)
+	if err == nil {
+		t.Error("Ожидалась ошибка для ключа неверной длины")
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadError ошибка одной попытки загрузки
+type UploadError struct {
+	Code int    // HTTP-статус ответа сервера, 0 если ответ не получен
+	Msg  string // Описание ошибки
+	Err  error  // Исходная ошибка, если есть
+
+	// RetryAfter ожидание перед повтором из заголовка Retry-After ответа 429 или 503
+	// (0, если сервер его не указал или разрешил повтор сразу — тогда действует RetryDelay)
+	RetryAfter time.Duration
+}
+
+// newUploadError создает ошибку загрузки без HTTP-статуса
+func newUploadError(msg string, err error) *UploadError {
+	return &UploadError{Msg: msg, Err: err}
+}
+
+func (e *UploadError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// isPermanentError определяет, является ли ошибка постоянной (не требует retry).
+// Постоянными считаются ответы сервера 4xx (в том числе 413 при превышении
+// лимита размера), кроме 429 Too Many Requests, и несовпадение типа содержимого;
+// ответы 5xx, 429 и сетевые ошибки повторяются
+func isPermanentError(err error) bool {
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) {
+		return false
+	}
+	if errors.Is(err, ErrContentTypeMismatch) {
+		return true
+	}
+
+	return uploadErr.Code >= 400 && uploadErr.Code < 500 &&
+		uploadErr.Code != http.StatusTooManyRequests
+}
+
+// shouldRetry определяет, нужно ли повторить загрузку после неудачной попытки attempt.
+// Используется ClientConfig.RetryCondition, а если он не задан — isPermanentError
+func (c *HTTPClient) shouldRetry(attempt int, err error) bool {
+	if c.config.RetryCondition != nil {
+		return c.config.RetryCondition(attempt, err)
+	}
+	return !isPermanentError(err)
+}
+
+// responseError создает ошибку для неуспешного ответа сервера
+func responseError(resp *http.Response) *UploadError {
+	body, _ := io.ReadAll(resp.Body)
+	uploadErr := &UploadError{
+		Code: resp.StatusCode,
+		Msg:  fmt.Sprintf("сервер вернул ошибку: %s, статус: %d, тело: %s", resp.Status, resp.StatusCode, string(body)),
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		uploadErr.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return uploadErr
+}
+
+// parseRetryAfter разбирает значение заголовка Retry-After (RFC 7231, раздел 7.1.3):
+// число секунд или HTTP-дату. Дата в прошлом означает повтор без ожидания
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(maxRetryAfter/time.Second) {
+			return maxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// maxRetryAfter предел значения Retry-After, защищающий от переполнения Duration
+const maxRetryAfter = 24 * time.Hour
+
+// retryDelay возвращает паузу перед повтором после ошибки err: время из заголовка
+// Retry-After, но не больше MaxRetryDelay, а без заголовка — RetryDelay
+func (c *HTTPClient) retryDelay(err error) time.Duration {
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.RetryAfter <= 0 {
+		return c.config.RetryDelay
+	}
+	if c.config.MaxRetryDelay > 0 && uploadErr.RetryAfter > c.config.MaxRetryDelay {
+		return c.config.MaxRetryDelay
+	}
+	return uploadErr.RetryAfter
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsPermanentError_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"400", &UploadError{Code: http.StatusBadRequest}, true},
+		{"401", &UploadError{Code: http.StatusUnauthorized}, true},
+		{"413", &UploadError{Code: http.StatusRequestEntityTooLarge}, true},
+		{"429", &UploadError{Code: http.StatusTooManyRequests}, false},
+		{"500", &UploadError{Code: http.StatusInternalServerError}, false},
+		{"503", &UploadError{Code: http.StatusServiceUnavailable}, false},
+		{"network", newUploadError("ошибка выполнения HTTP запроса", errors.New("connection refused")), false},
+		{"wrapped 404", fmt.Errorf("попытка 1: %w", &UploadError{Code: http.StatusNotFound}), true},
+		{"plain", errors.New("файл пустой"), false},
+	}
+
+	for _, test := range tests {
+		if result := isPermanentError(test.err); result != test.expected {
+			t.Errorf("%s: ожидалось %v, получено %v", test.name, test.expected, result)
+		}
+	}
+}
+
+// TestIsPermanentError фиксирует, что ошибка классифицируется по типу *UploadError
+// и коду ответа, а не по тексту: обертка через %w сохраняет классификацию, а текст,
+// похожий на ответ 4xx, без UploadError постоянной ошибкой не считается. При изменении
+// способа классификации тест нужно обновить намеренно
+func TestIsPermanentError(t *testing.T) {
+	badRequest := responseError(&http.Response{
+		Status:     "400 Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Body:       io.NopCloser(strings.NewReader("Ошибка получения файла")),
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"ответ 400", badRequest, true},
+		{"обертка %w", fmt.Errorf("ошибка загрузки файла: %w", badRequest), true},
+		{"двойная обертка %w", fmt.Errorf("пакет: %w", fmt.Errorf("файл: %w", badRequest)), true},
+		{"errors.Join", errors.Join(errors.New("первая"), badRequest), true},
+		{"обертка %v теряет тип", fmt.Errorf("ошибка загрузки файла: %v", badRequest), false},
+		{"текст ответа без типа", errors.New(badRequest.Error()), false},
+		{"неизвестный текст", errors.New("что-то пошло не так"), false},
+		{"отмена контекста", fmt.Errorf("попытка: %w", context.Canceled), false},
+		{"сетевая ошибка с причиной 4xx", newUploadError("ошибка выполнения HTTP запроса", &UploadError{Code: http.StatusForbidden}), false},
+		{"несовпадение типа содержимого", checkContentType("text/plain", "image/png"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isPermanentError(tt.err); result != tt.expected {
+				t.Errorf("isPermanentError(%v) = %v, ожидалось %v", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUploadFile_RetryByStatus(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "retry.bin")
+	if err := os.WriteFile(testFile, []byte("retry"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	tests := []struct {
+		status   int
+		expected int32
+	}{
+		{http.StatusBadRequest, 1},
+		{http.StatusServiceUnavailable, 3},
+	}
+
+	for _, test := range tests {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(test.status)
+		}))
+
+		config := DefaultConfig()
+		config.RetryAttempts = 2
+		config.RetryDelay = time.Millisecond
+		httpClient := NewHTTPClientWithConfig(config)
+
+		err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil)
+		server.Close()
+
+		var uploadErr *UploadError
+		if !errors.As(err, &uploadErr) || uploadErr.Code != test.status {
+			t.Errorf("Статус %d: ожидалась UploadError с кодом статуса, получена %v", test.status, err)
+		}
+		if requests != test.expected {
+			t.Errorf("Статус %d: ожидалось %d запросов, получено %d", test.status, test.expected, requests)
+		}
+	}
+}
+
+func TestUploadFile_RetryCondition(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "retry.bin")
+	if err := os.WriteFile(testFile, []byte("retry"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	// Пользовательское условие заменяет правило isPermanentError
+	retryOn503 := func(attempt int, err error) bool {
+		var uploadErr *UploadError
+		return errors.As(err, &uploadErr) && uploadErr.Code == http.StatusServiceUnavailable
+	}
+	retryAll := func(attempt int, err error) bool { return true }
+
+	tests := []struct {
+		name      string
+		status    int
+		condition func(attempt int, err error) bool
+		expected  int32
+	}{
+		{"503 повторяется", http.StatusServiceUnavailable, retryOn503, 3},
+		{"500 не повторяется", http.StatusInternalServerError, retryOn503, 1},
+		{"400 повторяется", http.StatusBadRequest, retryAll, 3},
+	}
+
+	for _, test := range tests {
+		var requests int32
+		var attempts []int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(test.status)
+		}))
+
+		config := DefaultConfig()
+		config.RetryAttempts = 2
+		config.RetryDelay = time.Millisecond
+		config.RetryCondition = func(attempt int, err error) bool {
+			attempts = append(attempts, attempt)
+			return test.condition(attempt, err)
+		}
+		httpClient := NewHTTPClientWithConfig(config)
+
+		if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err == nil {
+			t.Errorf("%s: ожидалась ошибка", test.name)
+		}
+		server.Close()
+
+		if requests != test.expected {
+			t.Errorf("%s: ожидалось %d запросов, получено %d", test.name, test.expected, requests)
+		}
+		if len(attempts) == 0 || attempts[0] != 1 {
+			t.Errorf("%s: номера попыток должны начинаться с 1, получено %v", test.name, attempts)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Monday, 01-Jan-24 12:01:00 GMT", time.Minute, true},
+		{"Mon Jan  1 12:00:05 2024", 5 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"99999999999999", maxRetryAfter, true},
+		{"-5", 0, false},
+		{"1.5", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, test := range tests {
+		got, ok := parseRetryAfter(test.value, now)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%q: ожидалось (%v, %v), получено (%v, %v)", test.value, test.want, test.ok, got, ok)
+		}
+	}
+}
+
+func TestUploadFile_RetryAfter(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "limited.bin")
+	if err := os.WriteFile(testFile, []byte("limited"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		dateIn     time.Duration // Если задано, Retry-After — HTTP-дата через dateIn от ответа
+		maxDelay   time.Duration
+		minWait    time.Duration
+		maxWait    time.Duration
+	}{
+		// Без заголовка действует RetryDelay
+		{"без заголовка", "", 0, time.Minute, 200 * time.Millisecond, 2 * time.Second},
+		{"секунды", "1", 0, time.Minute, time.Second, 3 * time.Second},
+		{"ограничение MaxRetryDelay", "3600", 0, 50 * time.Millisecond, 50 * time.Millisecond, time.Second},
+		// HTTP-дата с точностью до секунды: ожидание от 2 до 3 секунд
+		{"HTTP-дата", "", 3 * time.Second, time.Minute, time.Second, 4 * time.Second},
+	}
+
+	for _, test := range tests {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			if atomic.AddInt32(&requests, 1) == 1 {
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				if test.dateIn > 0 {
+					w.Header().Set("Retry-After", time.Now().Add(test.dateIn).UTC().Format(http.TimeFormat))
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+
+		config := DefaultConfig()
+		config.RetryAttempts = 1
+		config.RetryDelay = 200 * time.Millisecond
+		config.MaxRetryDelay = test.maxDelay
+		httpClient := NewHTTPClientWithConfig(config)
+
+		start := time.Now()
+		err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil)
+		elapsed := time.Since(start)
+		server.Close()
+
+		if err != nil || requests != 2 {
+			t.Errorf("%s: ожидалась успешная повторная попытка, получено %v после %d запросов", test.name, err, requests)
+		}
+		if elapsed < test.minWait || elapsed > test.maxWait {
+			t.Errorf("%s: ожидание %v вне диапазона [%v, %v]", test.name, elapsed, test.minWait, test.maxWait)
+		}
+	}
+}
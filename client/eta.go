@@ -0,0 +1,106 @@
+package client
+
+import "time"
+
+// defaultETAWarmupPeriod период прогрева оценки оставшегося времени по умолчанию
+const defaultETAWarmupPeriod = 3 * time.Second
+
+// defaultEMAAlpha коэффициент сглаживания скорости для оценки оставшегося времени по умолчанию
+const defaultEMAAlpha = 0.1
+
+// speedWindow интервал, по которому усредняется скорость передачи для оценки оставшегося времени
+const speedWindow = 5 * time.Second
+
+// speedSampleInterval минимальный интервал между сохраняемыми замерами скорости
+const speedSampleInterval = 100 * time.Millisecond
+
+// speedSample число переданных байт в момент времени
+type speedSample struct {
+	at    time.Time
+	bytes int64
+}
+
+// speedMeter оценивает скорость передачи по скользящему окну speedWindow.
+// Мгновенная скорость в начале передачи занижена из-за медленного старта TCP,
+// поэтому оценка оставшегося времени не выдается до истечения warmup. После прогрева
+// скорость окна дополнительно сглаживается ETASmoother, по ней и оценивается время
+type speedMeter struct {
+	start    time.Time
+	warmup   time.Duration
+	samples  []speedSample
+	smoother ETASmoother
+	smoothed float64
+}
+
+func newSpeedMeter(start time.Time, warmup time.Duration, alpha float64) *speedMeter {
+	return &speedMeter{start: start, warmup: warmup, samples: []speedSample{{at: start}}, smoother: ETASmoother{Alpha: alpha}}
+}
+
+// ETASmoother сглаживает скорость передачи экспоненциальным скользящим средним,
+// чтобы оценка оставшегося времени не скакала вслед за мгновенной скоростью
+type ETASmoother struct {
+	Alpha float64 // Вес нового замера от 0 до 1 (0 — 0.1); чем меньше, тем плавнее оценка
+
+	value   float64
+	samples int
+}
+
+// Update учитывает замер скорости в байтах в секунду и возвращает сглаженную скорость.
+// Пока замеров меньше 1/Alpha, возвращается их среднее: иначе первый, случайный замер
+// определял бы оценку еще долго, и она не устанавливалась бы в первые секунды передачи
+func (s *ETASmoother) Update(bytesPerSec float64) float64 {
+	alpha := s.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = defaultEMAAlpha
+	}
+	s.samples++
+	s.value += max(alpha, 1/float64(s.samples)) * (bytesPerSec - s.value)
+	return s.value
+}
+
+// add добавляет замер и отбрасывает замеры, вышедшие за окно. Самый старый
+// замер за пределами окна сохраняется, чтобы окно было покрыто целиком.
+// Callback прогресса может вызываться на каждое чтение, поэтому замеры чаще
+// speedSampleInterval заменяют последний
+func (m *speedMeter) add(now time.Time, bytes int64) {
+	if last := len(m.samples) - 1; last > 0 && now.Sub(m.samples[last-1].at) < speedSampleInterval {
+		m.samples[last] = speedSample{at: now, bytes: bytes}
+	} else {
+		m.samples = append(m.samples, speedSample{at: now, bytes: bytes})
+	}
+	cut := 0
+	for cut+1 < len(m.samples) && now.Sub(m.samples[cut+1].at) >= speedWindow {
+		cut++
+	}
+	m.samples = m.samples[cut:]
+
+	// До конца прогрева скорость занижена и только сместила бы среднее
+	if now.Sub(m.start) >= m.warmup {
+		if speed := m.speed(); speed > 0 {
+			m.smoothed = m.smoother.Update(speed)
+		}
+	}
+}
+
+// speed возвращает среднюю скорость в байтах в секунду за окно
+func (m *speedMeter) speed() float64 {
+	first, last := m.samples[0], m.samples[len(m.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.bytes-first.bytes) / elapsed
+}
+
+// eta возвращает оценку оставшегося времени по сглаженной скорости; false, если период прогрева
+// не истек, скорость неизвестна или размер передачи не известен
+func (m *speedMeter) eta(now time.Time, bytes, total int64) (time.Duration, bool) {
+	if now.Sub(m.start) < m.warmup {
+		return 0, false
+	}
+	speed := m.smoothed
+	if speed <= 0 || total <= bytes {
+		return 0, false
+	}
+	return time.Duration(float64(total-bytes) / speed * float64(time.Second)), true
+}
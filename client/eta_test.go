@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSpeedMeter_CoalescesSamples(t *testing.T) {
+	start := time.Now()
+	meter := newSpeedMeter(start, 0, defaultEMAAlpha)
+
+	// Частые вызовы callback не накапливают замеры
+	for i := 1; i <= 1000; i++ {
+		meter.add(start.Add(time.Duration(i)*time.Millisecond), int64(i)*1000)
+	}
+	if len(meter.samples) > int(time.Second/speedSampleInterval)+2 {
+		t.Errorf("Слишком много замеров: %d", len(meter.samples))
+	}
+	if speed := meter.speed(); speed != 1000*1000 {
+		t.Errorf("Ожидалась скорость 1000000 байт/с, получено %.1f", speed)
+	}
+}
+
+func TestJSONProgress_ETAWarmup(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		warmup  time.Duration
+		wantETA bool
+	}{
+		{"прогрев", time.Hour, false},
+		{"без прогрева", 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			progress := jsonProgress(&output, "file.bin", tc.warmup, defaultEMAAlpha, nil)
+			progress(0, 1000, 0)
+			time.Sleep(2 * speedSampleInterval)
+			progress(100, 1000, 10)
+
+			var last jsonProgressLine
+			for decoder := json.NewDecoder(&output); decoder.More(); {
+				if err := decoder.Decode(&last); err != nil {
+					t.Fatalf("Ошибка разбора строки прогресса: %v", err)
+				}
+			}
+			if (last.ETASec > 0) != tc.wantETA {
+				t.Errorf("Неверная оценка оставшегося времени: %v", last.ETASec)
+			}
+		})
+	}
+}
+
+func TestSpeedMeter_SmoothsAfterWarmup(t *testing.T) {
+	start := time.Now()
+	meter := newSpeedMeter(start, 2*time.Second, 0.5)
+
+	// Замеры прогрева не влияют на сглаженную скорость
+	meter.add(start.Add(time.Second), 100)
+	if meter.smoothed != 0 {
+		t.Errorf("Скорость во время прогрева не должна сглаживаться: %.1f", meter.smoothed)
+	}
+
+	meter.add(start.Add(2*time.Second), 2100)
+	meter.add(start.Add(3*time.Second), 5100)
+	eta, ok := meter.eta(start.Add(3*time.Second), 5100, 10000)
+	if !ok {
+		t.Fatal("Ожидалась оценка оставшегося времени")
+	}
+	// Окно: 1050 и 1700 байт/с, сглаженная скорость 1375 байт/с
+	remaining, speed := 4900.0, 1375.0
+	if want := time.Duration(remaining / speed * float64(time.Second)); eta != want {
+		t.Errorf("Ожидалась оценка %v, получено %v", want, eta)
+	}
+}
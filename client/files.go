@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FileInfo сведения о файле, сохраненном на сервере
+type FileInfo struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Checksum string    `json:"checksum"` // SHA-256 в hex
+}
+
+// fileList ответ сервера на GET /files
+type fileList struct {
+	Files []FileInfo `json:"files"`
+	Total int        `json:"total"`
+}
+
+// ListFiles возвращает страницу списка файлов сервера (новые первыми).
+// serverURL — адрес сервера; путь URL заменяется на /files, поэтому
+// подходит и URL загрузки. Нумерация страниц начинается с 1
+func (c *HTTPClient) ListFiles(ctx context.Context, serverURL string, page, perPage int) ([]FileInfo, error) {
+	if c.initErr != nil {
+		return nil, c.initErr
+	}
+
+	listURL, err := endpointURL(serverURL, "/files")
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	listURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL.String(), nil)
+	if err != nil {
+		return nil, newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var list fileList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("ошибка разбора списка файлов: %w", err)
+	}
+	return list.Files, nil
+}
+
+// endpointURL заменяет путь в адресе сервера на path
+func endpointURL(serverURL, path string) (*url.URL, error) {
+	base, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный URL сервера: %w", err)
+	}
+	return base.ResolveReference(&url.URL{Path: path}), nil
+}
+
+// DeleteFile удаляет файл на сервере. serverURL — адрес сервера,
+// путь URL заменяется на /files/{filename}
+func (c *HTTPClient) DeleteFile(ctx context.Context, filename, serverURL string) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+
+	deleteURL, err := endpointURL(serverURL, "/files/"+filename)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, deleteURL.String(), nil)
+	if err != nil {
+		return newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError(resp)
+	}
+
+	c.logger().Info("Файл удален", "file", filename, "url", serverURL)
+	return nil
+}
+
+// fileChecksum ответ сервера на GET /files/{filename}/checksum
+type fileChecksum struct {
+	Checksum string `json:"checksum"`
+}
+
+// FileChecksum возвращает контрольную сумму файла на сервере в hex, не скачивая его.
+// algo — md5, sha1, sha256 или sha512 (пусто — sha256). serverURL — адрес сервера,
+// путь URL заменяется на /files/{filename}/checksum
+func (c *HTTPClient) FileChecksum(ctx context.Context, serverURL, filename, algo string) (string, error) {
+	if c.initErr != nil {
+		return "", c.initErr
+	}
+
+	checksumURL, err := endpointURL(serverURL, "/files/"+filename+"/checksum")
+	if err != nil {
+		return "", err
+	}
+	if algo != "" {
+		checksumURL.RawQuery = url.Values{"algo": {algo}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL.String(), nil)
+	if err != nil {
+		return "", newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", responseError(resp)
+	}
+
+	var sum fileChecksum
+	if err := json.NewDecoder(resp.Body).Decode(&sum); err != nil {
+		return "", fmt.Errorf("ошибка разбора контрольной суммы: %w", err)
+	}
+	return sum.Checksum, nil
+}
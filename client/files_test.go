@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+// newUploadServer запускает сервер загрузки с локальным хранилищем в uploadDir
+func newUploadServer(t *testing.T, config *server.ServerConfig) *httptest.Server {
+	t.Helper()
+
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	ts := httptest.NewServer(server.NewHTTPServerWithConfig(config).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestListFiles(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	testFile := filepath.Join(t.TempDir(), "listed.bin")
+	if err := os.WriteFile(testFile, []byte("listed"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	httpClient := NewHTTPClient(10 * time.Second)
+	if err := httpClient.UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Подходит и URL загрузки: путь заменяется на /files
+	files, err := httpClient.ListFiles(context.Background(), ts.URL+"/upload", 1, 10)
+	if err != nil {
+		t.Fatalf("Ошибка получения списка: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "listed.bin" || files[0].Size != 6 || files[0].Checksum == "" {
+		t.Errorf("Неверный список файлов: %+v", files)
+	}
+
+	if _, err := httpClient.ListFiles(context.Background(), ts.URL, 0, 10); err == nil {
+		t.Error("Ожидалась ошибка для некорректного номера страницы")
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir(), AllowDelete: true})
+
+	testFile := filepath.Join(t.TempDir(), "deleted.bin")
+	if err := os.WriteFile(testFile, []byte("deleted"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	ctx := context.Background()
+	httpClient := NewHTTPClient(10 * time.Second)
+	if err := httpClient.UploadFile(ctx, testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	files, err := httpClient.ListFiles(ctx, ts.URL, 1, 10)
+	if err != nil || len(files) != 1 || files[0].Name != "deleted.bin" {
+		t.Fatalf("Файл отсутствует в списке после загрузки: %+v, %v", files, err)
+	}
+
+	if err := httpClient.DeleteFile(ctx, "deleted.bin", ts.URL); err != nil {
+		t.Fatalf("Ошибка удаления: %v", err)
+	}
+
+	files, err = httpClient.ListFiles(ctx, ts.URL, 1, 10)
+	if err != nil || len(files) != 0 {
+		t.Errorf("Файл остался в списке после удаления: %+v, %v", files, err)
+	}
+
+	// Повторное удаление возвращает 404
+	var uploadErr *UploadError
+	err = httpClient.DeleteFile(ctx, "deleted.bin", ts.URL)
+	if !errors.As(err, &uploadErr) || uploadErr.Code != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка 404, получено: %v", err)
+	}
+}
+
+func TestDeleteFile_Disabled(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	var uploadErr *UploadError
+	err := NewHTTPClient(10*time.Second).DeleteFile(context.Background(), "any.bin", ts.URL)
+	if !errors.As(err, &uploadErr) || uploadErr.Code != http.StatusForbidden {
+		t.Errorf("Ожидалась ошибка 403, получено: %v", err)
+	}
+}
+
+func TestFileChecksum(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	content := []byte("audited")
+	testFile := filepath.Join(t.TempDir(), "audited.bin")
+	if err := os.WriteFile(testFile, content, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	ctx := context.Background()
+	httpClient := NewHTTPClient(10 * time.Second)
+	if err := httpClient.UploadFile(ctx, testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	sum, err := httpClient.FileChecksum(ctx, ts.URL, "audited.bin", "sha512")
+	want := sha512.Sum512(content)
+	if err != nil || sum != hex.EncodeToString(want[:]) {
+		t.Errorf("Неверная контрольная сумма: %s, %v", sum, err)
+	}
+
+	var uploadErr *UploadError
+	_, err = httpClient.FileChecksum(ctx, ts.URL, "missing.bin", "")
+	if !errors.As(err, &uploadErr) || uploadErr.Code != http.StatusNotFound {
+		t.Errorf("Ожидалась ошибка 404, получено: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// headerTransport добавляет заголовки ClientConfig.CustomHeaders в каждый запрос
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip реализует http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper не должен изменять исходный запрос
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// validateCustomHeaders проверяет пользовательские заголовки. Content-Type
+// задается клиентом (multipart/form-data) и не может быть переопределен
+func validateCustomHeaders(headers map[string]string) error {
+	for key := range headers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("пустое имя заголовка")
+		}
+		if http.CanonicalHeaderKey(key) == "Content-Type" {
+			return fmt.Errorf("заголовок Content-Type нельзя переопределить")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCustomHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var seen []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Clone())
+		mu.Unlock()
+		if r.URL.Path == "/files" {
+			w.Write([]byte(`{"files": []}`))
+		}
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "headers.bin")
+	if err := os.WriteFile(testFile, []byte("headers"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.CustomHeaders = map[string]string{"X-Correlation-ID": "abc-123", "x-api-version": "2"}
+	httpClient := NewHTTPClientWithConfig(config)
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if _, err := httpClient.ListFiles(context.Background(), server.URL, 1, 10); err != nil {
+		t.Fatalf("Ошибка получения списка: %v", err)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("Ожидалось 2 запроса, получено %d", len(seen))
+	}
+	for _, header := range seen {
+		if header.Get("X-Correlation-ID") != "abc-123" || header.Get("X-Api-Version") != "2" {
+			t.Errorf("Заголовки не переданы: %v", header)
+		}
+	}
+	if !strings.HasPrefix(seen[0].Get("Content-Type"), "multipart/form-data") {
+		t.Errorf("Content-Type загрузки изменен: %s", seen[0].Get("Content-Type"))
+	}
+}
+
+func TestCustomHeaders_ContentTypeRejected(t *testing.T) {
+	config := DefaultConfig()
+	config.CustomHeaders = map[string]string{"content-type": "application/json"}
+	err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), "file.bin", "http://localhost", nil)
+	if err == nil || !strings.Contains(err.Error(), "Content-Type") {
+		t.Errorf("Ожидалась ошибка переопределения Content-Type, получено %v", err)
+	}
+}
+
+func TestSendFileSizeHeader(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sizes = append(sizes, r.Header.Get(FileSizeHeader))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "size.bin")
+	if err := os.WriteFile(testFile, []byte("declared size"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	for _, enabled := range []bool{true, false} {
+		config := DefaultConfig()
+		config.SendFileSizeHeader = enabled
+		if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, server.URL+"/upload", nil); err != nil {
+			t.Fatalf("Ошибка загрузки: %v", err)
+		}
+	}
+
+	if len(sizes) != 2 {
+		t.Fatalf("Ожидалось 2 запроса, получено %d", len(sizes))
+	}
+	if sizes[0] != "13" {
+		t.Errorf("Неверный заголовок %s: %q", FileSizeHeader, sizes[0])
+	}
+	if sizes[1] != "" {
+		t.Errorf("Заголовок %s не должен отправляться без опции: %q", FileSizeHeader, sizes[1])
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// IdempotencyKeyHeader заголовок с ключом идемпотентности загрузки. Сервер, получивший
+// повторный запрос с тем же ключом, возвращает сохраненный ответ, не принимая файл заново
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKey возвращает ключ идемпотентности загрузки файла: SHA-256 от абсолютного
+// пути, имени и директории на сервере, относительного пути, полей формы, времени изменения
+// и размера. Ключ одинаков для всех попыток загрузки неизменного файла в одно место,
+// поэтому повтор после потерянного ответа не дублирует запись, а загрузка того же файла
+// в другую директорию не получает чужой ответ
+func idempotencyKey(task uploadTask) (string, error) {
+	absPath, err := filepath.Abs(task.filePath)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%d\x00%d", absPath, task.formFileName(), task.remotePath,
+		task.headers.Get(RelativePathHeader), info.ModTime().UnixNano(), info.Size())
+	names := make([]string, 0, len(task.fields))
+	for name := range task.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(hash, "\x00%s=%s", name, task.fields[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// withIdempotencyKey возвращает задание с заголовком Idempotency-Key; параметры
+// из контекста должны быть уже применены к заданию. Ключ, заданный
+// в заголовках задания, сохраняется; ключ из CustomHeaders имеет приоритет над обоими
+func (t uploadTask) withIdempotencyKey() uploadTask {
+	if t.headers.Get(IdempotencyKeyHeader) != "" {
+		return t
+	}
+	key, err := idempotencyKey(t)
+	if err != nil {
+		// Ошибку доступа к файлу сообщит загрузка
+		return t
+	}
+	return t.withHeader(IdempotencyKeyHeader, key)
+}
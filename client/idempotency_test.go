@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadFile_IdempotentRetry(t *testing.T) {
+	uploadDir := t.TempDir()
+	srv := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir:      uploadDir,
+		IdempotencyTTL: time.Minute,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Первый ответ теряется: сервер сохраняет файл, но обрывает соединение
+	var requests atomic.Int32
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if requests.Add(1) == 1 {
+			srv.Handler().ServeHTTP(httptest.NewRecorder(), r)
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		srv.Handler().ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, []byte("report"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.RetryDelay = 10 * time.Millisecond
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("Все попытки должны отправлять один ключ: %q", keys)
+	}
+	if data, err := os.ReadFile(filepath.Join(uploadDir, "report.bin")); err != nil || string(data) != "report" {
+		t.Errorf("Файл не сохранен: %q, %v", data, err)
+	}
+}
+
+func TestUploadFile_IdempotencyKeyRemotePath(t *testing.T) {
+	uploadDir := t.TempDir()
+	srv := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir:       uploadDir,
+		AllowClientPath: true,
+		IdempotencyTTL:  time.Minute,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(testFile, []byte("data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	client := NewHTTPClientWithConfig(DefaultConfig())
+	for _, remotePath := range []string{"tenant1", "tenant2"} {
+		ctx := WithUploadOptions(context.Background(), UploadOptions{RemotePath: remotePath})
+		if err := client.UploadFile(ctx, testFile, ts.URL+"/upload", nil); err != nil {
+			t.Fatalf("Ошибка загрузки в %s: %v", remotePath, err)
+		}
+		if _, err := os.Stat(filepath.Join(uploadDir, remotePath, "a.bin")); err != nil {
+			t.Errorf("Файл не сохранен в %s: %v", remotePath, err)
+		}
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(testFile, []byte("data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	key := uploadTask{filePath: testFile}.withIdempotencyKey().headers.Get(IdempotencyKeyHeader)
+	if len(key) != 64 {
+		t.Fatalf("Ожидался SHA-256 в hex, получено %q", key)
+	}
+
+	// Под другим именем на сервере тот же файл загружается отдельно
+	renamed := uploadTask{filePath: testFile, remoteName: "copy.bin"}.withIdempotencyKey()
+	if renamed.headers.Get(IdempotencyKeyHeader) == key {
+		t.Error("Ключ должен зависеть от имени на сервере")
+	}
+
+	// Загрузка в другую директорию, по другому относительному пути или с другими
+	// полями формы не должна получать сохраненный ответ на первую
+	variants := map[string]uploadTask{
+		"RemotePath":   {filePath: testFile, remotePath: "tenant2"},
+		"RelativePath": {filePath: testFile, headers: http.Header{RelativePathHeader: {"dir/data.bin"}}},
+		"ExtraFields":  {filePath: testFile, fields: map[string]string{"tag": "v2"}},
+	}
+	for name, task := range variants {
+		if task.withIdempotencyKey().headers.Get(IdempotencyKeyHeader) == key {
+			t.Errorf("Ключ должен зависеть от %s", name)
+		}
+	}
+
+	// Изменение файла меняет ключ
+	if err := os.WriteFile(testFile, []byte("changed"), 0644); err != nil {
+		t.Fatalf("Ошибка изменения файла: %v", err)
+	}
+	if (uploadTask{filePath: testFile}).withIdempotencyKey().headers.Get(IdempotencyKeyHeader) == key {
+		t.Error("Ключ должен меняться вместе с файлом")
+	}
+
+	// Ключ из заголовков задания не заменяется
+	custom := uploadTask{filePath: testFile, headers: http.Header{IdempotencyKeyHeader: {"manual"}}}
+	if got := custom.withIdempotencyKey().headers.Get(IdempotencyKeyHeader); got != "manual" {
+		t.Errorf("Ожидался ключ manual, получено %q", got)
+	}
+}
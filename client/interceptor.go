@@ -0,0 +1,103 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Interceptor перехватывает HTTP-запросы загрузки файла
+type Interceptor interface {
+	// Before вызывается перед отправкой запроса и может изменить его заголовки.
+	// Ошибка отменяет попытку загрузки
+	Before(r *http.Request) error
+
+	// After вызывается после получения ответа или ошибки, в том числе ошибки Before
+	// следующего перехватчика; resp равен nil, если ответ не получен
+	After(r *http.Request, resp *http.Response, err error)
+}
+
+// AddInterceptor добавляет перехватчик запросов загрузки. Before вызываются в порядке
+// добавления, After — в обратном. Перехватчики добавляются до начала загрузок;
+// клиенты из WithAuth и WithLoggingTransport получают копию списка
+func (c *HTTPClient) AddInterceptor(i Interceptor) {
+	c.interceptors = append(c.interceptors, i)
+}
+
+// doIntercepted выполняет запрос загрузки, вызывая перехватчики
+func (c *HTTPClient) doIntercepted(req *http.Request) (*http.Response, error) {
+	for i, interceptor := range c.interceptors {
+		if err := interceptor.Before(req); err != nil {
+			err = fmt.Errorf("перехватчик запроса: %w", err)
+			for j := i - 1; j >= 0; j-- {
+				c.interceptors[j].After(req, nil, err)
+			}
+			return nil, err
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		c.interceptors[i].After(req, resp, err)
+	}
+	return resp, err
+}
+
+// HeaderInjectInterceptor добавляет заголовки в каждый запрос загрузки,
+// заменяя одноименные заголовки клиента
+type HeaderInjectInterceptor struct {
+	Headers map[string]string
+}
+
+// Before реализует Interceptor
+func (h *HeaderInjectInterceptor) Before(r *http.Request) error {
+	for name, value := range h.Headers {
+		r.Header.Set(name, value)
+	}
+	return nil
+}
+
+// After реализует Interceptor
+func (h *HeaderInjectInterceptor) After(*http.Request, *http.Response, error) {}
+
+// Recorder получатель метрик запросов MetricsInterceptor
+type Recorder interface {
+	// IncRequest учитывает завершенный запрос; status — HTTP-статус ответа или 0, если ответ не получен
+	IncRequest(status int)
+
+	// ObserveLatency учитывает время от отправки запроса до получения заголовков ответа или ошибки
+	ObserveLatency(latency time.Duration)
+}
+
+// MetricsInterceptor передает в Recorder число запросов загрузки и их задержку
+type MetricsInterceptor struct {
+	recorder Recorder
+	started  sync.Map // Запрос -> время отправки; загрузки могут идти параллельно
+}
+
+// NewMetricsInterceptor создает перехватчик, записывающий метрики в recorder
+func NewMetricsInterceptor(recorder Recorder) *MetricsInterceptor {
+	return &MetricsInterceptor{recorder: recorder}
+}
+
+// Before реализует Interceptor
+func (m *MetricsInterceptor) Before(r *http.Request) error {
+	m.started.Store(r, time.Now())
+	return nil
+}
+
+// After реализует Interceptor
+func (m *MetricsInterceptor) After(r *http.Request, resp *http.Response, err error) {
+	started, ok := m.started.LoadAndDelete(r)
+	if !ok {
+		return
+	}
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	m.recorder.IncRequest(status)
+	m.recorder.ObserveLatency(time.Since(started.(time.Time)))
+}
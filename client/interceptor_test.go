@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingInterceptor записывает вызовы перехватчика
+type recordingInterceptor struct {
+	name      string
+	calls     *[]string
+	beforeErr error
+}
+
+func (i *recordingInterceptor) Before(r *http.Request) error {
+	*i.calls = append(*i.calls, i.name+".Before")
+	return i.beforeErr
+}
+
+func (i *recordingInterceptor) After(r *http.Request, resp *http.Response, err error) {
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	*i.calls = append(*i.calls, i.name+".After:"+http.StatusText(status))
+}
+
+// memoryRecorder реализует Recorder
+type memoryRecorder struct {
+	mu        sync.Mutex
+	statuses  []int
+	latencies []time.Duration
+}
+
+func (r *memoryRecorder) IncRequest(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses = append(r.statuses, status)
+}
+
+func (r *memoryRecorder) ObserveLatency(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+}
+
+func TestInterceptors(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Tenant")
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "intercepted.bin")
+	if err := os.WriteFile(testFile, []byte("intercepted"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var calls []string
+	recorder := &memoryRecorder{}
+	httpClient := NewHTTPClient(10 * time.Second)
+	httpClient.AddInterceptor(&recordingInterceptor{name: "a", calls: &calls})
+	httpClient.AddInterceptor(&HeaderInjectInterceptor{Headers: map[string]string{"X-Tenant": "acme"}})
+	httpClient.AddInterceptor(NewMetricsInterceptor(recorder))
+	httpClient.AddInterceptor(&recordingInterceptor{name: "b", calls: &calls})
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if header != "acme" {
+		t.Errorf("Заголовок не добавлен: %q", header)
+	}
+	if got := strings.Join(calls, ","); got != "a.Before,b.Before,b.After:OK,a.After:OK" {
+		t.Errorf("Неверный порядок вызовов: %s", got)
+	}
+	if len(recorder.statuses) != 1 || recorder.statuses[0] != http.StatusOK || len(recorder.latencies) != 1 || recorder.latencies[0] <= 0 {
+		t.Errorf("Неверные метрики: %v, %v", recorder.statuses, recorder.latencies)
+	}
+
+	// Перехватчики копируются в производный клиент
+	if derived := httpClient.WithAuth(AuthConfig{Type: AuthTypeBearer, Token: "t"}); len(derived.interceptors) != 4 {
+		t.Errorf("Перехватчики не скопированы: %d", len(derived.interceptors))
+	}
+}
+
+func TestInterceptors_Errors(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "failed.bin")
+	if err := os.WriteFile(testFile, []byte("failed"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.RetryAttempts = 0
+
+	// After вызывается и при сетевой ошибке
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	var calls []string
+	recorder := &memoryRecorder{}
+	httpClient := NewHTTPClientWithConfig(config)
+	httpClient.AddInterceptor(&recordingInterceptor{name: "a", calls: &calls})
+	httpClient.AddInterceptor(NewMetricsInterceptor(recorder))
+	if err := httpClient.UploadFile(context.Background(), testFile, down.URL, nil); err == nil {
+		t.Fatal("Ожидалась сетевая ошибка")
+	}
+	if got := strings.Join(calls, ","); got != "a.Before,a.After:" {
+		t.Errorf("Неверные вызовы при сетевой ошибке: %s", got)
+	}
+	if len(recorder.statuses) != 1 || recorder.statuses[0] != 0 {
+		t.Errorf("Запрос без ответа не учтен: %v", recorder.statuses)
+	}
+
+	// Ошибка Before отменяет запрос, а уже вызванные перехватчики получают After
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { requests++ }))
+	defer server.Close()
+	calls = nil
+	errDenied := errors.New("запрещено")
+	httpClient = NewHTTPClientWithConfig(config)
+	httpClient.AddInterceptor(&recordingInterceptor{name: "a", calls: &calls})
+	httpClient.AddInterceptor(&recordingInterceptor{name: "b", calls: &calls, beforeErr: errDenied})
+	err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil)
+	if !errors.Is(err, errDenied) || requests != 0 {
+		t.Errorf("Ожидалась ошибка перехватчика без запроса, получено %v, запросов %d", err, requests)
+	}
+	if got := strings.Join(calls, ","); got != "a.Before,b.Before,a.After:" {
+		t.Errorf("Неверные вызовы при ошибке Before: %s", got)
+	}
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"net"
+	"time"
+)
+
+// defaultKeepAliveIdle время простоя соединения до первой проверки keep-alive,
+// если задан только TCPKeepAliveInterval (так же поступает net.Dialer)
+const defaultKeepAliveIdle = 15 * time.Second
+
+// configureKeepAlive настраивает TCP keep-alive соединений dialer. idle — время простоя
+// до первой проверки (отрицательное значение отключает keep-alive, 0 — значение net.Dialer),
+// interval — интервал между проверками. Интервал задается через параметры сокета
+// и поддерживается на Linux; на других ОС используется интервал системы
+func configureKeepAlive(dialer *net.Dialer, idle, interval time.Duration) {
+	dialer.KeepAlive = idle
+	if idle < 0 || interval <= 0 {
+		return
+	}
+	if idle == 0 {
+		idle = defaultKeepAliveIdle
+	}
+	if control := keepAliveControl(idle, interval); control != nil {
+		// net.Dialer после подключения перезаписал бы интервал значением KeepAlive,
+		// поэтому keep-alive полностью настраивается в Control
+		dialer.KeepAlive = -1
+		dialer.Control = control
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"strings"
+	"syscall"
+	"time"
+)
+
+// keepAliveControl возвращает функцию net.Dialer.Control, включающую keep-alive
+// с временем простоя idle и интервалом проверок interval
+func keepAliveControl(idle, interval time.Duration) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if !strings.HasPrefix(network, "tcp") {
+			// Unix-сокет не поддерживает TCP keep-alive
+			return nil
+		}
+
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			sockErr = setKeepAlive(int(fd), idle, interval)
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
+
+// setKeepAlive включает keep-alive на сокете fd; значения округляются до секунд
+func setKeepAlive(fd int, idle, interval time.Duration) error {
+	if err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1); err != nil {
+		return err
+	}
+	if err := syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, keepAliveSeconds(idle)); err != nil {
+		return err
+	}
+	return syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, keepAliveSeconds(interval))
+}
+
+// keepAliveSeconds переводит длительность в целые секунды, не меньше одной
+func keepAliveSeconds(d time.Duration) int {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestConfigureKeepAlive_Linux(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Ошибка запуска слушателя: %v", err)
+	}
+	defer listener.Close()
+
+	dialer := &net.Dialer{}
+	configureKeepAlive(dialer, 45*time.Second, 7*time.Second)
+	conn, err := dialer.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Ошибка подключения: %v", err)
+	}
+	defer conn.Close()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatalf("Ошибка получения сокета: %v", err)
+	}
+	options := map[string][3]int{
+		"SO_KEEPALIVE":  {syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1},
+		"TCP_KEEPIDLE":  {syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, 45},
+		"TCP_KEEPINTVL": {syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, 7},
+	}
+	rawConn.Control(func(fd uintptr) {
+		for name, option := range options {
+			value, err := syscall.GetsockoptInt(int(fd), option[0], option[1])
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			if value != option[2] {
+				t.Errorf("%s: ожидалось %d, получено %d", name, option[2], value)
+			}
+		}
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package client
+
+import (
+	"syscall"
+	"time"
+)
+
+// keepAliveControl возвращает nil: интервал проверок keep-alive на этой ОС не настраивается
+func keepAliveControl(idle, interval time.Duration) func(network, address string, c syscall.RawConn) error {
+	return nil
+}
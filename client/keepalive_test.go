@@ -0,0 +1,15 @@
+package client
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConfigureKeepAlive_Disabled(t *testing.T) {
+	dialer := &net.Dialer{}
+	configureKeepAlive(dialer, -1, 10*time.Second)
+	if dialer.KeepAlive >= 0 || dialer.Control != nil {
+		t.Errorf("Keep-alive должен быть отключен: %v", dialer.KeepAlive)
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultLimitCacheTTL время хранения ограничений сервера по умолчанию
+const defaultLimitCacheTTL = 5 * time.Minute
+
+// ErrFileTooLarge возвращается без отправки запроса, если файл больше
+// ServerLimits.MaxFileSizeBytes из кэша FetchServerLimits
+var ErrFileTooLarge = errors.New("файл превышает лимит сервера")
+
+// ServerLimits ограничения загрузки на сервере (GET /limits)
+type ServerLimits struct {
+	MaxFileSizeBytes     int64    `json:"max_file_size_bytes"`    // 0 — без ограничения
+	MaxConcurrentUploads int      `json:"max_concurrent_uploads"` // Одновременных загрузок с одного адреса (0 — без ограничения)
+	AllowedMIMETypes     []string `json:"allowed_mime_types"`     // Пусто — любые
+}
+
+// limitsCache ограничения серверов, полученные FetchServerLimits: адрес /limits -> ограничения
+type limitsCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedLimits
+}
+
+// cachedLimits ограничения сервера и время их получения
+type cachedLimits struct {
+	limits    ServerLimits
+	fetchedAt time.Time
+}
+
+// get возвращает ограничения, полученные не раньше ttl назад
+func (c *limitsCache) get(key string, ttl time.Duration) (ServerLimits, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) > ttl {
+		return ServerLimits{}, false
+	}
+	return entry.limits, true
+}
+
+// put сохраняет ограничения сервера
+func (c *limitsCache) put(key string, limits ServerLimits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]cachedLimits)
+	}
+	c.entries[key] = cachedLimits{limits: limits, fetchedAt: time.Now()}
+}
+
+// FetchServerLimits запрашивает ограничения загрузки у сервера. serverURL — адрес
+// сервера, путь URL заменяется на /limits. Результат кэшируется на ClientConfig.LimitCacheTTL:
+// пока он в кэше, файлы больше MaxFileSizeBytes отклоняются с ErrFileTooLarge до соединения
+// с сервером
+func (c *HTTPClient) FetchServerLimits(ctx context.Context, serverURL string) (*ServerLimits, error) {
+	if c.initErr != nil {
+		return nil, c.initErr
+	}
+
+	limitsURL, err := endpointURL(serverURL, "/limits")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, limitsURL.String(), nil)
+	if err != nil {
+		return nil, newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var limits ServerLimits
+	if err := json.NewDecoder(resp.Body).Decode(&limits); err != nil {
+		return nil, fmt.Errorf("ошибка разбора ограничений сервера: %w", err)
+	}
+	if c.config.LimitCacheTTL > 0 {
+		c.limits.put(limitsURL.String(), limits)
+	}
+	return &limits, nil
+}
+
+// checkServerLimits сверяет размер файла с кэшированными ограничениями сервера serverURL.
+// Без FetchServerLimits проверка не выполняется. Со сжатием размер передаваемых данных
+// заранее неизвестен, поэтому файл не проверяется
+func (c *HTTPClient) checkServerLimits(filePath, serverURL string) error {
+	if c.config.LimitCacheTTL <= 0 || c.config.CompressUpload {
+		return nil
+	}
+	limitsURL, err := endpointURL(serverURL, "/limits")
+	if err != nil {
+		return nil
+	}
+	limits, ok := c.limits.get(limitsURL.String(), c.config.LimitCacheTTL)
+	if !ok || limits.MaxFileSizeBytes <= 0 {
+		return nil
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	if fileInfo.Size() > limits.MaxFileSizeBytes {
+		return fmt.Errorf("%w: %s больше %s", ErrFileTooLarge, formatBytes(fileInfo.Size()), formatBytes(limits.MaxFileSizeBytes))
+	}
+	return nil
+}
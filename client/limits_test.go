@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestFetchServerLimits(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{
+		UploadDir:                 t.TempDir(),
+		MaxFileSizeBytes:          1024,
+		MaxConcurrentUploadsPerIP: 4,
+		AllowedMIMETypes:          []string{"text/plain"},
+	})
+
+	limits, err := NewHTTPClient(10*time.Second).FetchServerLimits(context.Background(), ts.URL+"/upload")
+	if err != nil {
+		t.Fatalf("Ошибка запроса ограничений: %v", err)
+	}
+	expected := ServerLimits{MaxFileSizeBytes: 1024, MaxConcurrentUploads: 4, AllowedMIMETypes: []string{"text/plain"}}
+	if !reflect.DeepEqual(*limits, expected) {
+		t.Errorf("Ожидалось %+v, получено %+v", expected, *limits)
+	}
+}
+
+func TestUploadFile_ServerLimits(t *testing.T) {
+	var uploads atomic.Int32
+	handler := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir:        t.TempDir(),
+		MaxFileSizeBytes: 1024,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/upload" {
+			uploads.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	small, large := filepath.Join(dir, "small.bin"), filepath.Join(dir, "large.bin")
+	os.WriteFile(small, make([]byte, 512), 0644)
+	os.WriteFile(large, make([]byte, 2048), 0644)
+
+	config := DefaultConfig()
+	config.RetryAttempts = 0
+	httpClient := NewHTTPClientWithConfig(config)
+
+	// Без кэша ограничений файл отправляется и отклоняется сервером
+	if err := httpClient.UploadFile(context.Background(), large, ts.URL+"/upload", nil); err == nil || errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Ожидался отказ сервера, получено %v", err)
+	}
+	if uploads.Load() != 1 {
+		t.Fatalf("Ожидался 1 запрос на загрузку, получено %d", uploads.Load())
+	}
+
+	if _, err := httpClient.FetchServerLimits(context.Background(), ts.URL); err != nil {
+		t.Fatalf("Ошибка запроса ограничений: %v", err)
+	}
+	if err := httpClient.UploadFile(context.Background(), large, ts.URL+"/upload", nil); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Ожидалась ошибка ErrFileTooLarge, получено %v", err)
+	}
+	if err := httpClient.UploadFile(context.Background(), small, ts.URL+"/upload", nil); err != nil {
+		t.Errorf("Ошибка загрузки файла в пределах лимита: %v", err)
+	}
+	if uploads.Load() != 2 {
+		t.Errorf("Файл больше лимита не должен отправляться: %d запросов на загрузку", uploads.Load())
+	}
+
+	// Устаревшие ограничения не применяются
+	config.LimitCacheTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if err := httpClient.UploadFile(context.Background(), large, ts.URL+"/upload", nil); errors.Is(err, ErrFileTooLarge) {
+		t.Error("Устаревшие ограничения не должны применяться")
+	}
+}
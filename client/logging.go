@@ -0,0 +1,142 @@
+package client
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// redactedHeaders заголовки, значения которых не попадают в лог
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", APIKeyHeader}
+
+// LoggingTransport http.RoundTripper, записывающий в лог метод, URL, статус ответа,
+// длительность и количество переданных байт каждого запроса. На уровне Debug
+// также записываются заголовки запроса и ответа со скрытыми учетными данными
+type LoggingTransport struct {
+	Base   http.RoundTripper // Вложенный транспорт (nil — http.DefaultTransport)
+	Logger *slog.Logger      // Логгер (nil — slog.Default())
+}
+
+// RoundTrip реализует http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	logger := t.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	logger = logger.With("method", req.Method, "url", req.URL.String())
+	if logger.Enabled(req.Context(), slog.LevelDebug) {
+		logger.Debug("HTTP запрос", "headers", redactHeaders(req.Header))
+	}
+
+	// Подсчитываем отправленные байты тела запроса
+	sent := &countingReadCloser{}
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		sent.rc = req.Body
+		req.Body = sent
+	}
+
+	startTime := time.Now()
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		logger.Error("Ошибка HTTP запроса",
+			"duration", time.Since(startTime).Round(time.Millisecond),
+			"bytes_sent", sent.n.Load(),
+			"error", err)
+		return nil, err
+	}
+
+	if logger.Enabled(req.Context(), slog.LevelDebug) {
+		logger.Debug("HTTP ответ", "status", resp.StatusCode, "headers", redactHeaders(resp.Header))
+	}
+
+	// Итоговая запись делается при закрытии тела ответа, когда известен его размер
+	resp.Body = &loggingBody{
+		countingReadCloser: countingReadCloser{rc: resp.Body},
+		onClose: func(received int64) {
+			logger.Info("HTTP запрос выполнен",
+				"status", resp.StatusCode,
+				"duration", time.Since(startTime).Round(time.Millisecond),
+				"bytes_sent", sent.n.Load(),
+				"bytes_received", received)
+		},
+	}
+
+	return resp, nil
+}
+
+// WithLoggingTransport возвращает новый клиент, записывающий в лог каждый HTTP-запрос.
+// Новый клиент использует общую конфигурацию и ограничение параллелизма с исходным
+func (c *HTTPClient) WithLoggingTransport(logger *slog.Logger) *HTTPClient {
+	httpClient := *c.client
+	httpClient.Transport = &LoggingTransport{Base: c.client.Transport, Logger: logger}
+
+	return &HTTPClient{
+		client:  &httpClient,
+		config:  c.config,
+		sem:     c.sem,
+		buffers: c.buffers,
+		sized:   c.sized,
+		limiter: c.limiter,
+		initErr: c.initErr,
+		stats:   c.stats,
+
+		progressMu: c.progressMu,
+		events:     c.events,
+		limits:     c.limits,
+		transfers:  c.transfers,
+
+		interceptors: append([]Interceptor(nil), c.interceptors...),
+	}
+}
+
+// redactHeaders возвращает копию заголовков со скрытыми учетными данными
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+	return redacted
+}
+
+// countingReadCloser подсчитывает количество прочитанных байт
+type countingReadCloser struct {
+	rc io.ReadCloser
+	n  atomic.Int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.rc.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+func (c *countingReadCloser) Close() error {
+	return c.rc.Close()
+}
+
+// loggingBody тело ответа, вызывающее onClose один раз при закрытии
+type loggingBody struct {
+	countingReadCloser
+	once    sync.Once
+	onClose func(received int64)
+}
+
+func (b *loggingBody) Close() error {
+	err := b.countingReadCloser.Close()
+	b.once.Do(func() { b.onClose(b.n.Load()) })
+	return err
+}
+
+// Проверка соответствия интерфейсу на этапе компиляции
+var _ http.RoundTripper = (*LoggingTransport)(nil)
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLoggingTransport(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "logging.bin")
+	if err := os.WriteFile(testFile, []byte("logging transport"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// Аутентификация добавляется до логирующего транспорта, поэтому заголовок попадает в лог
+	httpClient := NewHTTPClient(10 * time.Second).WithLoggingTransport(logger)
+	httpClient.client.Transport = &authTransport{
+		base: httpClient.client.Transport,
+		auth: AuthConfig{Type: AuthTypeBearer, Token: "secret-token"},
+	}
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"method=POST", "status=200", "bytes_sent=", "bytes_received=", "[REDACTED]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Лог не содержит %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "secret-token") {
+		t.Errorf("Токен не скрыт в логе:\n%s", output)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set(APIKeyHeader, "secret")
+	header.Set("Content-Type", "application/octet-stream")
+
+	redacted := redactHeaders(header)
+	if redacted.Get("Authorization") != "[REDACTED]" || redacted.Get(APIKeyHeader) != "[REDACTED]" {
+		t.Errorf("Учетные данные не скрыты: %v", redacted)
+	}
+	if redacted.Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("Изменен обычный заголовок: %v", redacted)
+	}
+	if header.Get("Authorization") != "Bearer secret" {
+		t.Error("Исходные заголовки не должны изменяться")
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ManifestEntry описание одного файла в манифесте загрузки
+type ManifestEntry struct {
+	LocalPath  string `json:"local_path"`
+	RemoteName string `json:"remote_name"`
+	Checksum   string `json:"checksum,omitempty"` // SHA-256 в hex, допускается префикс "sha256:"
+}
+
+// LoadManifest читает JSON-манифест загрузки
+func LoadManifest(manifestPath string) ([]ManifestEntry, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения манифеста: %w", err)
+	}
+
+	var entries []ManifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("ошибка разбора манифеста: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("манифест не содержит файлов")
+	}
+
+	return entries, nil
+}
+
+// UploadManifest загружает файлы, описанные в JSON-манифесте.
+// Перед началом загрузки проверяется наличие всех файлов и их контрольные суммы
+func (c *HTTPClient) UploadManifest(ctx context.Context, manifestPath, serverURL string) ([]UploadResult, error) {
+	entries, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]uploadTask, 0, len(entries))
+	for i, entry := range entries {
+		if err := validateManifestEntry(entry); err != nil {
+			return nil, fmt.Errorf("запись %d манифеста (%s): %w", i+1, entry.LocalPath, err)
+		}
+		tasks = append(tasks, uploadTask{filePath: entry.LocalPath, remoteName: entry.RemoteName})
+	}
+
+	return c.uploadTasks(ctx, tasks, serverURL, nil)
+}
+
+// validateManifestEntry проверяет существование файла и совпадение контрольной суммы
+func validateManifestEntry(entry ManifestEntry) error {
+	if entry.LocalPath == "" {
+		return fmt.Errorf("не указан local_path")
+	}
+
+	info, err := os.Stat(entry.LocalPath)
+	if err != nil {
+		return fmt.Errorf("файл недоступен: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("путь является директорией")
+	}
+
+	if entry.Checksum == "" {
+		return nil
+	}
+
+	expected := strings.ToLower(strings.TrimPrefix(entry.Checksum, "sha256:"))
+	actual, err := fileSHA256(entry.LocalPath)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("контрольная сумма не совпадает: ожидалась %s, получена %s", expected, actual)
+	}
+
+	return nil
+}
+
+// fileSHA256 вычисляет SHA-256 файла в hex
+func fileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUploadManifest(t *testing.T) {
+	tempDir := t.TempDir()
+	localPath := filepath.Join(tempDir, "local.bin")
+	if err := os.WriteFile(localPath, []byte("manifest data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	checksum, err := fileSHA256(localPath)
+	if err != nil {
+		t.Fatalf("Ошибка вычисления контрольной суммы: %v", err)
+	}
+
+	var requests int32
+	var remoteName atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		remoteName.Store(part.FileName())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	writeManifest := func(checksum string) string {
+		manifestPath := filepath.Join(tempDir, "manifest.json")
+		content := fmt.Sprintf(`[{"local_path": %q, "remote_name": "remote.bin", "checksum": %q}]`, localPath, checksum)
+		if err := os.WriteFile(manifestPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Ошибка создания манифеста: %v", err)
+		}
+		return manifestPath
+	}
+
+	httpClient := NewHTTPClient(10 * time.Second)
+
+	// Несовпадение контрольной суммы должно остановить загрузку до отправки запросов
+	_, err = httpClient.UploadManifest(context.Background(), writeManifest(strings.Repeat("0", 64)), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "контрольная сумма") {
+		t.Fatalf("Ожидалась ошибка контрольной суммы, получена: %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 0 {
+		t.Fatal("Запросы не должны отправляться при ошибке проверки манифеста")
+	}
+
+	results, err := httpClient.UploadManifest(context.Background(), writeManifest("sha256:"+checksum), server.URL)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки по манифесту: %v", err)
+	}
+	if len(results) != 1 || results[0].Err != nil || results[0].RemoteName != "remote.bin" {
+		t.Errorf("Неожиданные результаты: %+v", results)
+	}
+	if name, _ := remoteName.Load().(string); name != "remote.bin" {
+		t.Errorf("Ожидалось имя remote.bin на сервере, получено %q", name)
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MetadataHeaderPrefix префикс заголовков с метаданными загрузки: X-Meta-{Key}: {Value}.
+// Сервер сохраняет ключи в нижнем регистре
+const MetadataHeaderPrefix = "X-Meta-"
+
+// validateMetadata проверяет, что ключи и значения метаданных допустимы в заголовках HTTP
+func validateMetadata(metadata map[string]string) error {
+	for key, value := range metadata {
+		if !validMetadataKey(key) {
+			return fmt.Errorf("недопустимый ключ метаданных %q", key)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Errorf("недопустимое значение метаданных %q", key)
+		}
+	}
+	return nil
+}
+
+// validMetadataKey допускает в ключе латинские буквы, цифры, "-" и "_"
+func validMetadataKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// setMetadataHeaders добавляет ClientConfig.Metadata в запрос на загрузку
+func (c *HTTPClient) setMetadataHeaders(req *http.Request) {
+	for key, value := range c.config.Metadata {
+		req.Header.Set(MetadataHeaderPrefix+key, value)
+	}
+}
+
+// metadataURL возвращает адрес метаданных файла: {fileURL}/meta
+func metadataURL(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("некорректный URL файла: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/meta"
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// fetchMetadata запрашивает GET {fileURL}/meta. Если сервер не хранит метаданные
+// файла (404, 501 или ответ не в JSON), возвращается nil без ошибки
+func (c *HTTPClient) fetchMetadata(ctx context.Context, fileURL string) (map[string]string, error) {
+	metaURL, err := metadataURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metaURL, nil)
+	if err != nil {
+		return nil, newUploadError("ошибка создания HTTP запроса", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusNotImplemented:
+		return nil, nil
+	default:
+		return nil, responseError(resp)
+	}
+
+	// Сервер без эндпоинта метаданных может отдать по этому адресу что угодно
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return nil, nil
+	}
+
+	var metadata map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("ошибка разбора метаданных: %w", err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadMetadata_RoundTrip(t *testing.T) {
+	uploadDir := t.TempDir()
+	srv := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir: uploadDir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Сервер загрузки не отдает содержимое файлов, поэтому файл раздается отдельно,
+	// а /files/{filename}/meta обрабатывает сервер
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files/report.bin", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join(uploadDir, "report.bin"))
+	})
+	mux.Handle("/", srv.Handler())
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	os.WriteFile(testFile, []byte("report"), 0644)
+
+	config := DefaultConfig()
+	config.Timeout = 10 * time.Second
+	config.Metadata = map[string]string{"Author": "Иван", "project_id": "42"}
+	httpClient := NewHTTPClientWithConfig(config)
+	if err := httpClient.UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	stored, err := srv.Metadata("report.bin")
+	if err != nil {
+		t.Fatalf("Ошибка чтения метаданных на сервере: %v", err)
+	}
+	if stored["author"] != "Иван" || stored["project_id"] != "42" {
+		t.Errorf("Неверные метаданные на сервере: %v", stored)
+	}
+	if _, err := os.Stat(filepath.Join(uploadDir, "report.bin.meta.json")); err != nil {
+		t.Errorf("Файл метаданных не создан: %v", err)
+	}
+
+	destPath := filepath.Join(t.TempDir(), "downloaded.bin")
+	metadata, err := httpClient.DownloadFile(context.Background(), ts.URL+"/files/report.bin", destPath)
+	if err != nil {
+		t.Fatalf("Ошибка скачивания: %v", err)
+	}
+	if metadata["author"] != "Иван" || metadata["project_id"] != "42" {
+		t.Errorf("Неверные метаданные при скачивании: %v", metadata)
+	}
+	if content, _ := os.ReadFile(destPath); string(content) != "report" {
+		t.Errorf("Неверное содержимое: %q", content)
+	}
+}
+
+func TestDownloadFile_NoMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file.bin/meta" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("data"))
+	}))
+	defer ts.Close()
+
+	metadata, err := NewHTTPClient(10*time.Second).DownloadFile(context.Background(), ts.URL+"/file.bin", filepath.Join(t.TempDir(), "file.bin"))
+	if err != nil || metadata != nil {
+		t.Errorf("Ожидались пустые метаданные без ошибки, получено %v, %v", metadata, err)
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	valid := map[string]string{"author": "Иван", "Project-ID": "42", "build_number": ""}
+	if err := validateMetadata(valid); err != nil {
+		t.Errorf("Неожиданная ошибка: %v", err)
+	}
+
+	for _, metadata := range []map[string]string{
+		{"": "value"},
+		{"with space": "value"},
+		{"key": "line\r\nInjected: header"},
+	} {
+		if err := validateMetadata(metadata); err == nil {
+			t.Errorf("Ожидалась ошибка для %q", metadata)
+		}
+	}
+}
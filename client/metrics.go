@@ -0,0 +1,82 @@
+package client
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsSink получает сведения о каждой попытке загрузки, успешной или нет: файла,
+// потока, ответа по URL, файла архива или WebDAV. Вызывается из горутин параллельных
+// загрузок, поэтому реализация должна быть безопасной для одновременного использования
+type MetricsSink interface {
+	// ObserveUpload вызывается после попытки загрузки filePath (путь файла, имя файла в архиве
+	// или "-" для UploadReader и UploadFromURL) длительностью duration. bytes — фактически
+	// отправленные байты содержимого (после сжатия или шифрования), err — ошибка попытки или nil
+	ObserveUpload(filePath string, bytes int64, duration time.Duration, err error)
+}
+
+// uploadDurationBuckets границы корзин гистограммы длительности попытки в секундах
+var uploadDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// Значения метки result метрик PrometheusMetricsSink
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// PrometheusMetricsSink MetricsSink на основе коллекторов prometheus/client_golang.
+// Сам является prometheus.Collector и регистрируется в реестре приложения:
+//
+//	sink := client.NewPrometheusMetricsSink()
+//	prometheus.MustRegister(sink)
+//	config.Metrics = sink
+type PrometheusMetricsSink struct {
+	bytes    *prometheus.CounterVec   // http_client_upload_bytes_total{result}
+	attempts *prometheus.CounterVec   // http_client_upload_attempts_total{result}
+	duration *prometheus.HistogramVec // http_client_upload_duration_seconds{result}
+}
+
+// NewPrometheusMetricsSink создает незарегистрированный набор метрик
+func NewPrometheusMetricsSink() *PrometheusMetricsSink {
+	return &PrometheusMetricsSink{
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_client_upload_bytes_total",
+			Help: "Total number of content bytes sent in upload attempts by result.",
+		}, []string{"result"}),
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_client_upload_attempts_total",
+			Help: "Total number of upload attempts by result.",
+		}, []string{"result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_client_upload_duration_seconds",
+			Help:    "Duration of upload attempts in seconds by result.",
+			Buckets: uploadDurationBuckets,
+		}, []string{"result"}),
+	}
+}
+
+// ObserveUpload реализует MetricsSink
+func (m *PrometheusMetricsSink) ObserveUpload(filePath string, bytes int64, duration time.Duration, err error) {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	m.bytes.WithLabelValues(result).Add(float64(bytes))
+	m.attempts.WithLabelValues(result).Inc()
+	m.duration.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// Describe реализует prometheus.Collector
+func (m *PrometheusMetricsSink) Describe(ch chan<- *prometheus.Desc) {
+	m.bytes.Describe(ch)
+	m.attempts.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect реализует prometheus.Collector
+func (m *PrometheusMetricsSink) Collect(ch chan<- prometheus.Metric) {
+	m.bytes.Collect(ch)
+	m.attempts.Collect(ch)
+	m.duration.Collect(ch)
+}
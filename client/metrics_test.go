@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"httpBinaryClient/server"
+)
+
+// observation вызов MetricsSink.ObserveUpload
+type observation struct {
+	filePath string
+	bytes    int64
+	duration time.Duration
+	err      error
+}
+
+// recordingSink MetricsSink, сохраняющий вызовы для проверки в тестах
+type recordingSink struct {
+	mu           sync.Mutex
+	observations []observation
+}
+
+func (s *recordingSink) ObserveUpload(filePath string, bytes int64, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observations = append(s.observations, observation{filePath, bytes, duration, err})
+}
+
+func TestMetricsSink(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "недоступен", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 512))
+	}))
+	defer source.Close()
+
+	testFile := filepath.Join(t.TempDir(), "metrics.bin")
+	if err := os.WriteFile(testFile, make([]byte, 2048), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	sink := &recordingSink{}
+	config := DefaultConfig()
+	config.RetryAttempts = 1
+	config.RetryDelay = time.Millisecond
+	config.Metrics = sink
+	httpClient := NewHTTPClientWithConfig(config)
+	ctx := context.Background()
+	if err := httpClient.UploadFile(ctx, testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if err := httpClient.UploadFile(ctx, testFile, failing.URL, nil); err == nil {
+		t.Fatal("Ожидалась ошибка загрузки")
+	}
+	if err := httpClient.UploadReader(ctx, strings.NewReader("stream"), "stream.txt", ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки потока: %v", err)
+	}
+	if err := httpClient.UploadFromURL(ctx, source.URL+"/remote.bin", ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки по URL: %v", err)
+	}
+
+	// Успешная попытка, две неудачные (первая и повтор), поток и загрузка по URL
+	want := []struct {
+		filePath string
+		bytes    int64
+		failed   bool
+	}{
+		{testFile, 2048, false},
+		{testFile, -1, true},
+		{testFile, -1, true},
+		{"-", 6, false},
+		{"-", 512, false},
+	}
+	if len(sink.observations) != len(want) {
+		t.Fatalf("Ожидалось %d наблюдений, получено %d: %+v", len(want), len(sink.observations), sink.observations)
+	}
+	for i, w := range want {
+		got := sink.observations[i]
+		if got.filePath != w.filePath || (got.err != nil) != w.failed || got.duration <= 0 {
+			t.Errorf("Наблюдение %d: %+v", i, got)
+		}
+		// Сервер с ошибкой может ответить, не дочитав тело, поэтому объем не проверяется
+		if w.bytes >= 0 && got.bytes != w.bytes {
+			t.Errorf("Наблюдение %d: ожидалось %d байт, получено %d", i, w.bytes, got.bytes)
+		}
+	}
+}
+
+func TestMetricsSink_CompressedBytes(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+	testFile := filepath.Join(t.TempDir(), "zeros.bin")
+	if err := os.WriteFile(testFile, make([]byte, 64*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	sink := &recordingSink{}
+	config := DefaultConfig()
+	config.CompressUpload = true
+	config.Metrics = sink
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Учитываются отправленные сжатые байты, а не размер файла
+	if len(sink.observations) != 1 || sink.observations[0].bytes <= 0 || sink.observations[0].bytes >= 64*1024 {
+		t.Errorf("Неверное наблюдение сжатой загрузки: %+v", sink.observations)
+	}
+}
+
+func TestPrometheusMetricsSink(t *testing.T) {
+	sink := NewPrometheusMetricsSink()
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(sink); err != nil {
+		t.Fatalf("Ошибка регистрации: %v", err)
+	}
+
+	sink.ObserveUpload("a.bin", 1000, 200*time.Millisecond, nil)
+	sink.ObserveUpload("b.bin", 500, 2*time.Second, nil)
+	sink.ObserveUpload("c.bin", 100, 50*time.Millisecond, context.DeadlineExceeded)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Ошибка сбора метрик: %v", err)
+	}
+	// values значения метрик семейства по метке result: счетчик или число наблюдений гистограммы
+	values := make(map[string]map[string]float64)
+	for _, family := range families {
+		byResult := make(map[string]float64)
+		for _, metric := range family.GetMetric() {
+			result := metric.GetLabel()[0].GetValue()
+			if histogram := metric.GetHistogram(); histogram != nil {
+				byResult[result] = float64(histogram.GetSampleCount())
+				byResult[result+"_sum"] = histogram.GetSampleSum()
+			} else {
+				byResult[result] = metric.GetCounter().GetValue()
+			}
+		}
+		values[family.GetName()] = byResult
+	}
+
+	tests := []struct {
+		family, key string
+		want        float64
+	}{
+		{"http_client_upload_bytes_total", "success", 1500},
+		{"http_client_upload_bytes_total", "error", 100},
+		{"http_client_upload_attempts_total", "success", 2},
+		{"http_client_upload_attempts_total", "error", 1},
+		{"http_client_upload_duration_seconds", "success", 2},
+		{"http_client_upload_duration_seconds", "success_sum", 2.2},
+		{"http_client_upload_duration_seconds", "error", 1},
+	}
+	for _, tt := range tests {
+		if got := values[tt.family][tt.key]; math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s{%s}: ожидалось %v, получено %v", tt.family, tt.key, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"sort"
+	"time"
+)
+
+// UploadOptions параметры отдельной загрузки, дополняющие ClientConfig
+type UploadOptions struct {
+	// ExtraFields поля формы, отправляемые перед файлом, например описание или теги.
+	// Сервер возвращает принятые поля в JSON-ответе
+	ExtraFields map[string]string
+
+	// ExpectedContentType тип содержимого, который должен определить сервер с включенным
+	// DetectContentType, например application/octet-stream. Параметры (charset) не сравниваются.
+	// При несовпадении загрузка завершается ошибкой ErrContentTypeMismatch без повторов;
+	// если сервер тип не вернул, проверка пропускается
+	ExpectedContentType string
+
+	// RemotePath поддиректория загрузки на сервере, например tenants/acme. Передается
+	// в заголовке ClientConfig.PathHeaderName; сервер учитывает его только с AllowClientPath
+	RemotePath string
+}
+
+// ctxKey тип ключей контекста пакета, не пересекающийся с ключами других пакетов
+type ctxKey int
+
+// uploadOptionsKey ключ UploadOptions в контексте
+const uploadOptionsKey ctxKey = 0
+
+// WithUploadOptions возвращает контекст с параметрами загрузки opts, например полем формы
+// с идентификатором арендатора, добавленным промежуточным слоем. Загрузки файлов с этим
+// контекстом (UploadFile, UploadMultipleFiles, UploadDirectory и др.) применяют их так же,
+// как UploadFileWithOptions; параметры, переданные явно, имеют приоритет. Повторный вызов
+// дополняет параметры, уже записанные в ctx, и заменяет совпадающие
+func WithUploadOptions(ctx context.Context, opts UploadOptions) context.Context {
+	if parent, ok := ctx.Value(uploadOptionsKey).(UploadOptions); ok {
+		opts = mergeUploadOptions(parent, opts)
+	}
+	return context.WithValue(ctx, uploadOptionsKey, opts)
+}
+
+// mergeUploadOptions объединяет параметры: поля формы складываются, а непустые
+// значения override заменяют значения base
+func mergeUploadOptions(base, override UploadOptions) UploadOptions {
+	if len(base.ExtraFields) > 0 && len(override.ExtraFields) > 0 {
+		fields := make(map[string]string, len(base.ExtraFields)+len(override.ExtraFields))
+		for name, value := range base.ExtraFields {
+			fields[name] = value
+		}
+		for name, value := range override.ExtraFields {
+			fields[name] = value
+		}
+		base.ExtraFields = fields
+	} else if len(override.ExtraFields) > 0 {
+		base.ExtraFields = override.ExtraFields
+	}
+	if override.ExpectedContentType != "" {
+		base.ExpectedContentType = override.ExpectedContentType
+	}
+	if override.RemotePath != "" {
+		base.RemotePath = override.RemotePath
+	}
+	return base
+}
+
+// withContextOptions дополняет задание параметрами WithUploadOptions из ctx
+func (t uploadTask) withContextOptions(ctx context.Context) uploadTask {
+	opts, ok := ctx.Value(uploadOptionsKey).(UploadOptions)
+	if !ok {
+		return t
+	}
+	opts = mergeUploadOptions(opts, UploadOptions{ExtraFields: t.fields, ExpectedContentType: t.expectedContentType, RemotePath: t.remotePath})
+	t.fields, t.expectedContentType, t.remotePath = opts.ExtraFields, opts.ExpectedContentType, opts.RemotePath
+	return t
+}
+
+// pathHeaderName возвращает заголовок для UploadOptions.RemotePath
+func (c *HTTPClient) pathHeaderName() string {
+	if c.config.PathHeaderName != "" {
+		return c.config.PathHeaderName
+	}
+	return DefaultPathHeaderName
+}
+
+// UploadFileWithOptions выполняет потоковую загрузку файла с параметрами opts
+func (c *HTTPClient) UploadFileWithOptions(ctx context.Context, filePath, serverURL string, opts UploadOptions, progressCallback ProgressCallback) error {
+	if state, err := c.ProgressState(filePath); err == nil && state != nil {
+		// Сервер принимает файл целиком в одном запросе, поэтому передача начинается сначала
+		c.logger().Info("Найдено состояние прерванной загрузки",
+			"file", filePath,
+			"transferred", formatBytes(state.BytesTransferred),
+			"total", formatBytes(state.TotalBytes),
+			"started", state.StartTime.Format(time.RFC3339))
+	}
+
+	task := uploadTask{filePath: filePath, fields: opts.ExtraFields, expectedContentType: opts.ExpectedContentType, remotePath: opts.RemotePath}
+	_, _, err := c.upload(ctx, task, serverURL, progressCallback)
+	if err == nil && !c.config.DryRun {
+		c.clearProgressState(filePath)
+	}
+	return err
+}
+
+// writeFields записывает поля формы в порядке имен, чтобы запрос не зависел
+// от порядка обхода map
+func writeFields(w *multipart.Writer, fields map[string]string) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := w.WriteField(name, fields[name]); err != nil {
+			return fmt.Errorf("ошибка записи поля формы %s: %w", name, err)
+		}
+	}
+	return nil
+}
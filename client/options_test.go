@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadFileWithOptions_ExtraFields(t *testing.T) {
+	var parts []string
+	fields := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			parts = append(parts, part.FormName())
+			if part.FileName() == "" {
+				value, _ := io.ReadAll(part)
+				fields[part.FormName()] = string(value)
+			}
+		}
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, []byte("report"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	opts := UploadOptions{ExtraFields: map[string]string{"tags": "q3,finance", "description": "Отчет"}}
+	if err := NewHTTPClient(10*time.Second).UploadFileWithOptions(context.Background(), testFile, server.URL, opts, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Поля отправляются до файла в порядке имен
+	if len(parts) != 3 || parts[0] != "description" || parts[1] != "tags" || parts[2] != "file" {
+		t.Errorf("Неверный порядок частей формы: %v", parts)
+	}
+	if fields["description"] != "Отчет" || fields["tags"] != "q3,finance" {
+		t.Errorf("Неверные поля формы: %v", fields)
+	}
+}
+
+func TestWithUploadOptions(t *testing.T) {
+	var fields map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fields = make(map[string]string)
+		for name, values := range r.MultipartForm.Value {
+			fields[name] = values[0]
+		}
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, []byte("report"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	httpClient := NewHTTPClient(10 * time.Second)
+
+	// Промежуточные слои дополняют параметры друг друга
+	ctx := WithUploadOptions(context.Background(), UploadOptions{ExtraFields: map[string]string{"tenant": "acme", "source": "api"}})
+	ctx = WithUploadOptions(ctx, UploadOptions{ExtraFields: map[string]string{"source": "gateway"}})
+
+	if err := httpClient.UploadFile(ctx, testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if len(fields) != 2 || fields["tenant"] != "acme" || fields["source"] != "gateway" {
+		t.Errorf("Неверные поля из контекста: %v", fields)
+	}
+
+	// Явно переданные параметры имеют приоритет над контекстом
+	opts := UploadOptions{ExtraFields: map[string]string{"tenant": "globex", "description": "Отчет"}}
+	if err := httpClient.UploadFileWithOptions(ctx, testFile, server.URL, opts, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if len(fields) != 3 || fields["tenant"] != "globex" || fields["source"] != "gateway" || fields["description"] != "Отчет" {
+		t.Errorf("Неверное объединение полей: %v", fields)
+	}
+}
+
+func TestUploadFileWithOptions_RemotePath(t *testing.T) {
+	uploadDir := t.TempDir()
+	ts := httptest.NewServer(server.NewHTTPServerWithConfig(&server.ServerConfig{
+		UploadDir:       uploadDir,
+		AllowClientPath: true,
+		PathHeaderName:  "X-Tenant-Dir",
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler())
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, []byte("report"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	config := DefaultConfig()
+	config.PathHeaderName = "X-Tenant-Dir"
+	httpClient := NewHTTPClientWithConfig(config)
+
+	opts := UploadOptions{RemotePath: "tenants/acme"}
+	if err := httpClient.UploadFileWithOptions(context.Background(), testFile, ts.URL+"/upload", opts, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(uploadDir, "tenants", "acme", "report.bin")); err != nil {
+		t.Errorf("Файл не сохранен в поддиректории: %v", err)
+	}
+
+	// Поддиректория из контекста
+	ctx := WithUploadOptions(context.Background(), UploadOptions{RemotePath: "tenants/globex"})
+	if err := httpClient.UploadFile(ctx, testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(uploadDir, "tenants", "globex", "report.bin")); err != nil {
+		t.Errorf("Файл не сохранен в поддиректории из контекста: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Форматы вывода прогресса UploadFileWithProgress
+const (
+	ProgressFormatBar   = "bar"   // Полоса прогресса; вне терминала — строка на обновление
+	ProgressFormatHuman = "human" // Сообщения в логе на уровне debug
+	ProgressFormatJSON  = "json"  // Одна JSON-строка на обновление
+)
+
+// defaultProgressInterval минимальный интервал между вызовами callback прогресса по умолчанию
+const defaultProgressInterval = time.Second
+
+// ThrottleProgress оборачивает callback так, чтобы он вызывался не чаще одного
+// раза в interval. Первый вызов и вызов с завершением передачи (100%) передаются
+// всегда. При interval <= 0 или nil callback возвращается без изменений
+func ThrottleProgress(interval time.Duration, cb ProgressCallback) ProgressCallback {
+	if cb == nil || interval <= 0 {
+		return cb
+	}
+
+	var mu sync.Mutex
+	var lastUpdate time.Time
+
+	return func(bytesTransferred, totalBytes int64, percentage float64) {
+		mu.Lock()
+		if percentage < 100 && !lastUpdate.IsZero() && time.Since(lastUpdate) < interval {
+			mu.Unlock()
+			return
+		}
+		lastUpdate = time.Now()
+		mu.Unlock()
+
+		cb(bytesTransferred, totalBytes, percentage)
+	}
+}
+
+// serializeProgress оборачивает callback так, чтобы его вызовы не пересекались
+// с вызовами callback других загрузок клиента (см. ProgressCallback)
+func (c *HTTPClient) serializeProgress(cb ProgressCallback) ProgressCallback {
+	if cb == nil {
+		return nil
+	}
+	return func(bytesTransferred, totalBytes int64, percentage float64) {
+		c.progressMu.Lock()
+		defer c.progressMu.Unlock()
+		cb(bytesTransferred, totalBytes, percentage)
+	}
+}
+
+// jsonProgressLine строка прогресса в формате json
+type jsonProgressLine struct {
+	File     string  `json:"file"`
+	Bytes    int64   `json:"bytes"`
+	Total    int64   `json:"total"`
+	Pct      float64 `json:"pct"`
+	SpeedBPS float64 `json:"speed_bps"` // Средняя скорость с начала передачи
+	ETASec   float64 `json:"eta_sec"`   // Оставшееся время; 0, если размер неизвестен или скорость еще не установилась
+
+	Transport *TransportStats `json:"transport,omitempty"` // Состояние пула соединений клиента
+}
+
+// JSONProgress возвращает callback, который пишет прогресс в w построчно в формате JSON:
+// {"file":"x","bytes":N,"total":M,"pct":P,"speed_bps":S,"eta_sec":T}.
+// Вывод удобно разбирать в CI, например через jq. Оставшееся время оценивается по скорости
+// за последние секунды, сглаженной экспоненциальным средним, и не выводится первые 3 секунды передачи
+func JSONProgress(w io.Writer, file string) ProgressCallback {
+	return jsonProgress(w, file, defaultETAWarmupPeriod, defaultEMAAlpha, nil)
+}
+
+// jsonProgress реализует JSONProgress с периодом прогрева оценки warmup и коэффициентом
+// сглаживания скорости alpha; если stats
+// не nil, в каждую строку добавляется поле transport с состоянием пула соединений
+func jsonProgress(w io.Writer, file string, warmup time.Duration, alpha float64, stats func() TransportStats) ProgressCallback {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	start := time.Now()
+	meter := newSpeedMeter(start, warmup, alpha)
+
+	return func(bytesTransferred, totalBytes int64, percentage float64) {
+		line := jsonProgressLine{File: file, Bytes: bytesTransferred, Total: totalBytes, Pct: percentage}
+		if stats != nil {
+			transport := stats()
+			line.Transport = &transport
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		if elapsed := now.Sub(start).Seconds(); elapsed > 0 {
+			line.SpeedBPS = float64(bytesTransferred) / elapsed
+		}
+		meter.add(now, bytesTransferred)
+		if eta, ok := meter.eta(now, bytesTransferred, totalBytes); ok {
+			line.ETASec = eta.Seconds()
+		}
+		encoder.Encode(line)
+	}
+}
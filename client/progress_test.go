@@ -0,0 +1,149 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThrottleProgress(t *testing.T) {
+	var calls []float64
+	cb := ThrottleProgress(time.Hour, func(bytesTransferred, totalBytes int64, percentage float64) {
+		calls = append(calls, percentage)
+	})
+
+	// Первый вызов проходит, промежуточные отбрасываются, завершение проходит всегда
+	cb(10, 100, 10)
+	cb(50, 100, 50)
+	cb(90, 100, 90)
+	cb(100, 100, 100)
+
+	if len(calls) != 2 || calls[0] != 10 || calls[1] != 100 {
+		t.Errorf("Неверные вызовы callback: %v", calls)
+	}
+}
+
+func TestThrottleProgress_Interval(t *testing.T) {
+	count := 0
+	cb := ThrottleProgress(20*time.Millisecond, func(bytesTransferred, totalBytes int64, percentage float64) {
+		count++
+	})
+
+	cb(1, 100, 1)
+	cb(2, 100, 2)
+	time.Sleep(30 * time.Millisecond)
+	cb(3, 100, 3)
+
+	if count != 2 {
+		t.Errorf("Ожидалось 2 вызова, получено %d", count)
+	}
+}
+
+func TestThrottleProgress_Disabled(t *testing.T) {
+	if ThrottleProgress(time.Second, nil) != nil {
+		t.Error("Для nil callback должен возвращаться nil")
+	}
+
+	count := 0
+	cb := ThrottleProgress(0, func(bytesTransferred, totalBytes int64, percentage float64) {
+		count++
+	})
+	for i := 0; i < 5; i++ {
+		cb(int64(i), 100, float64(i))
+	}
+	if count != 5 {
+		t.Errorf("При нулевом интервале ожидалось 5 вызовов, получено %d", count)
+	}
+}
+
+func TestUploadFileWithProgress_JSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, make([]byte, 100*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var output bytes.Buffer
+	config := DefaultConfig()
+	config.BufferSize = 16 * 1024
+	config.ProgressInterval = 0
+	config.ProgressFormat = ProgressFormatJSON
+	config.ProgressOutput = &output
+	if err := NewHTTPClientWithConfig(config).UploadFileWithProgress(context.Background(), testFile, server.URL); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Каждая строка — отдельный JSON-объект
+	var lines []map[string]any
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Строка не является JSON: %q", scanner.Text())
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		t.Fatal("Прогресс не выведен")
+	}
+
+	last := lines[len(lines)-1]
+	for _, key := range []string{"file", "bytes", "total", "pct", "speed_bps", "eta_sec", "transport"} {
+		if _, ok := last[key]; !ok {
+			t.Errorf("В строке прогресса нет поля %s: %v", key, last)
+		}
+	}
+	if last["file"] != "report.bin" || last["bytes"] != float64(100*1024) || last["pct"] != float64(100) || last["eta_sec"] != float64(0) {
+		t.Errorf("Неверная последняя строка прогресса: %v", last)
+	}
+}
+
+func TestUploadFileWithProgress_UnknownFormat(t *testing.T) {
+	config := DefaultConfig()
+	config.ProgressFormat = "xml"
+	if err := NewHTTPClientWithConfig(config).UploadFileWithProgress(context.Background(), "file.bin", "http://localhost"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного формата прогресса")
+	}
+}
+
+func TestUploadFileWithProgressWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, make([]byte, 64*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	// Прогресс пишется в переданный writer, а не в ProgressOutput
+	var configured, output bytes.Buffer
+	config := DefaultConfig()
+	config.ProgressInterval = 0
+	config.ProgressFormat = ProgressFormatJSON
+	config.ProgressOutput = &configured
+	if err := NewHTTPClientWithConfig(config).UploadFileWithProgressWriter(context.Background(), testFile, server.URL, &output); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	if configured.Len() != 0 {
+		t.Errorf("Прогресс не должен выводиться в ProgressOutput: %q", configured.String())
+	}
+	if !strings.Contains(output.String(), `"file":"report.bin"`) {
+		t.Errorf("Прогресс не выведен в writer: %q", output.String())
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// defaultProgressBarWidth ширина полосы прогресса в символах по умолчанию
+const defaultProgressBarWidth = 40
+
+// ProgressBarRenderer формирует строку прогресса с полосой:
+// [=====>    ] 45.2% (4.5 MB / 10.0 MB) 2.3 MB/s ETA 3s
+type ProgressBarRenderer struct {
+	Width int // Ширина полосы в символах (0 — 40)
+}
+
+// Render возвращает строку прогресса без перевода строки. speed — скорость в байтах
+// в секунду; отрицательное eta означает, что оценка еще не готова. При неизвестном
+// размере (totalBytes <= 0) выводятся только переданные байты и скорость
+func (r *ProgressBarRenderer) Render(bytesTransferred, totalBytes int64, speed float64, eta time.Duration) string {
+	width := r.Width
+	if width <= 0 {
+		width = defaultProgressBarWidth
+	}
+
+	var b strings.Builder
+	if totalBytes > 0 {
+		fraction := min(float64(bytesTransferred)/float64(totalBytes), 1)
+		filled := int(fraction * float64(width))
+		b.WriteByte('[')
+		b.WriteString(strings.Repeat("=", filled))
+		if filled < width {
+			b.WriteByte('>')
+			b.WriteString(strings.Repeat(" ", width-filled-1))
+		}
+		fmt.Fprintf(&b, "] %.1f%% (%s / %s)", fraction*100, formatBytes(bytesTransferred), formatBytes(totalBytes))
+	} else {
+		fmt.Fprintf(&b, "[%s] (%s)", strings.Repeat(" ", width), formatBytes(bytesTransferred))
+	}
+
+	fmt.Fprintf(&b, " %s/s", formatBytes(int64(speed)))
+	if totalBytes > 0 {
+		if eta < 0 {
+			b.WriteString(" ETA --")
+		} else {
+			fmt.Fprintf(&b, " ETA %s", eta.Round(time.Second))
+		}
+	}
+	return b.String()
+}
+
+// isTerminal сообщает, выводится ли w в терминал. Проверка режима файла не подходит:
+// /dev/null тоже символьное устройство
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// barProgress возвращает callback, который выводит в w полосу прогресса. В терминале
+// строка перерисовывается на месте (\r и ANSI-очистка строки), иначе каждое обновление
+// выводится отдельной строкой, чтобы вывод в файл или CI оставался читаемым.
+// Оставшееся время оценивается по скорости, сглаженной с коэффициентом alpha
+func barProgress(w io.Writer, warmup time.Duration, alpha float64) ProgressCallback {
+	var mu sync.Mutex
+	renderer := &ProgressBarRenderer{}
+	meter := newSpeedMeter(time.Now(), warmup, alpha)
+	terminal := isTerminal(w)
+
+	return func(bytesTransferred, totalBytes int64, percentage float64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		meter.add(now, bytesTransferred)
+		eta := time.Duration(-1)
+		if remaining, ok := meter.eta(now, bytesTransferred, totalBytes); ok {
+			eta = remaining
+		} else if totalBytes > 0 && bytesTransferred >= totalBytes {
+			eta = 0
+		}
+
+		line := renderer.Render(bytesTransferred, totalBytes, meter.speed(), eta)
+		switch {
+		case !terminal:
+			fmt.Fprintln(w, line)
+		case percentage >= 100:
+			fmt.Fprintf(w, "\r\x1b[2K%s\n", line)
+		default:
+			fmt.Fprintf(w, "\r\x1b[2K%s", line)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBarRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		bytes    int64
+		total    int64
+		speed    float64
+		eta      time.Duration
+		expected string
+	}{
+		{
+			name:  "в процессе",
+			width: 10, bytes: 4608 * 1024, total: 10 * 1024 * 1024, speed: 2.3 * 1024 * 1024, eta: 3 * time.Second,
+			expected: "[====>     ] 45.0% (4.5 MB / 10.0 MB) 2.3 MB/s ETA 3s",
+		},
+		{
+			name:  "оценка не готова",
+			width: 4, bytes: 0, total: 1024, speed: 0, eta: -1,
+			expected: "[>   ] 0.0% (0 B / 1.0 KB) 0 B/s ETA --",
+		},
+		{
+			name:  "завершено",
+			width: 4, bytes: 1024, total: 1024, speed: 512, eta: 0,
+			expected: "[====] 100.0% (1.0 KB / 1.0 KB) 512 B/s ETA 0s",
+		},
+		{
+			name:  "размер неизвестен",
+			width: 4, bytes: 2048, total: 0, speed: 1024, eta: -1,
+			expected: "[    ] (2.0 KB) 1.0 KB/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := &ProgressBarRenderer{Width: tt.width}
+			if got := renderer.Render(tt.bytes, tt.total, tt.speed, tt.eta); got != tt.expected {
+				t.Errorf("Render() = %q, ожидалось %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProgressBarRenderer_DefaultWidth(t *testing.T) {
+	line := (&ProgressBarRenderer{}).Render(0, 100, 0, -1)
+	if bar := line[:strings.IndexByte(line, ']')+1]; len(bar) != defaultProgressBarWidth+2 {
+		t.Errorf("Ширина полосы %d, ожидалось %d: %q", len(bar)-2, defaultProgressBarWidth, line)
+	}
+}
+
+func TestUploadFileWithProgress_BarNonTerminal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "report.bin")
+	if err := os.WriteFile(testFile, make([]byte, 100*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var output bytes.Buffer
+	config := DefaultConfig()
+	config.BufferSize = 16 * 1024
+	config.ProgressInterval = 0
+	config.ProgressOutput = &output
+	if err := NewHTTPClientWithConfig(config).UploadFileWithProgress(context.Background(), testFile, server.URL); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Вне терминала каждое обновление выводится отдельной строкой без управляющих последовательностей
+	if strings.ContainsAny(output.String(), "\r\x1b") {
+		t.Errorf("Вывод вне терминала содержит управляющие символы: %q", output.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "["+strings.Repeat("=", defaultProgressBarWidth)+"] 100.0% (100.0 KB / 100.0 KB)") {
+		t.Errorf("Неверная последняя строка прогресса: %q", last)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	// /dev/null — символьное устройство, но не терминал
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Ошибка открытия %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	defer file.Close()
+
+	for _, w := range []io.Writer{devNull, file, &bytes.Buffer{}} {
+		if isTerminal(w) {
+			t.Errorf("%T не должен считаться терминалом", w)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressEventsBuffer размер буфера канала ProgressChan
+const progressEventsBuffer = 256
+
+// ProgressEvent событие прогресса загрузки из канала ProgressChan
+type ProgressEvent struct {
+	FilePath         string  // Путь локального файла ("-" для UploadReader, URL источника для UploadFromURL, путь в архиве для UploadArchiveContents)
+	BytesTransferred int64   // Отправлено байт в текущей попытке
+	TotalBytes       int64   // Размер файла (0, если неизвестен)
+	Percentage       float64 // Процент выполнения (0, если размер неизвестен)
+	Speed            float64 // Скорость в байтах в секунду за последние секунды передачи
+	Done             bool    // Загрузка завершена: успешно или с ошибкой Err
+	Err              error   // Ошибка загрузки (только при Done)
+}
+
+// progressEvents канал событий прогресса, общий для клиента и его копий
+// из WithAuth и WithLoggingTransport
+type progressEvents struct {
+	once    sync.Once
+	ch      chan ProgressEvent
+	enabled atomic.Bool // Канал создан; до вызова ProgressChan события не формируются
+}
+
+// ProgressChan возвращает канал событий прогресса всех загрузок файлов клиента: UploadFile,
+// UploadMultipleFiles, UploadDirectory, UploadReader, UploadFromURL, UploadArchiveContents и ChunkedUpload. Callback
+// прогресса, переданные в эти методы, продолжают вызываться. События формируются только после
+// первого вызова ProgressChan, с частотой ClientConfig.ProgressInterval; по завершении каждой
+// загрузки приходит событие с Done. Канал нужно читать параллельно с загрузками: при заполненном
+// буфере промежуточные события пропускаются, а событие завершения ждет читателя. Канал не закрывается
+func (c *HTTPClient) ProgressChan() <-chan ProgressEvent {
+	c.events.once.Do(func() {
+		c.events.ch = make(chan ProgressEvent, progressEventsBuffer)
+		c.events.enabled.Store(true)
+	})
+	return c.events.ch
+}
+
+// trackProgress оборачивает callback загрузки filePath так, чтобы каждый его вызов отправлял
+// событие в канал ProgressChan, и возвращает функцию, сообщающую о завершении загрузки.
+// Если канал не запрашивался, callback возвращается без изменений
+func (c *HTTPClient) trackProgress(filePath string, cb ProgressCallback) (ProgressCallback, func(error)) {
+	if !c.events.enabled.Load() {
+		return cb, func(error) {}
+	}
+
+	var mu sync.Mutex
+	meter := newSpeedMeter(time.Now(), 0, c.config.EMAAlpha)
+	last := ProgressEvent{FilePath: filePath}
+
+	tracked := func(bytesTransferred, totalBytes int64, percentage float64) {
+		if cb != nil {
+			cb(bytesTransferred, totalBytes, percentage)
+		}
+
+		mu.Lock()
+		meter.add(time.Now(), bytesTransferred)
+		last = ProgressEvent{
+			FilePath:         filePath,
+			BytesTransferred: bytesTransferred,
+			TotalBytes:       totalBytes,
+			Percentage:       percentage,
+			Speed:            meter.speed(),
+		}
+		event := last
+		mu.Unlock()
+
+		select {
+		case c.events.ch <- event:
+		default:
+		}
+	}
+
+	done := func(err error) {
+		mu.Lock()
+		event := last
+		mu.Unlock()
+		event.Done, event.Err = true, err
+		c.events.ch <- event
+	}
+	return tracked, done
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProgressChan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(testFile, make([]byte, 100*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.BufferSize = 16 * 1024
+	config.ProgressInterval = 0
+	httpClient := NewHTTPClientWithConfig(config)
+	events := httpClient.ProgressChan()
+	if httpClient.ProgressChan() != events {
+		t.Fatal("Повторный вызов должен возвращать тот же канал")
+	}
+
+	// Callback продолжает вызываться вместе с каналом
+	callbacks := 0
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, func(int64, int64, float64) { callbacks++ }); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if callbacks == 0 {
+		t.Error("Callback прогресса не вызван")
+	}
+
+	var progress []ProgressEvent
+	var done ProgressEvent
+	for done.FilePath == "" {
+		event := <-events
+		if event.Done {
+			done = event
+		} else {
+			progress = append(progress, event)
+		}
+	}
+	if len(progress) != callbacks {
+		t.Errorf("Ожидалось %d событий прогресса, получено %d", callbacks, len(progress))
+	}
+	if done.FilePath != testFile || done.Err != nil || done.BytesTransferred != 100*1024 || done.TotalBytes != 100*1024 || done.Percentage != 100 {
+		t.Errorf("Неверное событие завершения: %+v", done)
+	}
+
+	// Ошибка загрузки передается в событии завершения
+	missing := filepath.Join(t.TempDir(), "missing.bin")
+	if err := httpClient.UploadFile(context.Background(), missing, server.URL, nil); err == nil {
+		t.Fatal("Ожидалась ошибка для несуществующего файла")
+	}
+	if event := <-events; !event.Done || event.FilePath != missing || event.Err == nil {
+		t.Errorf("Неверное событие ошибки: %+v", event)
+	}
+
+	// Успешная загрузка из потока завершается событием без ошибки
+	if err := httpClient.UploadReader(context.Background(), strings.NewReader("stream"), "stream.bin", server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки из потока: %v", err)
+	}
+	for event := range events {
+		if event.Done {
+			if event.FilePath != "-" || event.Err != nil || event.BytesTransferred != 6 {
+				t.Errorf("Неверное событие завершения загрузки из потока: %+v", event)
+			}
+			break
+		}
+	}
+}
+
+func TestProgressChan_NotRequested(t *testing.T) {
+	httpClient := NewHTTPClient(0)
+	cb, finish := httpClient.trackProgress("file.bin", nil)
+	if cb != nil {
+		t.Error("Без ProgressChan callback не должен оборачиваться")
+	}
+	finish(nil)
+}
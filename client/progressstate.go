@@ -0,0 +1,136 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProgressState состояние загрузки, сохраняемое в ClientConfig.ProgressStatePath
+type ProgressState struct {
+	FilePath         string    `json:"filepath"` // Абсолютный путь файла
+	BytesTransferred int64     `json:"bytes_transferred"`
+	TotalBytes       int64     `json:"total_bytes"`
+	StartTime        time.Time `json:"start_time"` // Начало первой попытки, в том числе до перезапуска клиента
+
+	// Размер и время изменения файла: состояние измененного файла не используется
+	FileSize    int64     `json:"file_size"`
+	FileModTime time.Time `json:"file_mod_time"`
+}
+
+// ProgressState возвращает сохраненное состояние загрузки filePath или nil, если
+// ProgressStatePath не задан, состояния нет, оно относится к другому файлу или файл
+// с тех пор изменился (другие размер или время изменения)
+func (c *HTTPClient) ProgressState(filePath string) (*ProgressState, error) {
+	if c.config.ProgressStatePath == "" {
+		return nil, nil
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+
+	data, err := os.ReadFile(c.config.ProgressStatePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения состояния загрузки: %w", err)
+	}
+	var state ProgressState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("ошибка разбора состояния загрузки: %w", err)
+	}
+
+	if state.FilePath != absPath || state.FileSize != info.Size() || !state.FileModTime.Equal(info.ModTime()) {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// saveProgressState записывает состояние через временный файл, поэтому
+// прерванная запись не оставляет неполный JSON
+func (c *HTTPClient) saveProgressState(state *ProgressState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	path := c.config.ProgressStatePath
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp.progress.")
+	if err != nil {
+		return fmt.Errorf("ошибка записи состояния загрузки: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("ошибка записи состояния загрузки: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("ошибка записи состояния загрузки: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("ошибка записи состояния загрузки: %w", err)
+	}
+	return nil
+}
+
+// clearProgressState удаляет состояние загрузки filePath после ее успешного завершения.
+// Состояние другого файла не затрагивается
+func (c *HTTPClient) clearProgressState(filePath string) {
+	state, err := c.ProgressState(filePath)
+	if err != nil || state == nil {
+		return
+	}
+	if err := os.Remove(c.config.ProgressStatePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.logger().Warn("Не удалось удалить состояние загрузки", "path", c.config.ProgressStatePath, "error", err)
+	}
+}
+
+// persistProgress возвращает callback, который после next сохраняет прогресс
+// в ProgressStatePath. Время начала берется из состояния прерванной загрузки того же файла
+func (c *HTTPClient) persistProgress(filePath string, next ProgressCallback) ProgressCallback {
+	if c.config.ProgressStatePath == "" {
+		return next
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return next
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		// Ошибку открытия файла вернет UploadFile
+		return next
+	}
+
+	state := &ProgressState{FilePath: absPath, StartTime: time.Now(), FileSize: info.Size(), FileModTime: info.ModTime()}
+	if previous, err := c.ProgressState(filePath); err == nil && previous != nil {
+		state.StartTime = previous.StartTime
+	}
+
+	warned := false
+	return func(bytesTransferred, totalBytes int64, percentage float64) {
+		if next != nil {
+			next(bytesTransferred, totalBytes, percentage)
+		}
+		state.BytesTransferred = bytesTransferred
+		state.TotalBytes = totalBytes
+		if err := c.saveProgressState(state); err != nil && !warned {
+			// Ошибка записи состояния не прерывает загрузку
+			warned = true
+			c.logger().Warn("Не удалось сохранить состояние загрузки", "path", c.config.ProgressStatePath, "error", err)
+		}
+	}
+}
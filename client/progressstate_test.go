@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProgressState_Persisted(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "progress.json")
+	testFile := filepath.Join(dir, "large.bin")
+	content := []byte(strings.Repeat("x", 256*1024))
+	if err := os.WriteFile(testFile, content, 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var reject atomic.Bool
+	reject.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if reject.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.ProgressInterval = 0
+	config.BufferSize = 16 * 1024
+	config.ProgressStatePath = statePath
+	httpClient := NewHTTPClientWithConfig(config)
+
+	// Прерванная загрузка оставляет состояние
+	if err := httpClient.UploadFileWithProgress(context.Background(), testFile, server.URL); err == nil {
+		t.Fatal("Ожидалась ошибка загрузки")
+	}
+	state, err := httpClient.ProgressState(testFile)
+	if err != nil || state == nil {
+		t.Fatalf("Состояние загрузки не сохранено: %v", err)
+	}
+	absPath, _ := filepath.Abs(testFile)
+	if state.FilePath != absPath || state.BytesTransferred == 0 || state.TotalBytes != int64(len(content)) || state.StartTime.IsZero() {
+		t.Errorf("Неверное состояние: %+v", state)
+	}
+
+	// Новый клиент, как после перезапуска, сохраняет время начала и удаляет состояние после успеха
+	reject.Store(false)
+	restarted := NewHTTPClientWithConfig(config)
+	progress := restarted.persistProgress(testFile, nil)
+	progress(1, int64(len(content)), 0)
+	resumed, err := restarted.ProgressState(testFile)
+	if err != nil || resumed == nil || !resumed.StartTime.Equal(state.StartTime) {
+		t.Errorf("Время начала не сохранено после перезапуска: %+v, %v", resumed, err)
+	}
+	if err := restarted.UploadFileWithProgress(context.Background(), testFile, server.URL); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("Состояние не удалено после успешной загрузки: %v", err)
+	}
+}
+
+func TestProgressState_FileChanged(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "changed.bin")
+	if err := os.WriteFile(testFile, []byte("original"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.ProgressStatePath = filepath.Join(dir, "progress.json")
+	httpClient := NewHTTPClientWithConfig(config)
+	httpClient.persistProgress(testFile, nil)(4, 8, 50)
+
+	if state, err := httpClient.ProgressState(testFile); err != nil || state == nil {
+		t.Fatalf("Состояние не найдено: %v", err)
+	}
+	if state, _ := httpClient.ProgressState(filepath.Join(dir, "progress.json")); state != nil {
+		t.Error("Состояние другого файла не должно использоваться")
+	}
+
+	// Изменение времени модификации делает состояние недействительным
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(testFile, later, later)
+	if state, err := httpClient.ProgressState(testFile); err != nil || state != nil {
+		t.Errorf("Состояние измененного файла должно игнорироваться: %+v, %v", state, err)
+	}
+
+	// Состояние, не относящееся к неизмененному файлу, не удаляется
+	httpClient.clearProgressState(testFile)
+	if _, err := os.Stat(config.ProgressStatePath); err != nil {
+		t.Errorf("Состояние удалено: %v", err)
+	}
+}
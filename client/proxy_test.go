@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadFile_HTTPProxy(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "proxy.bin")
+	if err := os.WriteFile(testFile, []byte("proxy"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	// Прокси отвечает сам, запоминая адрес, к которому обращался клиент
+	var proxiedURL string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		proxiedURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxyServer.Close()
+
+	config := DefaultConfig()
+	config.ProxyURL = proxyServer.URL
+	httpClient := NewHTTPClientWithConfig(config)
+
+	target := "http://upload.example.invalid/upload"
+	if err := httpClient.UploadFile(context.Background(), testFile, target, nil); err != nil {
+		t.Fatalf("Ошибка загрузки через прокси: %v", err)
+	}
+	if proxiedURL != target {
+		t.Errorf("Ожидался запрос к %s через прокси, получен %q", target, proxiedURL)
+	}
+}
+
+func TestNewHTTPClientWithConfig_InvalidProxy(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "proxy.bin")
+	if err := os.WriteFile(testFile, []byte("proxy"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.ProxyURL = "ftp://proxy.example.invalid:21"
+	httpClient := NewHTTPClientWithConfig(config)
+
+	err := httpClient.UploadFile(context.Background(), testFile, "http://localhost:1/upload", nil)
+	if err == nil || !strings.Contains(err.Error(), "неподдерживаемая схема прокси") {
+		t.Errorf("Ожидалась ошибка схемы прокси, получена: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// queueJob задание в очереди загрузки
+type queueJob struct {
+	id       string
+	filePath string
+	priority int
+	seq      uint64 // Порядок постановки в очередь для заданий с одинаковым приоритетом
+}
+
+// jobHeap min-heap заданий: меньшее значение priority обрабатывается раньше
+type jobHeap []*queueJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) { *h = append(*h, x.(*queueJob)) }
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	job := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return job
+}
+
+// UploadQueue очередь загрузок с приоритетами. Задания с меньшим значением
+// priority запускаются раньше; одновременно выполняется не более
+// ClientConfig.MaxConcurrency загрузок. Результаты читаются из Results
+type UploadQueue struct {
+	client    *HTTPClient
+	serverURL string
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    jobHeap
+	seq     uint64
+	stopped bool
+
+	results chan UploadResult
+	done    chan struct{}
+}
+
+// NewUploadQueue создает очередь загрузок и запускает ее обработку
+func NewUploadQueue(config *ClientConfig, serverURL string) *UploadQueue {
+	httpClient := NewHTTPClientWithConfig(config)
+
+	q := &UploadQueue{
+		client:    httpClient,
+		serverURL: serverURL,
+		results:   make(chan UploadResult, cap(httpClient.sem)),
+		done:      make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	go q.dispatch()
+	return q
+}
+
+// Enqueue ставит файл в очередь и возвращает идентификатор задания
+func (q *UploadQueue) Enqueue(filePath string, priority int) (string, error) {
+	if _, err := os.Stat(filePath); err != nil {
+		return "", fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return "", fmt.Errorf("очередь остановлена")
+	}
+
+	q.seq++
+	job := &queueJob{
+		id:       fmt.Sprintf("job-%d", q.seq),
+		filePath: filePath,
+		priority: priority,
+		seq:      q.seq,
+	}
+	heap.Push(&q.jobs, job)
+	q.cond.Signal()
+
+	return job.id, nil
+}
+
+// Results возвращает канал результатов. Канал закрывается после Stop,
+// когда все поставленные в очередь задания завершены. Результаты нужно
+// читать, иначе обработка очереди остановится
+func (q *UploadQueue) Results() <-chan UploadResult {
+	return q.results
+}
+
+// Stop прекращает прием новых заданий и дожидается завершения всех
+// уже поставленных в очередь загрузок
+func (q *UploadQueue) Stop() error {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return fmt.Errorf("очередь уже остановлена")
+	}
+	q.stopped = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	<-q.done
+	return nil
+}
+
+// dispatch запускает задания по приоритету по мере освобождения слотов семафора
+func (q *UploadQueue) dispatch() {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(q.results)
+		close(q.done)
+	}()
+
+	for {
+		// Слот занимаем до выбора задания, чтобы задание с высоким
+		// приоритетом, поставленное позже, не обогнали уже извлеченные
+		q.client.sem <- struct{}{}
+
+		q.mu.Lock()
+		for len(q.jobs) == 0 && !q.stopped {
+			q.cond.Wait()
+		}
+		if len(q.jobs) == 0 {
+			q.mu.Unlock()
+			<-q.client.sem
+			return
+		}
+		job := heap.Pop(&q.jobs).(*queueJob)
+		q.mu.Unlock()
+
+		wg.Add(1)
+		go func(job *queueJob) {
+			defer wg.Done()
+			defer func() { <-q.client.sem }()
+
+			task := uploadTask{filePath: job.filePath, skipped: new(bool)}
+			startTime := time.Now()
+			sessionID, acceptedURL, err := q.client.uploadWithRetry(context.Background(), task, q.serverURL, nil)
+			q.results <- UploadResult{
+				JobID:      job.id,
+				LocalPath:  job.filePath,
+				RemoteName: task.formFileName(),
+				SessionID:  sessionID,
+				ServerURL:  acceptedURL,
+				Duration:   time.Since(startTime),
+				Skipped:    *task.skipped,
+				Err:        err,
+			}
+		}(job)
+	}
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUploadQueue_PriorityOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	createFile := func(name string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Ошибка создания файла: %v", err)
+		}
+		return path
+	}
+
+	release := make(chan struct{})
+	var once sync.Once
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.Copy(io.Discard, part)
+
+		// Первая загрузка ждет, пока в очередь поставят остальные задания
+		once.Do(func() { <-release })
+
+		mu.Lock()
+		order = append(order, part.FileName())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.MaxConcurrency = 1
+	queue := NewUploadQueue(config, server.URL)
+
+	if _, err := queue.Enqueue(createFile("first.bin"), 5); err != nil {
+		t.Fatalf("Ошибка постановки в очередь: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	jobs := []struct {
+		name     string
+		priority int
+	}{
+		{"low.bin", 10},
+		{"high.bin", 1},
+		{"medium_a.bin", 5},
+		{"medium_b.bin", 5},
+	}
+	jobIDs := make(map[string]bool)
+	for _, job := range jobs {
+		id, err := queue.Enqueue(createFile(job.name), job.priority)
+		if err != nil {
+			t.Fatalf("Ошибка постановки в очередь: %v", err)
+		}
+		jobIDs[id] = true
+	}
+	if len(jobIDs) != len(jobs) {
+		t.Errorf("Идентификаторы заданий должны быть уникальными: %v", jobIDs)
+	}
+	close(release)
+
+	var results []UploadResult
+	collected := make(chan struct{})
+	go func() {
+		for result := range queue.Results() {
+			results = append(results, result)
+		}
+		close(collected)
+	}()
+
+	if err := queue.Stop(); err != nil {
+		t.Fatalf("Ошибка остановки очереди: %v", err)
+	}
+	<-collected
+
+	if _, err := queue.Enqueue(createFile("late.bin"), 0); err == nil {
+		t.Error("Остановленная очередь не должна принимать задания")
+	}
+
+	for _, result := range results {
+		if result.Err != nil || result.JobID == "" {
+			t.Errorf("Неожиданный результат: %+v", result)
+		}
+	}
+
+	expected := "first.bin,high.bin,medium_a.bin,medium_b.bin,low.bin"
+	if got := strings.Join(order, ","); got != expected {
+		t.Errorf("Ожидался порядок %s, получен %s", expected, got)
+	}
+	if len(results) != 5 {
+		t.Errorf("Ожидалось 5 результатов, получено %d", len(results))
+	}
+}
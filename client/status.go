@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Состояния сессии загрузки на сервере
+const (
+	SessionInProgress = "in_progress"
+	SessionComplete   = "complete"
+	SessionFailed     = "failed"
+)
+
+// UploadStatus состояние сессии загрузки на сервере
+type UploadStatus struct {
+	State         string `json:"state"` // in_progress, complete или failed
+	BytesReceived int64  `json:"bytes_received"`
+	Filename      string `json:"filename"`
+}
+
+// UploadStatus запрашивает состояние сессии загрузки. serverURL — адрес сервера,
+// путь URL заменяется на /upload/status/{sessionID}. Идентификатор сессии
+// возвращается в UploadResult.SessionID
+func (c *HTTPClient) UploadStatus(ctx context.Context, sessionID, serverURL string) (*UploadStatus, error) {
+	if c.initErr != nil {
+		return nil, c.initErr
+	}
+
+	statusURL, err := endpointURL(serverURL, "/upload/status/"+sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL.String(), nil)
+	if err != nil {
+		return nil, newUploadError("ошибка создания HTTP запроса", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var status UploadStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("ошибка разбора состояния загрузки: %w", err)
+	}
+	return &status, nil
+}
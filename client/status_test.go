@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadStatus(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	testFile := filepath.Join(t.TempDir(), "tracked.bin")
+	if err := os.WriteFile(testFile, []byte("tracked"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	ctx := context.Background()
+	httpClient := NewHTTPClient(10 * time.Second)
+	results, err := httpClient.uploadTasks(ctx, []uploadTask{{filePath: testFile}}, ts.URL+"/upload", nil)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if results[0].SessionID == "" {
+		t.Fatal("Идентификатор сессии не получен")
+	}
+
+	status, err := httpClient.UploadStatus(ctx, results[0].SessionID, ts.URL+"/upload")
+	if err != nil {
+		t.Fatalf("Ошибка получения состояния: %v", err)
+	}
+	if status.State != SessionComplete || status.BytesReceived != 7 || status.Filename != "tracked.bin" {
+		t.Errorf("Неверное состояние загрузки: %+v", status)
+	}
+
+	if _, err := httpClient.UploadStatus(ctx, "unknown", ts.URL); err == nil {
+		t.Error("Ожидалась ошибка для неизвестной сессии")
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// UploadReader загружает содержимое потока r под именем filename. Размер потока
+// неизвестен, поэтому callback прогресса получает только количество отправленных
+// байт (totalBytes и percentage равны 0). Поток нельзя прочитать повторно,
+// поэтому выполняется одна попытка без повторов
+func (c *HTTPClient) UploadReader(ctx context.Context, r io.Reader, filename, serverURL string, progressCallback ProgressCallback) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	if filename == "" {
+		return fmt.Errorf("не указано имя файла")
+	}
+	if c.config.CompressUpload && len(c.config.EncryptionKey) > 0 {
+		return fmt.Errorf("сжатие не поддерживается вместе с шифрованием")
+	}
+
+	if err := c.acquireSlot(ctx); err != nil {
+		return err
+	}
+	defer func() { <-c.sem }()
+
+	task := c.withCorrelationID(uploadTask{filePath: "-", remoteName: filename})
+	logger := c.logger().With("file", filename, "url", serverURL, "correlation_id", c.correlationID(task))
+	logger.Info("Начало загрузки из потока")
+	startTime := time.Now()
+
+	progressCallback, finish := c.trackProgress(task.filePath, c.serializeProgress(progressCallback))
+	ctx, span := c.startUploadSpan(ctx, task, serverURL, 0)
+	sessionID, err := c.sendStream(ctx, r, 0, task, serverURL, ThrottleProgress(c.config.ProgressInterval, progressCallback))
+	endUploadSpan(span, err)
+	if err != nil {
+		finish(err)
+		logger.Error("Ошибка загрузки", "error", err)
+		return err
+	}
+	finish(nil)
+
+	logger.Info("Загрузка завершена", "duration", time.Since(startTime).Round(time.Millisecond), "session_id", sessionID)
+	return nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadReader(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	ts := httptest.NewServer(server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend: backend,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler())
+	defer ts.Close()
+
+	content := bytes.Repeat([]byte("stream"), 50000)
+	config := DefaultConfig()
+	config.BufferSize = 32 * 1024
+	config.ProgressInterval = 0
+	httpClient := NewHTTPClientWithConfig(config)
+
+	// MultiReader скрывает размер данных, как у stdin
+	var lastBytes, lastTotal int64
+	var lastPercentage float64
+	err := httpClient.UploadReader(context.Background(), io.MultiReader(bytes.NewReader(content)), "data.tar.gz", ts.URL+"/upload",
+		func(bytesTransferred, totalBytes int64, percentage float64) {
+			lastBytes, lastTotal, lastPercentage = bytesTransferred, totalBytes, percentage
+		})
+	if err != nil {
+		t.Fatalf("Ошибка загрузки из потока: %v", err)
+	}
+
+	received, ok := backend.Contents("data.tar.gz")
+	if !ok || !bytes.Equal(received, content) {
+		t.Errorf("Сервер получил %d байт вместо %d", len(received), len(content))
+	}
+
+	// Размер потока неизвестен: сообщается только количество отправленных байт
+	if lastBytes != int64(len(content)) || lastTotal != 0 || lastPercentage != 0 {
+		t.Errorf("Неверный прогресс: %d байт, total %d, %.2f%%", lastBytes, lastTotal, lastPercentage)
+	}
+}
+
+func TestUploadReader_Errors(t *testing.T) {
+	httpClient := NewHTTPClient(time.Second)
+
+	if err := httpClient.UploadReader(context.Background(), strings.NewReader("data"), "", "http://localhost", nil); err == nil {
+		t.Error("Ожидалась ошибка без имени файла")
+	}
+
+	// Отказ сервера не повторяется: поток уже прочитан
+	ts := httptest.NewServer(nil)
+	defer ts.Close()
+	if err := httpClient.UploadReader(context.Background(), strings.NewReader("data"), "a.bin", ts.URL+"/missing", nil); err == nil {
+		t.Error("Ожидалась ошибка для ответа 404")
+	}
+}
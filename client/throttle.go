@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket ограничивает скорость передачи. Корзина вмещает объем данных
+// за одну секунду, поэтому после паузы допускается короткий всплеск, а средняя
+// скорость не превышает rate. Одна корзина используется всеми загрузками клиента
+type tokenBucket struct {
+	rate  float64 // Байт в секунду
+	burst float64 // Емкость корзины в байтах
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newTokenBucket создает корзину со скоростью bytesPerSec, заполненную целиком
+func newTokenBucket(bytesPerSec int64) *tokenBucket {
+	rate := float64(bytesPerSec)
+	return &tokenBucket{
+		rate:   rate,
+		burst:  rate,
+		tokens: rate,
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// wait резервирует n байт и ждет, пока резерв не будет покрыт накопленными токенами.
+// Если n больше емкости корзины, токены уходят в минус и ожидание пропорционально долгу
+func (b *tokenBucket) wait(ctx context.Context, n int64) error {
+	b.mu.Lock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens -= float64(n)
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+	return b.sleep(ctx, time.Duration(deficit/b.rate*float64(time.Second)))
+}
+
+// sleepContext ждет d или отмены контекста
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttle ждет, пока ограничение скорости позволит отправить n байт
+func (c *HTTPClient) throttle(ctx context.Context, n int64) error {
+	if c.limiter == nil || n <= 0 {
+		return nil
+	}
+	return c.limiter.wait(ctx, n)
+}
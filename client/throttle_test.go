@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeClock время, которое продвигается только при ожидании
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	f.now = f.now.Add(d)
+	f.slept += d
+	return nil
+}
+
+// newFakeBucket создает корзину, работающую по фальшивым часам
+func newFakeBucket(bytesPerSec int64) (*tokenBucket, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	bucket := newTokenBucket(bytesPerSec)
+	bucket.now = clock.Now
+	bucket.sleep = clock.Sleep
+	bucket.last = clock.now
+	return bucket, clock
+}
+
+func TestTokenBucket_Rate(t *testing.T) {
+	bucket, clock := newFakeBucket(1000)
+
+	// Первая секунда передается сразу из заполненной корзины, остальное — со скоростью 1000 байт/с
+	for i := 0; i < 100; i++ {
+		if err := bucket.wait(context.Background(), 100); err != nil {
+			t.Fatalf("Неожиданная ошибка: %v", err)
+		}
+	}
+	if clock.slept != 9*time.Second {
+		t.Errorf("Ожидалось ожидание 9s для 10000 байт, получено %v", clock.slept)
+	}
+}
+
+func TestTokenBucket_Burst(t *testing.T) {
+	bucket, clock := newFakeBucket(1000)
+
+	// Блок больше емкости корзины: ожидание пропорционально превышению
+	bucket.wait(context.Background(), 3000)
+	if clock.slept != 2*time.Second {
+		t.Errorf("Ожидалось ожидание 2s, получено %v", clock.slept)
+	}
+
+	// После долгого простоя корзина заполняется не больше чем на секунду
+	clock.now = clock.now.Add(time.Hour)
+	clock.slept = 0
+	bucket.wait(context.Background(), 1000)
+	bucket.wait(context.Background(), 1000)
+	if clock.slept != time.Second {
+		t.Errorf("Ожидалось ожидание 1s после простоя, получено %v", clock.slept)
+	}
+}
+
+func TestTokenBucket_ContextCanceled(t *testing.T) {
+	bucket := newTokenBucket(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := bucket.wait(ctx, 1000); err != context.Canceled {
+		t.Errorf("Ожидалась ошибка context.Canceled, получено %v", err)
+	}
+}
+
+func TestUploadFile_Throttled(t *testing.T) {
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		received = r.ContentLength
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "throttled.bin")
+	if err := os.WriteFile(testFile, make([]byte, 256*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.BufferSize = 16 * 1024
+	config.MaxUploadBytesPerSec = 1024 * 1024
+	httpClient := NewHTTPClientWithConfig(config)
+
+	clock := &fakeClock{now: time.Now()}
+	httpClient.limiter.now = clock.Now
+	httpClient.limiter.sleep = clock.Sleep
+	httpClient.limiter.last = clock.now
+	httpClient.limiter.tokens = 0
+
+	if err := httpClient.UploadFile(context.Background(), testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if received == 0 {
+		t.Fatal("Сервер не получил данные")
+	}
+
+	// При пустой корзине 256KB со скоростью 1MB/s занимают четверть секунды
+	if clock.slept != 250*time.Millisecond {
+		t.Errorf("Ожидалось ожидание 250ms, получено %v", clock.slept)
+	}
+}
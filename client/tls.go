@@ -0,0 +1,51 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig возвращает настройки TLS клиента или nil, если TLSCertFile, TLSCACertFile
+// и TLSInsecureSkipVerify не заданы (используются системные корневые сертификаты).
+// Используется в NewHTTPClientWithConfig и клиентах других транспортов
+func (c *ClientConfig) TLSConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" && c.TLSCACertFile == "" && !c.TLSInsecureSkipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: c.TLSInsecureSkipVerify}
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			return nil, fmt.Errorf("сертификат и ключ клиента должны быть указаны вместе")
+		}
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки сертификата клиента: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.TLSCACertFile != "" {
+		pool, err := loadCertPool(c.TLSCACertFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// loadCertPool загружает сертификаты CA из PEM-файла
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения сертификата CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("файл %s не содержит сертификатов в формате PEM", path)
+	}
+	return pool, nil
+}
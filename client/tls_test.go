@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+// testCA самоподписанный CA для выпуска тестовых сертификатов
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+// newTestCA создает CA и записывает его сертификат в ca.pem
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Ошибка генерации ключа: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Ошибка создания сертификата CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, filepath.Join(ca.dir, "ca.pem"), "CERTIFICATE", der)
+	return ca
+}
+
+// issue выпускает сертификат и возвращает пути к файлам сертификата и ключа
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Ошибка генерации ключа: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Ошибка создания сертификата: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Ошибка кодирования ключа: %v", err)
+	}
+
+	certFile := filepath.Join(ca.dir, name+".pem")
+	keyFile := filepath.Join(ca.dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+// writePEM записывает данные в PEM-файл
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("Ошибка записи %s: %v", path, err)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	config := &server.ServerConfig{
+		Backend:     server.NewMemoryStorageBackend(),
+		TLSCertFile: serverCert,
+		TLSKeyFile:  serverKey,
+		ClientCA:    filepath.Join(ca.dir, "ca.pem"),
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		t.Fatalf("Ошибка настройки TLS сервера: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(server.NewHTTPServerWithConfig(config).Handler())
+	ts.TLS = tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "mtls.bin")
+	if err := os.WriteFile(testFile, []byte("mtls"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	// newClient создает клиент, доверяющий тестовому CA
+	newClient := func(clientConfig *ClientConfig) *HTTPClient {
+		clientConfig.RetryAttempts = 0
+		httpClient := NewHTTPClientWithConfig(clientConfig)
+		transport := httpClient.client.Transport.(*statsTransport).base
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+		transport.TLSClientConfig.RootCAs.AddCert(ca.cert)
+		return httpClient
+	}
+
+	withCert := DefaultConfig()
+	withCert.TLSCertFile = clientCert
+	withCert.TLSKeyFile = clientKey
+	if err := newClient(withCert).UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Errorf("Ошибка загрузки с сертификатом клиента: %v", err)
+	}
+
+	// Без сертификата клиента сервер разрывает соединение при рукопожатии
+	if err := newClient(DefaultConfig()).UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err == nil {
+		t.Error("Ожидалась ошибка загрузки без сертификата клиента")
+	}
+}
+
+func TestClientCertConfigErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.TLSCertFile = "client.pem"
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), "missing.bin", "https://localhost", nil); err == nil {
+		t.Error("Ожидалась ошибка для сертификата без ключа")
+	}
+
+	config.TLSKeyFile = filepath.Join(t.TempDir(), "missing-key.pem")
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), "missing.bin", "https://localhost", nil); err == nil {
+		t.Error("Ожидалась ошибка для отсутствующих файлов сертификата")
+	}
+}
+
+func TestTLSCACertFile(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+
+	config := &server.ServerConfig{
+		Backend:     server.NewMemoryStorageBackend(),
+		TLSCertFile: serverCert,
+		TLSKeyFile:  serverKey,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		t.Fatalf("Ошибка настройки TLS сервера: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(server.NewHTTPServerWithConfig(config).Handler())
+	ts.TLS = tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	testFile := filepath.Join(t.TempDir(), "ca.bin")
+	if err := os.WriteFile(testFile, []byte("custom CA"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(*ClientConfig)
+		wantErr bool
+	}{
+		{"системные корневые сертификаты", func(*ClientConfig) {}, true},
+		{"собственный CA", func(c *ClientConfig) { c.TLSCACertFile = filepath.Join(ca.dir, "ca.pem") }, false},
+		{"без проверки сертификата", func(c *ClientConfig) { c.TLSInsecureSkipVerify = true }, false},
+		{"файл CA не найден", func(c *ClientConfig) { c.TLSCACertFile = filepath.Join(ca.dir, "missing.pem") }, true},
+		{"файл CA без сертификатов", func(c *ClientConfig) { c.TLSCACertFile = testFile }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig := DefaultConfig()
+			clientConfig.RetryAttempts = 0
+			clientConfig.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			tt.setup(clientConfig)
+
+			err := NewHTTPClientWithConfig(clientConfig).UploadFile(context.Background(), testFile, ts.URL+"/upload", nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ошибка загрузки: %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName имя трассировщика клиента
+const tracerName = "httpBinaryClient/client"
+
+// startUploadSpan начинает span "upload_file" для одной попытки загрузки.
+// Если трассировка выключена, возвращается span-заглушка
+func (c *HTTPClient) startUploadSpan(ctx context.Context, task uploadTask, serverURL string, attempt int) (context.Context, trace.Span) {
+	if !c.config.TracingEnabled {
+		return ctx, noop.Span{}
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, "upload_file",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("file.name", task.formFileName()),
+			attribute.String("server.url", serverURL),
+			attribute.Int("retry.attempt", attempt),
+		))
+}
+
+// injectTraceContext добавляет заголовок traceparent текущего span в запрос
+func (c *HTTPClient) injectTraceContext(ctx context.Context, req *http.Request) {
+	if c.config.TracingEnabled {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+}
+
+// endUploadSpan завершает span, отмечая ошибку попытки
+func endUploadSpan(span trace.Span, err *UploadError) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if err.Code != 0 {
+			span.SetAttributes(attribute.Int("http.status_code", err.Code))
+		}
+	}
+	span.End()
+}
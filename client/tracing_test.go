@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestUploadFile_TraceparentPropagation(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "trace.bin")
+	if err := os.WriteFile(testFile, []byte("trace"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	config := DefaultConfig()
+	httpClient := NewHTTPClientWithConfig(config)
+	if err := httpClient.UploadFile(ctx, testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if traceparent != "" {
+		t.Errorf("При выключенной трассировке заголовок traceparent не должен отправляться, получен %q", traceparent)
+	}
+
+	config.TracingEnabled = true
+	httpClient = NewHTTPClientWithConfig(config)
+	if err := httpClient.UploadFile(ctx, testFile, server.URL, nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if !strings.Contains(traceparent, traceID.String()) {
+		t.Errorf("Ожидался traceparent с trace id %s, получен %q", traceID, traceparent)
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// TransferStats накопленная статистика загрузок клиента
+type TransferStats struct {
+	// TotalBytesUploaded байты содержимого, фактически отправленные во всех попытках,
+	// включая неудачные; при сжатии или шифровании — закодированные байты
+	TotalBytesUploaded int64 `json:"total_bytes_uploaded"`
+	TotalFilesUploaded int64 `json:"total_files_uploaded"` // Успешно загруженные файлы и потоки
+	TotalErrors        int64 `json:"total_errors"`         // Неудачные попытки загрузки, включая повторенные
+
+	// AverageSpeedBytesPerSec средняя скорость: TotalBytesUploaded, деленный на суммарное
+	// время всех попыток. При параллельной загрузке общая пропускная способность выше
+	AverageSpeedBytesPerSec float64 `json:"average_speed_bytes_per_sec"`
+	// PeakSpeedBytesPerSec наибольшая средняя скорость одной успешной попытки
+	PeakSpeedBytesPerSec float64 `json:"peak_speed_bytes_per_sec"`
+}
+
+// transferStats счетчики загрузок, общие для копий клиента из WithAuth и WithLoggingTransport
+type transferStats struct {
+	bytes    atomic.Int64
+	files    atomic.Int64
+	errors   atomic.Int64
+	duration atomic.Int64  // Суммарное время попыток в наносекундах
+	peak     atomic.Uint64 // Биты float64 наибольшей скорости
+}
+
+// observe учитывает попытку загрузки, отправившую bytes байт за duration. Попытка
+// с ответом 304 Not Modified (skipped) не считается загруженным файлом
+func (s *transferStats) observe(bytes int64, duration time.Duration, failed, skipped bool) {
+	s.bytes.Add(bytes)
+	s.duration.Add(int64(duration))
+	switch {
+	case failed:
+		s.errors.Add(1)
+		return
+	case skipped:
+		return
+	}
+	s.files.Add(1)
+
+	if duration <= 0 {
+		return
+	}
+	speed := float64(bytes) / duration.Seconds()
+	for {
+		old := s.peak.Load()
+		if speed <= math.Float64frombits(old) || s.peak.CompareAndSwap(old, math.Float64bits(speed)) {
+			return
+		}
+	}
+}
+
+// observeAttempt учитывает попытку загрузки filePath в Stats и ClientConfig.Metrics.
+// Вызывается из пути отправки (sendStream, putWebDAV) с числом фактически отправленных
+// байт. err должен быть nil для успешной попытки
+func (c *HTTPClient) observeAttempt(filePath string, bytes int64, duration time.Duration, skipped bool, err error) {
+	c.transfers.observe(bytes, duration, err != nil, skipped)
+	if c.config.Metrics != nil {
+		c.config.Metrics.ObserveUpload(filePath, bytes, duration, err)
+	}
+}
+
+// snapshot возвращает текущие значения счетчиков
+func (s *transferStats) snapshot() TransferStats {
+	stats := TransferStats{
+		TotalBytesUploaded:   s.bytes.Load(),
+		TotalFilesUploaded:   s.files.Load(),
+		TotalErrors:          s.errors.Load(),
+		PeakSpeedBytesPerSec: math.Float64frombits(s.peak.Load()),
+	}
+	if duration := time.Duration(s.duration.Load()); duration > 0 {
+		stats.AverageSpeedBytesPerSec = float64(stats.TotalBytesUploaded) / duration.Seconds()
+	}
+	return stats
+}
+
+// Stats возвращает статистику загрузок с момента создания клиента, например для
+// периодического отчета долго работающего процесса. Учитываются все попытки загрузки:
+// файлов, потоков (UploadReader), ответов по URL (UploadFromURL), файлов архивов и WebDAV.
+// Режим DryRun ничего не отправляет и не учитывается
+func (c *HTTPClient) Stats() TransferStats {
+	return c.transfers.snapshot()
+}
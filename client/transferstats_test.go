@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestStats(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "недоступен", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.bin")
+	large := filepath.Join(dir, "large.bin")
+	if err := os.WriteFile(small, make([]byte, 100), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	if err := os.WriteFile(large, make([]byte, 64*1024), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.RetryAttempts = 1
+	config.RetryDelay = time.Millisecond
+	httpClient := NewHTTPClientWithConfig(config)
+	if err := httpClient.UploadFile(context.Background(), small, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	// Копия клиента с аутентификацией ведет общую статистику
+	if err := httpClient.WithAuth(AuthConfig{Type: AuthTypeBearer, Token: "token"}).UploadFile(context.Background(), large, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	// Загрузка потока учитывается так же, как загрузка файла
+	if err := httpClient.UploadReader(context.Background(), strings.NewReader("stream"), "stream.txt", ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки потока: %v", err)
+	}
+
+	stats := httpClient.Stats()
+	if stats.TotalFilesUploaded != 3 || stats.TotalBytesUploaded != 100+64*1024+6 || stats.TotalErrors != 0 {
+		t.Errorf("Неверные счетчики: %+v", stats)
+	}
+	if stats.AverageSpeedBytesPerSec <= 0 || stats.PeakSpeedBytesPerSec < stats.AverageSpeedBytesPerSec {
+		t.Errorf("Неверные скорости: %+v", stats)
+	}
+
+	// Неудачные попытки учитываются в ошибках; сервер может ответить, не дочитав тело,
+	// поэтому отправленный объем не проверяется точно
+	if err := httpClient.UploadFile(context.Background(), small, failing.URL, nil); err == nil {
+		t.Fatal("Ожидалась ошибка загрузки")
+	}
+	failed := httpClient.Stats()
+	if failed.TotalFilesUploaded != 3 || failed.TotalErrors != 2 || failed.TotalBytesUploaded < stats.TotalBytesUploaded {
+		t.Errorf("Неверные счетчики после ошибки: %+v", failed)
+	}
+}
+
+func TestTransferStats_ConcurrentPeak(t *testing.T) {
+	stats := &transferStats{}
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(duration time.Duration) {
+			defer wg.Done()
+			stats.observe(1000, duration, false, false)
+		}(time.Duration(i) * time.Second)
+	}
+	wg.Wait()
+
+	// Наибольшая скорость у попытки длительностью секунду
+	snapshot := stats.snapshot()
+	if snapshot.TotalFilesUploaded != 10 || snapshot.PeakSpeedBytesPerSec != 1000 {
+		t.Errorf("Неверная статистика: %+v", snapshot)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// TransportStats состояние пула соединений клиента
+type TransportStats struct {
+	IdleConns   int   `json:"idle_conns"`   // Открытые соединения, не занятые запросами
+	ActiveConns int   `json:"active_conns"` // Соединения, по которым выполняется запрос или читается ответ
+	WaitCount   int64 `json:"wait_count"`   // Сколько раз загрузка ждала свободного слота MaxConcurrency
+}
+
+// connStats счетчики соединений, общие для копий клиента из WithAuth и WithLoggingTransport
+type connStats struct {
+	open   atomic.Int64
+	active atomic.Int64
+	waits  atomic.Int64
+}
+
+// snapshot возвращает текущее состояние пула. Для HTTP/2 несколько запросов
+// используют одно соединение, поэтому число активных ограничено числом открытых
+func (s *connStats) snapshot() TransportStats {
+	open := s.open.Load()
+	active := s.active.Load()
+	if active > open {
+		active = open
+	}
+	return TransportStats{
+		IdleConns:   int(open - active),
+		ActiveConns: int(active),
+		WaitCount:   s.waits.Load(),
+	}
+}
+
+// dialContext оборачивает функцию установки соединения так, чтобы учитывались открытые соединения
+func (s *connStats) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		s.open.Add(1)
+		return &trackedConn{Conn: conn, stats: s}, nil
+	}
+}
+
+// trackedConn соединение, закрытие которого уменьшает счетчик открытых соединений
+type trackedConn struct {
+	net.Conn
+	stats *connStats
+	once  sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { c.stats.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// statsTransport учитывает запросы, занимающие соединение: от отправки до
+// полного чтения или закрытия тела ответа
+type statsTransport struct {
+	base  *http.Transport
+	stats *connStats
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.active.Add(1)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.stats.active.Add(-1)
+		return nil, err
+	}
+	resp.Body = &trackedBody{ReadCloser: resp.Body, stats: t.stats}
+	return resp, nil
+}
+
+// trackedBody тело ответа, после чтения до конца или закрытия которого соединение считается свободным
+type trackedBody struct {
+	io.ReadCloser
+	stats *connStats
+	once  sync.Once
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
+
+func (b *trackedBody) release() {
+	b.once.Do(func() { b.stats.active.Add(-1) })
+}
+
+// TransportStats возвращает состояние пула соединений клиента. Значения помогают
+// заметить нехватку соединений при параллельной загрузке: растущий WaitCount при
+// нулевом IdleConns означает, что MaxConcurrency ограничивает передачу
+func (c *HTTPClient) TransportStats() TransportStats {
+	return c.stats.snapshot()
+}
+
+// acquireSlot занимает слот семафора параллельных загрузок, учитывая ожидание в TransportStats
+func (c *HTTPClient) acquireSlot(ctx context.Context) error {
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	c.stats.waits.Add(1)
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
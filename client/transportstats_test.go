@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestTransportStats_IdleAfterUpload(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	testFile := filepath.Join(t.TempDir(), "pooled.bin")
+	if err := os.WriteFile(testFile, []byte("pooled"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	httpClient := NewHTTPClientWithConfig(DefaultConfig())
+	if err := httpClient.UploadFile(context.Background(), testFile, ts.URL+"/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+
+	// Соединение возвращается в пул после чтения ответа
+	stats := httpClient.TransportStats()
+	if stats.IdleConns != 1 || stats.ActiveConns != 0 || stats.WaitCount != 0 {
+		t.Errorf("Неверное состояние пула: %+v", stats)
+	}
+
+	ts.CloseClientConnections()
+	deadline := time.Now().Add(5 * time.Second)
+	for httpClient.TransportStats().IdleConns != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Закрытое соединение осталось в статистике: %+v", httpClient.TransportStats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTransportStats_WaitCount(t *testing.T) {
+	ts := newUploadServer(t, &server.ServerConfig{UploadDir: t.TempDir()})
+
+	testFile := filepath.Join(t.TempDir(), "queued.bin")
+	if err := os.WriteFile(testFile, []byte("queued"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.MaxConcurrency = 1
+	httpClient := NewHTTPClientWithConfig(config)
+
+	// Единственный слот занят, загрузка должна дождаться его освобождения
+	httpClient.sem <- struct{}{}
+	done := make(chan error, 1)
+	go func() {
+		done <- httpClient.UploadFile(context.Background(), testFile, ts.URL+"/upload", nil)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for httpClient.TransportStats().WaitCount != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Ожидание слота не учтено")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	<-httpClient.sem
+
+	if err := <-done; err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	// Копия клиента разделяет статистику
+	if stats := httpClient.WithAuth(AuthConfig{}).TransportStats(); stats.WaitCount != 1 {
+		t.Errorf("Ожидался WaitCount 1, получено %d", stats.WaitCount)
+	}
+}
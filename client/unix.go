@@ -0,0 +1,15 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// configureUnixSocket направляет все соединения транспорта в Unix-сокет socketPath.
+// Хост из URL запроса при этом не используется, но остается в заголовке Host
+func configureUnixSocket(transport *http.Transport, dialer *net.Dialer, socketPath string) {
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadFile_UnixSocket(t *testing.T) {
+	// Путь сокета ограничен ~100 байтами, поэтому директория создается с коротким именем
+	socketDir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(socketDir)
+	socketPath := filepath.Join(socketDir, "upload.sock")
+
+	uploadDir := t.TempDir()
+	srv := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		SocketPath: socketPath,
+		UploadDir:  uploadDir,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	errChan := make(chan error, 1)
+	go func() { errChan <- srv.Start() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("unix", socketPath)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Сервер не запустился: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	testFile := filepath.Join(t.TempDir(), "socket.bin")
+	os.WriteFile(testFile, []byte("over unix socket"), 0644)
+
+	config := DefaultConfig()
+	config.Timeout = 10 * time.Second
+	config.SocketPath = socketPath
+	// Хост в URL не используется: соединение идет через сокет
+	if err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), testFile, "http://localhost/upload", nil); err != nil {
+		t.Fatalf("Ошибка загрузки: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(uploadDir, "socket.bin")); string(content) != "over unix socket" {
+		t.Errorf("Неверное содержимое: %q", content)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Ошибка остановки: %v", err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("Start вернул ошибку: %v", err)
+	}
+	if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Файл сокета не удален после остановки")
+	}
+}
+
+func TestUnixSocket_WithProxy(t *testing.T) {
+	config := DefaultConfig()
+	config.SocketPath = "/tmp/upload.sock"
+	config.ProxyURL = "http://proxy:3128"
+
+	err := NewHTTPClientWithConfig(config).UploadFile(context.Background(), "missing.bin", "http://localhost/upload", nil)
+	if err == nil {
+		t.Error("Ожидалась ошибка конфигурации")
+	}
+}
@@ -0,0 +1,17 @@
+package client
+
+import "context"
+
+// Транспорты загрузки (ClientConfig.Transport)
+const (
+	TransportHTTP = "http" // multipart-запросы HTTPClient
+	TransportGRPC = "grpc" // потоковый вызов FileUpload.Upload, см. пакет httpBinaryClient/grpc
+)
+
+// Uploader загрузка файла на сервер независимо от транспорта. Реализуется HTTPClient
+// и grpc.GRPCClient; нужный клиент по ClientConfig.Transport создает grpc.NewClient
+type Uploader interface {
+	UploadFile(ctx context.Context, filePath, serverURL string, progressCallback ProgressCallback) error
+}
+
+var _ Uploader = (*HTTPClient)(nil)
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+)
+
+// UploadFromURL скачивает файл по sourceURL и сразу передает его на serverURL, не сохраняя
+// на диск: тело ответа источника потоком идет в multipart-запрос. Имя файла берется
+// из последнего сегмента пути sourceURL. Если источник сообщил Content-Length, размер
+// передается серверу в заголовке X-File-Size и используется для прогресса. Ответ
+// источника нельзя прочитать повторно, поэтому выполняется одна попытка без повторов
+func (c *HTTPClient) UploadFromURL(ctx context.Context, sourceURL, serverURL string, progressCallback ProgressCallback) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	if c.config.SocketPath != "" {
+		return fmt.Errorf("загрузка по URL не поддерживается через Unix-сокет")
+	}
+	if c.config.CompressUpload && len(c.config.EncryptionKey) > 0 {
+		return fmt.Errorf("сжатие не поддерживается вместе с шифрованием")
+	}
+
+	source, err := url.Parse(sourceURL)
+	if err != nil {
+		return fmt.Errorf("неверный URL источника: %w", err)
+	}
+	filename, err := urlFilename(source)
+	if err != nil {
+		return err
+	}
+
+	if err := c.acquireSlot(ctx); err != nil {
+		return err
+	}
+	defer func() { <-c.sem }()
+
+	// Параметры запроса подписанных ссылок содержат учетные данные и в лог не попадают
+	sourceForLog := &url.URL{Scheme: source.Scheme, Host: source.Host, Path: source.Path}
+	logger := c.logger().With("file", filename, "source", sourceForLog.String(), "url", serverURL)
+	logger.Info("Начало загрузки по URL")
+	startTime := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса к источнику: %w", err)
+	}
+	resp, err := c.sourceClient().Do(req)
+	if err != nil {
+		logger.Error("Ошибка запроса к источнику", "error", err)
+		return fmt.Errorf("ошибка запроса к источнику: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("Источник вернул ошибку", "status", resp.StatusCode)
+		return fmt.Errorf("источник вернул статус %d", resp.StatusCode)
+	}
+
+	// Размер ответа без Content-Length неизвестен (-1): прогресс сообщает только отправленные байты
+	fileSize := max(resp.ContentLength, 0)
+	task := c.withCorrelationID(uploadTask{filePath: "-", remoteName: filename})
+	logger = logger.With("correlation_id", c.correlationID(task))
+	if fileSize > 0 && len(c.config.EncryptionKey) == 0 {
+		task = task.withHeader(FileSizeHeader, strconv.FormatInt(fileSize, 10))
+	}
+
+	progressCallback, finish := c.trackProgress(sourceForLog.String(), c.serializeProgress(progressCallback))
+	ctx, span := c.startUploadSpan(ctx, task, serverURL, 0)
+	sessionID, uploadErr := c.sendStream(ctx, resp.Body, fileSize, task, serverURL, ThrottleProgress(c.config.ProgressInterval, progressCallback))
+	endUploadSpan(span, uploadErr)
+	if uploadErr != nil {
+		finish(uploadErr)
+		logger.Error("Ошибка загрузки", "error", uploadErr)
+		return uploadErr
+	}
+	finish(nil)
+
+	logger.Info("Загрузка завершена",
+		"size", formatBytes(fileSize),
+		"duration", time.Since(startTime).Round(time.Millisecond),
+		"session_id", sessionID)
+	return nil
+}
+
+// urlFilename возвращает имя файла из последнего сегмента пути URL
+func urlFilename(u *url.URL) (string, error) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("неподдерживаемая схема URL источника: %q", u.Scheme)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || name == ".." {
+		return "", fmt.Errorf("не удалось определить имя файла из URL %s", u.Redacted())
+	}
+	return name, nil
+}
+
+// sourceClient возвращает клиент для запроса к источнику. Аутентификация и заголовки
+// ClientConfig.CustomHeaders предназначены серверу загрузки и не отправляются источнику
+func (c *HTTPClient) sourceClient() *http.Client {
+	httpClient := *c.client
+	httpClient.Transport = withoutServerHeaders(c.client.Transport)
+	return &httpClient
+}
+
+// withoutServerHeaders убирает из цепочки транспортов добавляющие заголовки сервера загрузки
+func withoutServerHeaders(rt http.RoundTripper) http.RoundTripper {
+	switch t := rt.(type) {
+	case *authTransport:
+		return withoutServerHeaders(t.base)
+	case *headerTransport:
+		return withoutServerHeaders(t.base)
+	case *LoggingTransport:
+		return &LoggingTransport{Base: withoutServerHeaders(t.Base), Logger: t.Logger}
+	}
+	return rt
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"httpBinaryClient/server"
+)
+
+func TestUploadFromURL(t *testing.T) {
+	content := bytes.Repeat([]byte("remote"), 50000)
+	var sourceAuth string
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sourceAuth = r.Header.Get("Authorization")
+		if r.URL.Path != "/exports/archive.bin" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content)
+	}))
+	defer source.Close()
+
+	backend := server.NewMemoryStorageBackend()
+	handler := server.NewHTTPServerWithConfig(&server.ServerConfig{
+		Backend: backend,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+	var fileSize, serverAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fileSize, serverAuth = r.Header.Get(FileSizeHeader), r.Header.Get("Authorization")
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	config := DefaultConfig()
+	config.ProgressInterval = 0
+	httpClient := NewHTTPClientWithConfig(config).WithAuth(AuthConfig{Type: AuthTypeBearer, Token: "secret"})
+
+	var lastBytes, lastTotal int64
+	err := httpClient.UploadFromURL(context.Background(), source.URL+"/exports/archive.bin?sig=abc", ts.URL+"/upload",
+		func(bytesTransferred, totalBytes int64, percentage float64) {
+			lastBytes, lastTotal = bytesTransferred, totalBytes
+		})
+	if err != nil {
+		t.Fatalf("Ошибка загрузки по URL: %v", err)
+	}
+
+	received, ok := backend.Contents("archive.bin")
+	if !ok || !bytes.Equal(received, content) {
+		t.Errorf("Сервер получил %d байт вместо %d", len(received), len(content))
+	}
+	if fileSize != "300000" {
+		t.Errorf("Ожидался заголовок %s: 300000, получено %q", FileSizeHeader, fileSize)
+	}
+	if lastBytes != int64(len(content)) || lastTotal != int64(len(content)) {
+		t.Errorf("Неверный прогресс: %d из %d байт", lastBytes, lastTotal)
+	}
+
+	// Учетные данные сервера загрузки не передаются источнику
+	if serverAuth != "Bearer secret" || sourceAuth != "" {
+		t.Errorf("Неверная аутентификация: сервер %q, источник %q", serverAuth, sourceAuth)
+	}
+}
+
+func TestUploadFromURL_Errors(t *testing.T) {
+	source := httptest.NewServer(http.NotFoundHandler())
+	defer source.Close()
+	httpClient := NewHTTPClient(0)
+
+	for _, sourceURL := range []string{
+		source.URL + "/missing.bin", // источник вернул 404
+		source.URL + "/",            // нет имени файла
+		"ftp://example.com/a.bin",   // неподдерживаемая схема
+	} {
+		if err := httpClient.UploadFromURL(context.Background(), sourceURL, "http://localhost/upload", nil); err == nil {
+			t.Errorf("Ожидалась ошибка для %s", sourceURL)
+		}
+	}
+}
+
+func TestURLFilename(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"https://example.com/a/b/report.csv":        "report.csv",
+		"https://example.com/data.bin?token=x#frag": "data.bin",
+		"http://example.com/%D0%B0%D1%80%D1%85.zip": "арх.zip",
+	} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("Ошибка разбора %q: %v", rawURL, err)
+		}
+		if got, err := urlFilename(u); err != nil || got != want {
+			t.Errorf("urlFilename(%q) = %q, %v; ожидалось %q", rawURL, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// watchPollInterval период опроса директории в режиме наблюдения
+const watchPollInterval = 250 * time.Millisecond
+
+// watchedFile состояние файла в наблюдаемой директории
+type watchedFile struct {
+	size     int64
+	modTime  time.Time
+	changed  time.Time // Когда последний раз замечено изменение размера или времени модификации
+	uploaded bool      // Файл уже отправлен (или отправка завершилась ошибкой)
+}
+
+// WatchDirectory наблюдает за директорией и загружает появляющиеся в ней файлы.
+// Файл отправляется после того, как его размер и время модификации не менялись
+// в течение ClientConfig.StabilizeDuration. Файлы, существовавшие на момент запуска,
+// не загружаются; каждый файл отправляется не более одного раза.
+// Изменения отслеживаются периодическим опросом директории.
+// Метод блокируется до отмены контекста и возвращает ctx.Err()
+func (c *HTTPClient) WatchDirectory(ctx context.Context, dirPath, serverURL string, progressCallback ProgressCallback) error {
+	stabilize := c.config.StabilizeDuration
+	if stabilize <= 0 {
+		stabilize = defaultStabilizeDuration
+	}
+
+	files := make(map[string]*watchedFile)
+
+	// Запоминаем уже существующие файлы, чтобы не отправлять их
+	initial, err := scanDirectory(dirPath)
+	if err != nil {
+		return err
+	}
+	for path := range initial {
+		files[path] = &watchedFile{uploaded: true}
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := scanDirectory(dirPath)
+		if err != nil {
+			c.logger().Error("Ошибка чтения директории", "dir", dirPath, "error", err)
+			continue
+		}
+
+		now := time.Now()
+		for path, info := range current {
+			state, ok := files[path]
+			if !ok {
+				files[path] = &watchedFile{size: info.Size(), modTime: info.ModTime(), changed: now}
+				continue
+			}
+			if state.uploaded {
+				continue
+			}
+
+			if info.Size() != state.size || !info.ModTime().Equal(state.modTime) {
+				state.size, state.modTime, state.changed = info.Size(), info.ModTime(), now
+				continue
+			}
+
+			// Пустой файл, скорее всего, еще не начали записывать
+			if state.size == 0 || now.Sub(state.changed) < stabilize {
+				continue
+			}
+
+			state.uploaded = true
+			wg.Add(1)
+			go func(path string) {
+				defer wg.Done()
+				// Результат загрузки записывается в лог в UploadFile
+				c.UploadFile(ctx, path, serverURL, progressCallback)
+			}(path)
+		}
+
+		// Удаленные файлы забываем, чтобы повторно созданный файл был отправлен
+		for path := range files {
+			if _, ok := current[path]; !ok {
+				delete(files, path)
+			}
+		}
+	}
+}
+
+// scanDirectory возвращает информацию об обычных файлах директории
+func scanDirectory(dirPath string) (map[string]os.FileInfo, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]os.FileInfo, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Файл мог быть удален между чтением директории и запросом информации
+		}
+		files[filepath.Join(dirPath, entry.Name())] = info
+	}
+
+	return files, nil
+}
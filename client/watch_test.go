@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchDirectory_UploadsNewFilesOnce(t *testing.T) {
+	watchDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(watchDir, "existing.bin"), []byte("old"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	var mu sync.Mutex
+	uploads := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		uploads[part.FileName()]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.StabilizeDuration = 100 * time.Millisecond
+	httpClient := NewHTTPClientWithConfig(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- httpClient.WatchDirectory(ctx, watchDir, server.URL, nil)
+	}()
+
+	time.Sleep(2 * watchPollInterval)
+	newFile := filepath.Join(watchDir, "new.bin")
+	if err := os.WriteFile(newFile, []byte("new data"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		count := uploads["new.bin"]
+		mu.Unlock()
+		if count > 0 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Дополнительные опросы не должны приводить к повторной отправке
+	time.Sleep(4 * watchPollInterval)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Ожидалась ошибка context.Canceled, получена: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if uploads["new.bin"] != 1 {
+		t.Errorf("Новый файл должен быть загружен ровно один раз, загружен %d раз", uploads["new.bin"])
+	}
+	if uploads["existing.bin"] != 0 {
+		t.Error("Существовавший до запуска файл не должен загружаться")
+	}
+}
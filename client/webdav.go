@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// WebDAVUpload загружает файл на WebDAV-сервер (Nextcloud, ownCloud, Apache mod_dav) запросом
+// PUT с содержимым файла и Content-Type: application/octet-stream. webdavURL — полный адрес
+// файла на сервере, например https://cloud.example.com/remote.php/dav/files/alice/report.pdf;
+// директории должны существовать. Успешными считаются ответы 201 Created (файл создан),
+// 204 No Content (файл заменен) и 200 OK. Ответ 423 Locked (файл заблокирован другим клиентом)
+// повторяется, как 5xx и сетевые ошибки, до RetryAttempts раз. Для basic-аутентификации
+// используется клиент из WithAuth(AuthConfig{Type: AuthTypeBasic, ...}).
+// Сжатие и шифрование не поддерживаются: сервер сохраняет тело запроса как есть
+func (c *HTTPClient) WebDAVUpload(ctx context.Context, filePath, webdavURL string, progressCallback ProgressCallback) error {
+	if err := c.validateUploadFile(filePath); err != nil {
+		return err
+	}
+	if len(c.config.EncryptionKey) > 0 || c.config.CompressUpload {
+		return fmt.Errorf("загрузка на WebDAV не поддерживает сжатие и шифрование")
+	}
+	if target, err := url.Parse(webdavURL); err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return fmt.Errorf("неверный URL WebDAV: %s", webdavURL)
+	}
+
+	if err := c.acquireSlot(ctx); err != nil {
+		return err
+	}
+	defer func() { <-c.sem }()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("ошибка получения информации о файле: %w", err)
+	}
+	fileSize := fileInfo.Size()
+
+	logger := c.logger().With("file", filePath, "url", webdavURL)
+	logger.Info("Начало загрузки на WebDAV")
+	startTime := time.Now()
+
+	progressCallback, finish := c.trackProgress(filePath, c.serializeProgress(progressCallback))
+	progressCallback = ThrottleProgress(c.config.ProgressInterval, progressCallback)
+	var sent atomic.Int64
+	onProgress := func(n int64) {
+		bytesSent := sent.Add(n)
+		if progressCallback != nil {
+			progressCallback(bytesSent, fileSize, float64(bytesSent)/float64(fileSize)*100)
+		}
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				finish(ctx.Err())
+				return ctx.Err()
+			case <-time.After(c.retryDelay(lastErr)):
+			}
+		}
+
+		attempts++
+		// Отправленные в неудачной попытке байты вычитаются из прогресса
+		body := &chunkProgressReader{ctx: ctx, client: c, r: io.NewSectionReader(file, 0, fileSize), onProgress: onProgress}
+		attemptStart := time.Now()
+		status, err := c.putWebDAV(ctx, webdavURL, body, fileSize)
+		c.observeAttempt(filePath, body.n, time.Since(attemptStart), false, err)
+		if err == nil {
+			logger.Info("Загрузка завершена", "duration", time.Since(startTime).Round(time.Millisecond), "status", status)
+			finish(nil)
+			return nil
+		}
+
+		lastErr = err
+		onProgress(-body.n)
+		if !c.shouldRetryWebDAV(attempts, err) || ctx.Err() != nil {
+			break
+		}
+		if attempt < c.config.RetryAttempts {
+			logger.Warn("Попытка загрузки не удалась, повторяем", "attempt", attempts, "error", err)
+		}
+	}
+
+	err = fmt.Errorf("загрузка не удалась после %d попыток, последняя ошибка: %w", attempts, lastErr)
+	logger.Error("Ошибка загрузки", "error", err)
+	finish(err)
+	return err
+}
+
+// putWebDAV выполняет запрос PUT и возвращает статус успешного ответа
+func (c *HTTPClient) putWebDAV(ctx context.Context, webdavURL string, body io.Reader, size int64) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, webdavURL, body)
+	if err != nil {
+		return 0, newUploadError("ошибка создания HTTP запроса", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.doIntercepted(req)
+	if err != nil {
+		return 0, newUploadError("ошибка выполнения HTTP запроса", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp.StatusCode, nil
+	}
+	return 0, responseError(resp)
+}
+
+// shouldRetryWebDAV дополняет shouldRetry повтором ответа 423 Locked: блокировка
+// файла другим клиентом временная, хотя и относится к ответам 4xx
+func (c *HTTPClient) shouldRetryWebDAV(attempt int, err error) bool {
+	var uploadErr *UploadError
+	if c.config.RetryCondition == nil && errors.As(err, &uploadErr) && uploadErr.Code == http.StatusLocked {
+		return true
+	}
+	return c.shouldRetry(attempt, err)
+}
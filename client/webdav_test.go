@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webDAVServer имитирует WebDAV-сервер: первые locked запросов PUT получают 423 Locked,
+// затем файл сохраняется с ответом 201 Created, а повторная запись — 204 No Content
+type webDAVServer struct {
+	mu       sync.Mutex
+	locked   int
+	requests int
+	files    map[string][]byte
+	header   http.Header
+}
+
+func (s *webDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.header = r.Header.Clone()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	data, _ := io.ReadAll(r.Body)
+	if s.locked > 0 {
+		s.locked--
+		w.WriteHeader(http.StatusLocked)
+		return
+	}
+
+	_, exists := s.files[r.URL.Path]
+	s.files[r.URL.Path] = data
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestWebDAVUpload(t *testing.T) {
+	dav := &webDAVServer{locked: 2, files: make(map[string][]byte)}
+	ts := httptest.NewServer(dav)
+	defer ts.Close()
+
+	content := bytes.Repeat([]byte("webdav"), 20000)
+	filePath := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+	config.ProgressInterval = 0
+	httpClient := NewHTTPClientWithConfig(config).WithAuth(AuthConfig{Type: AuthTypeBasic, Username: "alice", Password: "secret"})
+
+	var maxBytes, lastBytes int64
+	fileURL := ts.URL + "/remote.php/dav/files/alice/report.pdf"
+	err := httpClient.WebDAVUpload(context.Background(), filePath, fileURL, func(bytesTransferred, totalBytes int64, percentage float64) {
+		maxBytes = max(maxBytes, bytesTransferred)
+		lastBytes = bytesTransferred
+	})
+	if err != nil {
+		t.Fatalf("Ошибка загрузки на WebDAV: %v", err)
+	}
+	if !bytes.Equal(dav.files["/remote.php/dav/files/alice/report.pdf"], content) {
+		t.Errorf("Сервер сохранил %d байт вместо %d", len(dav.files["/remote.php/dav/files/alice/report.pdf"]), len(content))
+	}
+	if dav.requests != 3 {
+		t.Errorf("Ожидалось 3 запроса (два ответа 423 и успешный), получено %d", dav.requests)
+	}
+	if got := dav.header.Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q, ожидался application/octet-stream", got)
+	}
+	// Прогресс неудачных попыток вычитается, поэтому он не превышает размер файла
+	if lastBytes != int64(len(content)) || maxBytes != int64(len(content)) {
+		t.Errorf("Прогресс: последний %d, наибольший %d, ожидалось %d", lastBytes, maxBytes, len(content))
+	}
+
+	// Перезапись существующего файла отвечает 204 No Content
+	if err := httpClient.WebDAVUpload(context.Background(), filePath, fileURL, nil); err != nil {
+		t.Fatalf("Ошибка перезаписи файла на WebDAV: %v", err)
+	}
+}
+
+func TestWebDAVUpload_Errors(t *testing.T) {
+	dav := &webDAVServer{files: make(map[string][]byte)}
+	ts := httptest.NewServer(dav)
+	defer ts.Close()
+
+	filePath := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(filePath, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+
+	// Ответ 401 без учетных данных не повторяется
+	err := NewHTTPClientWithConfig(config).WebDAVUpload(context.Background(), filePath, ts.URL+"/data.bin", nil)
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.Code != http.StatusUnauthorized {
+		t.Fatalf("Ожидалась ошибка 401, получено %v", err)
+	}
+	if dav.requests != 1 {
+		t.Errorf("Ответ 401 повторен: %d запросов", dav.requests)
+	}
+
+	// Блокировка дольше RetryAttempts попыток возвращает 423
+	dav.locked = config.RetryAttempts + 1
+	authClient := NewHTTPClientWithConfig(config).WithAuth(AuthConfig{Type: AuthTypeBasic, Username: "alice", Password: "secret"})
+	err = authClient.WebDAVUpload(context.Background(), filePath, ts.URL+"/data.bin", nil)
+	if !errors.As(err, &uploadErr) || uploadErr.Code != http.StatusLocked {
+		t.Fatalf("Ожидалась ошибка 423, получено %v", err)
+	}
+
+	for _, webdavURL := range []string{"ftp://example.com/data.bin", "://bad"} {
+		if err := authClient.WebDAVUpload(context.Background(), filePath, webdavURL, nil); err == nil {
+			t.Errorf("Для URL %q ожидалась ошибка", webdavURL)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"httpBinaryClient/client"
+)
+
+// maxMessageSize наибольший размер сообщения, принимаемого сервером gRPC по умолчанию
+const maxMessageSize = 4 * 1024 * 1024
+
+// GRPCClient загружает файлы вызовом FileUpload.Upload. Из ClientConfig учитываются
+// BufferSize (размер части), Timeout, ConnectTimeout, TCPKeepAlive, RetryAttempts,
+// RetryDelay, ProgressInterval, CustomHeaders (передаются как метаданные вызова),
+// SocketPath, TLSCertFile/TLSKeyFile, TLSCACertFile, TLSInsecureSkipVerify и Logger
+type GRPCClient struct {
+	config  *client.ClientConfig
+	tls     *tls.Config // Настройки TLS для адресов https://
+	initErr error       // Ошибка настройки, возвращаемая при загрузке
+}
+
+// NewClient создает клиент с транспортом config.Transport: HTTPClient для http
+// (и пустого значения) или GRPCClient для grpc
+func NewClient(config *client.ClientConfig) client.Uploader {
+	if config == nil {
+		config = client.DefaultConfig()
+	}
+	switch config.Transport {
+	case "", client.TransportHTTP:
+		return client.NewHTTPClientWithConfig(config)
+	case client.TransportGRPC:
+		return NewGRPCClient(config)
+	default:
+		c := NewGRPCClient(config)
+		c.initErr = fmt.Errorf("неизвестный транспорт %q, ожидается %s или %s", config.Transport, client.TransportHTTP, client.TransportGRPC)
+		return c
+	}
+}
+
+// NewGRPCClient создает gRPC-клиент
+func NewGRPCClient(config *client.ClientConfig) *GRPCClient {
+	if config == nil {
+		config = client.DefaultConfig()
+	}
+
+	tlsConfig, initErr := config.TLSConfig()
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	c := &GRPCClient{config: config, tls: tlsConfig, initErr: initErr}
+	if config.TLSInsecureSkipVerify {
+		c.logger().Warn("Проверка сертификата сервера отключена (TLSInsecureSkipVerify), используйте только для разработки")
+	}
+	return c
+}
+
+// UploadFile загружает файл потоком частей размером BufferSize. serverURL — адрес
+// сервера вида http://host:9090 (h2c) или https://host:9090, путь не учитывается
+func (c *GRPCClient) UploadFile(ctx context.Context, filePath, serverURL string, progressCallback client.ProgressCallback) error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	conn, err := c.dial(serverURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("ошибка получения информации о файле: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s не является обычным файлом", filePath)
+	}
+
+	logger := c.logger().With("file", filePath, "url", serverURL, "transport", client.TransportGRPC)
+	logger.Info("Начало загрузки")
+	startTime := time.Now()
+	progressCallback = client.ThrottleProgress(c.config.ProgressInterval, progressCallback)
+
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.config.RetryDelay):
+			}
+		}
+
+		attempts++
+		resp, err := c.uploadOnce(ctx, NewFileUploadClient(conn), filePath, info.Size(), progressCallback)
+		if err == nil {
+			logger.Info("Загрузка завершена",
+				"duration", time.Since(startTime).Round(time.Millisecond),
+				"filename", resp.GetFilename(),
+				"size", resp.GetSize())
+			return nil
+		}
+
+		lastErr = err
+		if !shouldRetry(ctx, err) {
+			break
+		}
+		if attempt < c.config.RetryAttempts {
+			logger.Warn("Попытка загрузки не удалась, повторяем", "attempt", attempts, "error", err)
+		}
+	}
+
+	err = fmt.Errorf("загрузка не удалась после %d попыток, последняя ошибка: %w", attempts, lastErr)
+	logger.Error("Ошибка загрузки", "error", err)
+	return err
+}
+
+// dial создает соединение с сервером serverURL: http:// — HTTP/2 без шифрования,
+// https:// — по TLS. Путь адреса не учитывается; соединение устанавливается при первом вызове
+func (c *GRPCClient) dial(serverURL string) (*grpc.ClientConn, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный адрес сервера: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("в адресе сервера %q не указан хост", serverURL)
+	}
+	var creds credentials.TransportCredentials
+	switch u.Scheme {
+	case "http":
+		creds = insecure.NewCredentials()
+	case "https":
+		creds = credentials.NewTLS(c.tls)
+	default:
+		return nil, fmt.Errorf("неподдерживаемая схема адреса сервера %q, ожидается http или https", u.Scheme)
+	}
+
+	dialer := &net.Dialer{Timeout: c.config.ConnectTimeout, KeepAlive: c.config.TCPKeepAlive}
+	conn, err := grpc.NewClient("passthrough:///"+u.Host,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			if c.config.SocketPath != "" {
+				return dialer.DialContext(ctx, "unix", c.config.SocketPath)
+			}
+			return dialer.DialContext(ctx, "tcp", addr)
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания соединения: %w", err)
+	}
+	return conn, nil
+}
+
+// uploadOnce выполняет один вызов Upload
+func (c *GRPCClient) uploadOnce(ctx context.Context, uploadClient FileUploadClient, filePath string, fileSize int64, progressCallback client.ProgressCallback) (*UploadResponse, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	// Отмена прерывает вызов, если отправка частей завершилась ошибкой
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if len(c.config.CustomHeaders) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(c.config.CustomHeaders))
+	}
+
+	stream, err := uploadClient.Upload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// io.EOF от Send означает, что сервер завершил вызов; его статус возвращает CloseAndRecv
+	if err := c.writeChunks(stream, file, filepath.Base(filePath), fileSize, progressCallback); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return stream.CloseAndRecv()
+}
+
+// writeChunks отправляет файл частями UploadChunk; имя и размер передаются в первой части
+func (c *GRPCClient) writeChunks(stream FileUpload_UploadClient, r io.Reader, filename string, fileSize int64, progressCallback client.ProgressCallback) error {
+	bufferSize := c.config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = client.DefaultConfig().BufferSize
+	}
+	// Часть вместе с остальными полями должна поместиться в одно сообщение
+	bufferSize = min(bufferSize, maxMessageSize-1024)
+
+	// Сообщение кодируется в Send, поэтому буфер можно использовать повторно
+	buf := make([]byte, bufferSize)
+	chunk := &UploadChunk{Filename: filename, TotalSize: fileSize}
+	var sent int64
+	for first := true; ; first = false {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || first {
+			chunk.Data = buf[:n]
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			chunk = &UploadChunk{}
+			sent += int64(n)
+			if progressCallback != nil && fileSize > 0 {
+				progressCallback(sent, fileSize, float64(sent)/float64(fileSize)*100)
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла: %w", err)
+		}
+	}
+}
+
+// logger возвращает логгер клиента
+func (c *GRPCClient) logger() *slog.Logger {
+	if c.config.Logger != nil {
+		return c.config.Logger
+	}
+	return slog.Default()
+}
+
+// shouldRetry решает, повторять ли вызов после ошибки err: статусы gRPC повторяются,
+// если они временные, остальные ошибки — всегда, пока не отменен ctx
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	switch st.Code() {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"httpBinaryClient/client"
+	"httpBinaryClient/server"
+)
+
+// newTestServer запускает gRPC-сервер на локальном порту и возвращает его адрес
+func newTestServer(t *testing.T, config *GRPCServerConfig) string {
+	t.Helper()
+	if config.Logger == nil {
+		config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewGRPCServerWithConfig(config)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(listener) }()
+	t.Cleanup(func() {
+		srv.Shutdown(context.Background())
+		if err := <-served; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	return "http://" + listener.Addr().String()
+}
+
+// testConfig конфигурация клиента без повторов и промежуточных логов
+func testConfig() *client.ClientConfig {
+	config := client.DefaultConfig()
+	config.RetryAttempts = 0
+	config.ProgressInterval = 0
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return config
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGRPCClient_UploadFile(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	url := newTestServer(t, &GRPCServerConfig{Backend: backend})
+
+	data := make([]byte, 200*1024+17)
+	rand.Read(data)
+	path := writeFile(t, "data.bin", data)
+
+	config := testConfig()
+	config.BufferSize = 64 * 1024
+	var calls int
+	var last int64
+	err := NewGRPCClient(config).UploadFile(context.Background(), path, url, func(transferred, total int64, percentage float64) {
+		calls++
+		if transferred < last || total != int64(len(data)) {
+			t.Errorf("некорректный прогресс %d/%d", transferred, total)
+		}
+		last = transferred
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+
+	got, ok := backend.Contents("data.bin")
+	if !ok || !bytes.Equal(got, data) {
+		t.Fatalf("сохраненное содержимое не совпадает: %d байт из %d", len(got), len(data))
+	}
+	if calls != 4 || last != int64(len(data)) {
+		t.Errorf("прогресс: %d вызовов, последний %d байт; ожидалось 4 вызова и %d байт", calls, last, len(data))
+	}
+}
+
+func TestGRPCClient_EmptyFile(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	url := newTestServer(t, &GRPCServerConfig{Backend: backend})
+
+	path := writeFile(t, "empty.txt", nil)
+	if err := NewGRPCClient(testConfig()).UploadFile(context.Background(), path, url, nil); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if got, ok := backend.Contents("empty.txt"); !ok || len(got) != 0 {
+		t.Errorf("пустой файл не сохранен: %v %q", ok, got)
+	}
+}
+
+func TestGRPCClient_StatusErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "exists.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	url := newTestServer(t, &GRPCServerConfig{
+		Backend:          server.NewLocalStorageBackend(dir, server.CollisionSkip),
+		AuthToken:        "secret",
+		MaxFileSizeBytes: 10,
+	})
+
+	tests := []struct {
+		name    string
+		token   string
+		file    string
+		data    string
+		code    codes.Code
+		message string
+	}{
+		{"без токена", "", "a.txt", "data", codes.Unauthenticated, "требуется аутентификация"},
+		{"неверный токен", "wrong", "a.txt", "data", codes.Unauthenticated, "требуется аутентификация"},
+		{"файл существует", "secret", "exists.txt", "new", codes.AlreadyExists, "exists.txt уже существует"},
+		{"слишком большой", "secret", "big.txt", strings.Repeat("x", 11), codes.ResourceExhausted, "превышает допустимый размер"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.RetryAttempts = 2
+			config.RetryDelay = time.Millisecond
+			if tt.token != "" {
+				config.CustomHeaders = map[string]string{"Authorization": "Bearer " + tt.token}
+			}
+			path := writeFile(t, tt.file, []byte(tt.data))
+
+			err := NewGRPCClient(config).UploadFile(context.Background(), path, url, nil)
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("ожидался статус gRPC, получено %v", err)
+			}
+			if st.Code() != tt.code || !strings.Contains(st.Message(), tt.message) {
+				t.Errorf("получен статус %v %q, ожидался %v с %q", st.Code(), st.Message(), tt.code, tt.message)
+			}
+			// Постоянные ошибки не повторяются
+			if !strings.Contains(err.Error(), "после 1 попыток") {
+				t.Errorf("ошибка не должна повторяться: %v", err)
+			}
+		})
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "exists.txt")); string(data) != "old" {
+		t.Errorf("существующий файл изменен: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "big.txt")); !os.IsNotExist(err) {
+		t.Errorf("файл сверх ограничения сохранен: %v", err)
+	}
+}
+
+func TestGRPCClient_InvalidURL(t *testing.T) {
+	path := writeFile(t, "a.txt", []byte("data"))
+	for _, serverURL := range []string{"grpc://localhost:9090", "localhost:9090", "http://"} {
+		if err := NewGRPCClient(testConfig()).UploadFile(context.Background(), path, serverURL, nil); err == nil {
+			t.Errorf("для адреса %q ожидалась ошибка", serverURL)
+		}
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		transport string
+		want      any
+	}{
+		{"", &client.HTTPClient{}},
+		{client.TransportHTTP, &client.HTTPClient{}},
+		{client.TransportGRPC, &GRPCClient{}},
+	}
+	for _, tt := range tests {
+		config := testConfig()
+		config.Transport = tt.transport
+		got := NewClient(config)
+		switch tt.want.(type) {
+		case *client.HTTPClient:
+			if _, ok := got.(*client.HTTPClient); !ok {
+				t.Errorf("Transport %q: получен %T, ожидался *client.HTTPClient", tt.transport, got)
+			}
+		case *GRPCClient:
+			if _, ok := got.(*GRPCClient); !ok {
+				t.Errorf("Transport %q: получен %T, ожидался *GRPCClient", tt.transport, got)
+			}
+		}
+	}
+
+	config := testConfig()
+	config.Transport = "ftp"
+	path := writeFile(t, "a.txt", []byte("data"))
+	if err := NewClient(config).UploadFile(context.Background(), path, "http://localhost:1", nil); err == nil || !strings.Contains(err.Error(), "ftp") {
+		t.Errorf("для неизвестного транспорта ожидалась ошибка, получено %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+package grpc
+
+// Код сообщений и сервиса генерируется из upload.proto; нужны protoc, protoc-gen-go
+// и protoc-gen-go-grpc в PATH
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative upload.proto
@@ -0,0 +1,284 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"httpBinaryClient/server"
+)
+
+// DefaultAddr адрес gRPC-сервера по умолчанию
+const DefaultAddr = ":9090"
+
+// errFileTooLarge возвращается при чтении файла, превысившего MaxFileSizeBytes
+var errFileTooLarge = errors.New("файл превышает допустимый размер")
+
+// GRPCServerConfig конфигурация gRPC-сервера
+type GRPCServerConfig struct {
+	Addr    string                // Адрес host:port (пусто — :9090)
+	Backend server.StorageBackend // Хранилище принятых файлов
+
+	AuthToken        string // Если задан, вызов требует метаданные authorization: Bearer {токен}
+	MaxFileSizeBytes int64  // Максимальный размер файла (0 — без ограничения)
+
+	// Сертификат и ключ PEM. Если заданы, сервер работает по TLS, иначе по HTTP/2 без шифрования (h2c)
+	TLSCertFile string
+	TLSKeyFile  string
+
+	Logger *slog.Logger // Логгер сервера (nil — slog.Default())
+}
+
+// GRPCServer принимает файлы вызовом FileUpload.Upload и сохраняет их в StorageBackend
+type GRPCServer struct {
+	UnimplementedFileUploadServer
+
+	config *GRPCServerConfig
+
+	mu     sync.Mutex
+	server *grpc.Server
+}
+
+// NewGRPCServer создает gRPC-сервер, сохраняющий файлы в backend
+func NewGRPCServer(backend server.StorageBackend) *GRPCServer {
+	return NewGRPCServerWithConfig(&GRPCServerConfig{Backend: backend})
+}
+
+// NewGRPCServerWithConfig создает gRPC-сервер с кастомной конфигурацией
+func NewGRPCServerWithConfig(config *GRPCServerConfig) *GRPCServer {
+	return &GRPCServer{config: config}
+}
+
+// newServer создает grpc.Server с сервисом FileUpload и проверкой токена
+func (s *GRPCServer) newServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.StreamInterceptor(s.authInterceptor))
+	srv := grpc.NewServer(opts...)
+	RegisterFileUploadServer(srv, s)
+	return srv
+}
+
+// Handler возвращает обработчик для встраивания в другой HTTP-сервер. Он принимает
+// HTTP/2 как по TLS, так и без шифрования; Shutdown на него не влияет
+func (s *GRPCServer) Handler() http.Handler {
+	return h2c.NewHandler(s.newServer(), &http2.Server{})
+}
+
+// Start запускает сервер на Addr и блокируется до его остановки
+func (s *GRPCServer) Start() error {
+	addr := s.config.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve принимает соединения на listener и блокируется до остановки сервера
+func (s *GRPCServer) Serve(listener net.Listener) error {
+	if s.config.Backend == nil {
+		listener.Close()
+		return fmt.Errorf("не задано хранилище файлов")
+	}
+
+	var opts []grpc.ServerOption
+	useTLS := s.config.TLSCertFile != "" || s.config.TLSKeyFile != ""
+	if useTLS {
+		creds, err := credentials.NewServerTLSFromFile(s.config.TLSCertFile, s.config.TLSKeyFile)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("ошибка загрузки сертификата: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	srv := s.newServer(opts...)
+	s.mu.Lock()
+	s.server = srv
+	s.mu.Unlock()
+
+	s.logger().Info("gRPC-сервер запущен", "addr", listener.Addr().String(), "tls", useTLS)
+	err := srv.Serve(listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown останавливает сервер, дожидаясь завершения загрузок; по истечении ctx
+// оставшиеся вызовы прерываются
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// logger возвращает логгер сервера
+func (s *GRPCServer) logger() *slog.Logger {
+	if s.config.Logger != nil {
+		return s.config.Logger
+	}
+	return slog.Default()
+}
+
+// authInterceptor отклоняет вызовы без токена AuthToken в метаданных authorization
+func (s *GRPCServer) authInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if s.config.AuthToken != "" && !s.validToken(stream.Context()) {
+		return s.fail(stream.Context(), "", status.Error(codes.Unauthenticated, "требуется аутентификация"))
+	}
+	return handler(srv, stream)
+}
+
+// validToken сравнивает токен из метаданных authorization с ожидаемым
+func (s *GRPCServer) validToken(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Upload реализует FileUploadServer: читает поток частей и сохраняет файл.
+// Имя и размер берутся из первой части
+func (s *GRPCServer) Upload(stream FileUpload_UploadServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return s.fail(ctx, "", status.Error(codes.InvalidArgument, "поток не содержит частей файла"))
+	}
+	if err != nil {
+		return s.fail(ctx, "", err)
+	}
+	if first.GetFilename() == "" {
+		return s.fail(ctx, "", status.Error(codes.InvalidArgument, "в первой части не указано имя файла"))
+	}
+	maxSize := s.config.MaxFileSizeBytes
+	if maxSize > 0 && first.GetTotalSize() > maxSize {
+		return s.fail(ctx, first.GetFilename(), status.Errorf(codes.ResourceExhausted, "%s: %d байт, допустимо %d", errFileTooLarge, first.GetTotalSize(), maxSize))
+	}
+
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	filename := server.SanitizeFilename(first.GetFilename())
+	fileMetadata := map[string]string{
+		server.MetadataOriginalName: first.GetFilename(),
+		server.MetadataRemoteAddr:   remoteAddr,
+	}
+	src := &chunkReader{stream: stream, buf: first.GetData(), limit: maxSize}
+	written, err := s.config.Backend.Save(ctx, filename, src, fileMetadata)
+	if err != nil {
+		return s.fail(ctx, filename, saveStatus(ctx, err, filename, maxSize))
+	}
+
+	if stored := fileMetadata[server.MetadataStoredName]; stored != "" {
+		filename = stored
+	}
+	if err := stream.SendAndClose(&UploadResponse{Filename: filename, Size: written}); err != nil {
+		s.logger().Error("Ошибка отправки ответа gRPC", "remote_addr", remoteAddr, "error", err)
+		return err
+	}
+	s.logger().Info("Файл загружен по gRPC",
+		"filename", filename,
+		"size", written,
+		"remote_addr", remoteAddr)
+	return nil
+}
+
+// saveStatus преобразует ошибку сохранения файла в статус gRPC
+func saveStatus(ctx context.Context, err error, filename string, maxSize int64) error {
+	switch {
+	case errors.Is(err, errFileTooLarge):
+		return status.Errorf(codes.ResourceExhausted, "%s: допустимо %d байт", errFileTooLarge, maxSize)
+	case errors.Is(err, server.ErrFileExists):
+		return status.Errorf(codes.AlreadyExists, "файл %s уже существует", filename)
+	case errors.Is(err, server.ErrReservedName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case ctx.Err() != nil:
+		return status.Error(codes.Canceled, "загрузка прервана клиентом")
+	}
+	if st, ok := status.FromError(err); ok {
+		// Ошибка чтения потока частей
+		return st.Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// fail записывает в лог ошибку вызова и возвращает ее
+func (s *GRPCServer) fail(ctx context.Context, filename string, err error) error {
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	st := status.Convert(err)
+	s.logger().Error("Ошибка вызова gRPC",
+		"filename", filename,
+		"remote_addr", remoteAddr,
+		"code", st.Code().String(),
+		"error", st.Message())
+	return err
+}
+
+// chunkReader отдает содержимое частей UploadChunk как непрерывный поток
+type chunkReader struct {
+	stream FileUpload_UploadServer
+	buf    []byte
+	n      int64
+	limit  int64 // Наибольший размер содержимого (0 — без ограничения)
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		chunk, err := c.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		c.buf = chunk.GetData()
+	}
+
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	c.n += int64(n)
+	if c.limit > 0 && c.n > c.limit {
+		return 0, errFileTooLarge
+	}
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"httpBinaryClient/server"
+)
+
+// dialTest создает соединение с тестовым сервером по адресу http://host:port
+func dialTest(t *testing.T, serverURL string) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient(strings.TrimPrefix(serverURL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// callUpload отправляет части chunks вызовом Upload и возвращает его ошибку
+func callUpload(t *testing.T, conn *grpc.ClientConn, chunks ...*UploadChunk) error {
+	t.Helper()
+	stream, err := NewFileUploadClient(conn).Upload(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		// Ошибку Send сервер возвращает в CloseAndRecv
+		if stream.Send(chunk) != nil {
+			break
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+func TestGRPCServer_Chunks(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	conn := dialTest(t, newTestServer(t, &GRPCServerConfig{Backend: backend, MaxFileSizeBytes: 8}))
+
+	// Имя очищается так же, как при загрузке через HTTP
+	err := callUpload(t, conn,
+		&UploadChunk{Filename: "../dir/my file.txt", Data: []byte("abc")},
+		&UploadChunk{Filename: "ignored.txt", Data: []byte("def")},
+	)
+	if err != nil {
+		t.Fatalf("вызов завершился ошибкой: %v", err)
+	}
+	if got, ok := backend.Contents("my_file.txt"); !ok || string(got) != "abcdef" {
+		t.Errorf("сохранено %v %q", ok, got)
+	}
+
+	// Без total_size ограничение проверяется по мере приема
+	err = callUpload(t, conn,
+		&UploadChunk{Filename: "big.txt", Data: []byte("12345")},
+		&UploadChunk{Data: []byte("6789")},
+	)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("ожидался ResourceExhausted, получено %v", err)
+	}
+	if _, ok := backend.Contents("big.txt"); ok {
+		t.Error("файл сверх ограничения сохранен")
+	}
+}
+
+func TestGRPCServer_InvalidCalls(t *testing.T) {
+	conn := dialTest(t, newTestServer(t, &GRPCServerConfig{Backend: server.NewMemoryStorageBackend()}))
+
+	tests := []struct {
+		name   string
+		chunks []*UploadChunk
+		code   codes.Code
+	}{
+		{"пустой поток", nil, codes.InvalidArgument},
+		{"без имени", []*UploadChunk{{Data: []byte("x")}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := callUpload(t, conn, tt.chunks...); status.Code(err) != tt.code {
+				t.Errorf("ожидался статус %v, получено %v", tt.code, err)
+			}
+		})
+	}
+
+	err := conn.Invoke(context.Background(), "/fileupload.FileUpload/Delete", &UploadChunk{Filename: "a"}, &UploadResponse{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("неизвестный метод: ожидался Unimplemented, получено %v", err)
+	}
+}
+
+func TestGRPCServer_Handler(t *testing.T) {
+	backend := server.NewMemoryStorageBackend()
+	ts := httptest.NewServer(NewGRPCServerWithConfig(&GRPCServerConfig{Backend: backend, Logger: testConfig().Logger}).Handler())
+	defer ts.Close()
+
+	// Встроенный обработчик принимает вызовы по h2c
+	path := writeFile(t, "handler.txt", []byte("data"))
+	if err := NewGRPCClient(testConfig()).UploadFile(context.Background(), path, ts.URL, nil); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if got, ok := backend.Contents("handler.txt"); !ok || string(got) != "data" {
+		t.Errorf("сохранено %v %q", ok, got)
+	}
+
+	resp, err := http.Post(ts.URL+"/fileupload.FileUpload/Upload", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("запрос не gRPC: статус %d, ожидался 415", resp.StatusCode)
+	}
+}
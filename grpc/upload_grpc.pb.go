@@ -0,0 +1,121 @@
+// Описание сервиса загрузки файлов. upload.pb.go и upload_grpc.pb.go сгенерированы
+// из него командой go generate (см. generate.go)
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v27.3.0
+// source: upload.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FileUpload_Upload_FullMethodName = "/fileupload.FileUpload/Upload"
+)
+
+// FileUploadClient is the client API for FileUpload service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FileUploadClient interface {
+	// Upload принимает файл потоком частей. Имя и размер передаются в первой части,
+	// в остальных — только данные
+	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, UploadResponse], error)
+}
+
+type fileUploadClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFileUploadClient(cc grpc.ClientConnInterface) FileUploadClient {
+	return &fileUploadClient{cc}
+}
+
+func (c *fileUploadClient) Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, UploadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileUpload_ServiceDesc.Streams[0], FileUpload_Upload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadChunk, UploadResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUpload_UploadClient = grpc.ClientStreamingClient[UploadChunk, UploadResponse]
+
+// FileUploadServer is the server API for FileUpload service.
+// All implementations must embed UnimplementedFileUploadServer
+// for forward compatibility.
+type FileUploadServer interface {
+	// Upload принимает файл потоком частей. Имя и размер передаются в первой части,
+	// в остальных — только данные
+	Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error
+	mustEmbedUnimplementedFileUploadServer()
+}
+
+// UnimplementedFileUploadServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFileUploadServer struct{}
+
+func (UnimplementedFileUploadServer) Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedFileUploadServer) mustEmbedUnimplementedFileUploadServer() {}
+func (UnimplementedFileUploadServer) testEmbeddedByValue()                    {}
+
+// UnsafeFileUploadServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FileUploadServer will
+// result in compilation errors.
+type UnsafeFileUploadServer interface {
+	mustEmbedUnimplementedFileUploadServer()
+}
+
+func RegisterFileUploadServer(s grpc.ServiceRegistrar, srv FileUploadServer) {
+	// If the following call pancis, it indicates UnimplementedFileUploadServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FileUpload_ServiceDesc, srv)
+}
+
+func _FileUpload_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileUploadServer).Upload(&grpc.GenericServerStream[UploadChunk, UploadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUpload_UploadServer = grpc.ClientStreamingServer[UploadChunk, UploadResponse]
+
+// FileUpload_ServiceDesc is the grpc.ServiceDesc for FileUpload service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FileUpload_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fileupload.FileUpload",
+	HandlerType: (*FileUploadServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _FileUpload_Upload_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "upload.proto",
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultAuditLogMaxSizeMB размер журнала аудита, после которого он ротируется
+const defaultAuditLogMaxSizeMB = 100
+
+// auditFlushInterval период сброса буфера журнала аудита на диск
+const auditFlushInterval = time.Second
+
+// AuditRecord запись журнала аудита о запросе на загрузку (строка NDJSON)
+type AuditRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	RemoteIP   string    `json:"remote_ip"`
+	Filename   string    `json:"filename"`
+	SizeBytes  int64     `json:"size_bytes"`
+	DurationMs int64     `json:"duration_ms"`
+	Status     int       `json:"status"`
+	Error      string    `json:"error"`
+	SHA256     string    `json:"sha256"` // Пусто, если файл не сохранен
+}
+
+// auditLog журнал аудита в формате NDJSON. Записи буферизуются и сбрасываются
+// на диск раз в auditFlushInterval; файл больше maxSize переименовывается
+// в {path}.{timestamp}, и запись продолжается в новый файл
+type auditLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File // nil, пока не записана первая запись или после close
+	writer  *bufio.Writer
+	size    int64
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// newAuditLog создает журнал; при пустом path возвращает nil. Файл открывается
+// при первой записи, поэтому ошибка открытия не мешает запуску сервера
+func newAuditLog(path string, maxSizeMB int) *auditLog {
+	if path == "" {
+		return nil
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultAuditLogMaxSizeMB
+	}
+	return &auditLog{path: path, maxSize: int64(maxSizeMB) << 20}
+}
+
+// write добавляет запись в журнал, при необходимости ротируя файл
+func (l *auditLog) write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.writer.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// open открывает файл журнала для дописывания и запускает периодический сброс буфера
+func (l *auditLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия журнала аудита: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка открытия журнала аудита: %w", err)
+	}
+
+	l.file = file
+	l.writer = bufio.NewWriter(file)
+	l.size = info.Size()
+	if l.stop == nil {
+		l.stop = make(chan struct{})
+		l.done = make(chan struct{})
+		go l.flushLoop(l.stop, l.done)
+	}
+	return nil
+}
+
+// rotate переименовывает заполненный файл и открывает новый. Вызывается под l.mu
+func (l *auditLog) rotate() error {
+	if err := l.writer.Flush(); err != nil {
+		return fmt.Errorf("ошибка записи журнала аудита: %w", err)
+	}
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("ошибка закрытия журнала аудита: %w", err)
+	}
+	l.file = nil
+
+	if err := os.Rename(l.path, fmt.Sprintf("%s.%d", l.path, time.Now().UnixNano())); err != nil {
+		return fmt.Errorf("ошибка ротации журнала аудита: %w", err)
+	}
+	return l.open()
+}
+
+// flushLoop сбрасывает буфер на диск, пока не закрыт stop
+func (l *auditLog) flushLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			l.flush()
+		}
+	}
+}
+
+// flush сбрасывает буфер на диск
+func (l *auditLog) flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.writer.Flush()
+}
+
+// close останавливает периодический сброс, сбрасывает буфер и закрывает файл.
+// Следующая запись откроет файл заново
+func (l *auditLog) close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	stop, done := l.stop, l.done
+	l.stop, l.done = nil, nil
+	l.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.writer.Flush()
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// auditKey ключ контекста запроса с записью аудита
+type auditKey struct{}
+
+// auditEntry сведения о загрузке, которые заполняет обработчик
+type auditEntry struct {
+	filename string
+	size     int64
+	sha256   string
+	err      string
+}
+
+// auditFile дополняет запись аудита запроса именем и размером файла и, если
+// передан hasher, его SHA-256. Без журнала аудита ничего не делает
+func auditFile(r *http.Request, filename string, size int64, hasher hash.Hash) {
+	entry, ok := r.Context().Value(auditKey{}).(*auditEntry)
+	if !ok {
+		return
+	}
+	entry.filename = filename
+	entry.size = size
+	if hasher != nil {
+		entry.sha256 = hex.EncodeToString(hasher.Sum(nil))
+	}
+}
+
+// recordAuditError сохраняет текст ошибки в записи аудита запроса, если она есть
+func recordAuditError(r *http.Request, msg string) {
+	if entry, ok := r.Context().Value(auditKey{}).(*auditEntry); ok {
+		entry.err = msg
+	}
+}
+
+// audited записывает в журнал аудита результат каждого запроса на загрузку,
+// успешного или нет. Ошибка записи журнала не влияет на ответ клиенту
+func (s *HTTPServer) audited(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.audit == nil {
+			next(w, r)
+			return
+		}
+
+		startTime := time.Now()
+		entry := &auditEntry{}
+		recorder := &statusRecorder{ResponseWriter: w}
+		next(recorder, r.WithContext(context.WithValue(r.Context(), auditKey{}, entry)))
+
+		record := AuditRecord{
+			Timestamp:  time.Now().UTC(),
+			Filename:   entry.filename,
+			SizeBytes:  entry.size,
+			DurationMs: time.Since(startTime).Milliseconds(),
+			Status:     recorder.status,
+			Error:      entry.err,
+			SHA256:     entry.sha256,
+		}
+		if record.Status == 0 {
+			record.Status = http.StatusOK
+		}
+		if ip := s.clientIP(r); ip != nil {
+			record.RemoteIP = ip.String()
+		}
+		if err := s.audit.write(record); err != nil {
+			s.logger().Warn("Не удалось записать журнал аудита", "path", s.config.AuditLogPath, "error", err)
+		}
+	}
+}
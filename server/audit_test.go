@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readAuditLog возвращает записи журнала аудита
+func readAuditLog(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Ошибка открытия журнала: %v", err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Некорректная строка журнала %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditLog_Uploads(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:        t.TempDir(),
+		AuditLogPath:     auditPath,
+		MaxFileSizeBytes: 16,
+	})
+	handler := s.Handler()
+
+	content := []byte("audited upload")
+	for _, tc := range []struct {
+		name    string
+		content []byte
+		status  int
+	}{
+		{"ok.bin", content, http.StatusOK},
+		{"large.bin", []byte(strings.Repeat("x", 64)), http.StatusRequestEntityTooLarge},
+	} {
+		req := newUploadRequest(t, tc.name, tc.content)
+		req.RemoteAddr = "192.0.2.10:5000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Fatalf("%s: ожидался статус %d, получен %d", tc.name, tc.status, rec.Code)
+		}
+	}
+
+	// Shutdown сбрасывает буфер журнала на диск
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Ошибка остановки: %v", err)
+	}
+	records := readAuditLog(t, auditPath)
+	if len(records) != 2 {
+		t.Fatalf("Ожидалось 2 записи, получено %d: %+v", len(records), records)
+	}
+
+	sum := sha256.Sum256(content)
+	ok := records[0]
+	if ok.Filename != "ok.bin" || ok.SizeBytes != int64(len(content)) || ok.Status != http.StatusOK ||
+		ok.Error != "" || ok.SHA256 != hex.EncodeToString(sum[:]) || ok.RemoteIP != "192.0.2.10" || ok.Timestamp.IsZero() {
+		t.Errorf("Неверная запись об успешной загрузке: %+v", ok)
+	}
+	failed := records[1]
+	if failed.Status != http.StatusRequestEntityTooLarge || failed.Error == "" || failed.SHA256 != "" {
+		t.Errorf("Неверная запись об отклоненной загрузке: %+v", failed)
+	}
+}
+
+func TestAuditLog_Rotation(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	log := newAuditLog(auditPath, 1)
+	log.maxSize = 300
+	defer log.close()
+
+	for i := 0; i < 5; i++ {
+		if err := log.write(AuditRecord{Filename: "file.bin", Status: http.StatusOK}); err != nil {
+			t.Fatalf("Ошибка записи: %v", err)
+		}
+	}
+	if err := log.close(); err != nil {
+		t.Fatalf("Ошибка закрытия: %v", err)
+	}
+
+	rotated, _ := filepath.Glob(auditPath + ".*")
+	if len(rotated) == 0 {
+		t.Fatal("Журнал не ротирован")
+	}
+	total := len(readAuditLog(t, auditPath))
+	for _, path := range rotated {
+		info, _ := os.Stat(path)
+		if info.Size() > 300 {
+			t.Errorf("Ротированный файл превышает лимит: %d", info.Size())
+		}
+		total += len(readAuditLog(t, path))
+	}
+	if total != 5 {
+		t.Errorf("Потеряны записи при ротации: %d", total)
+	}
+
+	if newAuditLog("", 0) != nil {
+		t.Error("Без пути журнал должен отключаться")
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultChecksumAlgo алгоритм контрольной суммы, если параметр algo не указан
+const defaultChecksumAlgo = "sha256"
+
+// checksumAlgos поддерживаемые алгоритмы GET /files/{filename}/checksum
+var checksumAlgos = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HashAlgorithm алгоритм контрольной суммы, вычисляемой при загрузке (ServerConfig.ComputeHashOnUpload)
+type HashAlgorithm string
+
+// Алгоритмы ServerConfig.ComputeHashOnUpload
+const (
+	HashNone   HashAlgorithm = "none"
+	HashMD5    HashAlgorithm = "md5"
+	HashSHA256 HashAlgorithm = "sha256"
+)
+
+// uploadChecksum возвращает reader, вычисляющий контрольную сумму прочитанных данных
+// алгоритмом ComputeHashOnUpload. Если сумма не вычисляется, r возвращается без изменений,
+// а хеш равен nil
+func (s *HTTPServer) uploadChecksum(r io.Reader) (io.Reader, hash.Hash) {
+	switch s.config.ComputeHashOnUpload {
+	case HashMD5, HashSHA256:
+		hasher := checksumAlgos[string(s.config.ComputeHashOnUpload)]()
+		return io.TeeReader(r, hasher), hasher
+	default:
+		return r, nil
+	}
+}
+
+// FileChecksum ответ на GET /files/{filename}/checksum
+type FileChecksum struct {
+	Filename   string    `json:"filename"`
+	Algo       string    `json:"algo"`
+	Checksum   string    `json:"checksum"` // В hex
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// FileOpener хранилище, позволяющее прочитать сохраненный файл
+type FileOpener interface {
+	// Open открывает файл для чтения; если файла нет, возвращает ошибку,
+	// для которой errors.Is(err, fs.ErrNotExist) истинно
+	Open(filename string) (io.ReadCloser, error)
+}
+
+// Open реализует FileOpener
+func (b *LocalStorageBackend) Open(filename string) (io.ReadCloser, error) {
+	file, err := os.Open(b.path(filename))
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, fmt.Errorf("%s не является файлом: %w", filename, fs.ErrNotExist)
+	}
+	return file, nil
+}
+
+// Open реализует FileOpener
+func (b *MemoryStorageBackend) Open(filename string) (io.ReadCloser, error) {
+	data, ok := b.Contents(filename)
+	if !ok {
+		return nil, fmt.Errorf("файл %s не найден: %w", filename, fs.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// checksumCache хранит вычисленные контрольные суммы в течение ttl, чтобы
+// периодический опрос не перечитывал файл целиком. Записи файла сбрасываются
+// при его загрузке и удалении
+type checksumCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]map[string]FileChecksum // Имя файла -> алгоритм -> сумма
+}
+
+// newChecksumCache создает кэш; при ttl <= 0 возвращает nil
+func newChecksumCache(ttl time.Duration) *checksumCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &checksumCache{ttl: ttl, entries: make(map[string]map[string]FileChecksum)}
+}
+
+// get возвращает сумму, вычисленную не раньше now-ttl
+func (c *checksumCache) get(filename, algo string, now time.Time) (FileChecksum, bool) {
+	if c == nil {
+		return FileChecksum{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sum, ok := c.entries[filename][algo]
+	if !ok || now.Sub(sum.ComputedAt) >= c.ttl {
+		return FileChecksum{}, false
+	}
+	return sum, true
+}
+
+// put сохраняет сумму и удаляет устаревшие записи
+func (c *checksumCache) put(sum FileChecksum) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, sums := range c.entries {
+		for algo, cached := range sums {
+			if sum.ComputedAt.Sub(cached.ComputedAt) >= c.ttl {
+				delete(sums, algo)
+			}
+		}
+		if len(sums) == 0 {
+			delete(c.entries, name)
+		}
+	}
+	if c.entries[sum.Filename] == nil {
+		c.entries[sum.Filename] = make(map[string]FileChecksum)
+	}
+	c.entries[sum.Filename][sum.Algo] = sum
+}
+
+// invalidate удаляет суммы файла, содержимое которого изменилось
+func (c *checksumCache) invalidate(filename string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, filename)
+}
+
+// fileChecksum вычисляет контрольную сумму файла алгоритмом algo или берет ее из кэша
+func (s *HTTPServer) fileChecksum(opener FileOpener, filename, algo string) (FileChecksum, error) {
+	if sum, ok := s.checksums.get(filename, algo, time.Now()); ok {
+		return sum, nil
+	}
+
+	file, err := opener.Open(filename)
+	if err != nil {
+		return FileChecksum{}, err
+	}
+	defer file.Close()
+
+	hasher := checksumAlgos[algo]()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return FileChecksum{}, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+
+	sum := FileChecksum{
+		Filename:   filename,
+		Algo:       algo,
+		Checksum:   hex.EncodeToString(hasher.Sum(nil)),
+		ComputedAt: time.Now().UTC(),
+	}
+	s.checksums.put(sum)
+	return sum, nil
+}
+
+// handleFileChecksum отдает контрольную сумму файла без передачи содержимого:
+// GET /files/{filename}/checksum?algo=sha256
+func (s *HTTPServer) handleFileChecksum(w http.ResponseWriter, r *http.Request, filename string) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	algo := strings.ToLower(r.URL.Query().Get("algo"))
+	if algo == "" {
+		algo = defaultChecksumAlgo
+	}
+	if _, ok := checksumAlgos[algo]; !ok {
+		names := make([]string, 0, len(checksumAlgos))
+		for name := range checksumAlgos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		s.requestError(w, r, fmt.Sprintf("Неизвестный алгоритм %s, поддерживаются: %s", algo, strings.Join(names, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	opener, ok := s.storage.(FileOpener)
+	if !ok {
+		s.requestError(w, r, "Хранилище не поддерживает чтение файлов", http.StatusNotImplemented)
+		return
+	}
+
+	sum, err := s.fileChecksum(opener, filename, algo)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.requestError(w, r, fmt.Sprintf("Файл %s не найден", filename), http.StatusNotFound)
+			return
+		}
+		s.requestError(w, r, fmt.Sprintf("Ошибка вычисления контрольной суммы: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sum)
+}
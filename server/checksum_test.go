@@ -0,0 +1,156 @@
+package server
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// getChecksum выполняет GET /files/{name}/checksum через обработчик сервера
+func getChecksum(t *testing.T, s *HTTPServer, name, algo string) (*httptest.ResponseRecorder, FileChecksum) {
+	t.Helper()
+
+	target := "/files/" + name + "/checksum"
+	if algo != "" {
+		target += "?algo=" + algo
+	}
+	rec := httptest.NewRecorder()
+	s.handleFile(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var sum FileChecksum
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&sum); err != nil {
+			t.Fatalf("Ошибка разбора ответа: %v", err)
+		}
+	}
+	return rec, sum
+}
+
+func TestHandleFileChecksum(t *testing.T) {
+	uploadDir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, ChecksumCacheTTL: time.Hour})
+	content := []byte("checksum content")
+	upload(t, s, "sum.bin", content)
+
+	sha := sha256.Sum256(content)
+	rec, sum := getChecksum(t, s, "sum.bin", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	if sum.Filename != "sum.bin" || sum.Algo != "sha256" || sum.Checksum != hex.EncodeToString(sha[:]) || sum.ComputedAt.IsZero() {
+		t.Errorf("Неверный ответ: %+v", sum)
+	}
+
+	md := md5.Sum(content)
+	if _, sum := getChecksum(t, s, "sum.bin", "MD5"); sum.Algo != "md5" || sum.Checksum != hex.EncodeToString(md[:]) {
+		t.Errorf("Неверная сумма md5: %+v", sum)
+	}
+
+	// Изменение файла в обход сервера не видно до истечения TTL
+	os.WriteFile(filepath.Join(uploadDir, "sum.bin"), []byte("changed"), 0644)
+	if _, cached := getChecksum(t, s, "sum.bin", ""); cached.Checksum != hex.EncodeToString(sha[:]) {
+		t.Errorf("Сумма не взята из кэша: %+v", cached)
+	}
+
+	// Повторная загрузка сбрасывает кэш
+	upload(t, s, "sum.bin", []byte("reuploaded"))
+	sha = sha256.Sum256([]byte("reuploaded"))
+	if _, sum := getChecksum(t, s, "sum.bin", ""); sum.Checksum != hex.EncodeToString(sha[:]) {
+		t.Errorf("Сумма не пересчитана после загрузки: %+v", sum)
+	}
+
+	for _, tc := range []struct {
+		name, algo string
+		status     int
+	}{
+		{"sum.bin", "crc32", http.StatusBadRequest},
+		{"missing.bin", "sha1", http.StatusNotFound},
+	} {
+		if rec, _ := getChecksum(t, s, tc.name, tc.algo); rec.Code != tc.status {
+			t.Errorf("%s?algo=%s: ожидался статус %d, получен %d", tc.name, tc.algo, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestChecksumCache_Expiry(t *testing.T) {
+	cache := newChecksumCache(time.Minute)
+	now := time.Now()
+	cache.put(FileChecksum{Filename: "a.bin", Algo: "sha256", Checksum: "old", ComputedAt: now.Add(-2 * time.Minute)})
+	if _, ok := cache.get("a.bin", "sha256", now); ok {
+		t.Error("Устаревшая сумма возвращена из кэша")
+	}
+
+	// Новая запись вытесняет устаревшие
+	cache.put(FileChecksum{Filename: "b.bin", Algo: "sha256", Checksum: "new", ComputedAt: now})
+	if _, ok := cache.entries["a.bin"]; ok {
+		t.Error("Устаревшая запись не удалена")
+	}
+	if sum, ok := cache.get("b.bin", "sha256", now); !ok || sum.Checksum != "new" {
+		t.Errorf("Свежая сумма не найдена: %+v", sum)
+	}
+
+	cache.invalidate("b.bin")
+	if _, ok := cache.get("b.bin", "sha256", now); ok {
+		t.Error("Сумма не сброшена")
+	}
+	if newChecksumCache(0) != nil {
+		t.Error("При нулевом TTL кэш должен отключаться")
+	}
+}
+
+func TestHandleUpload_ComputeHashOnUpload(t *testing.T) {
+	content := []byte("upload checksum")
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+
+	tests := []struct {
+		algo     HashAlgorithm
+		expected string
+	}{
+		{HashMD5, hex.EncodeToString(md5Sum[:])},
+		{HashSHA256, hex.EncodeToString(sha256Sum[:])},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algo), func(t *testing.T) {
+			s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), ComputeHashOnUpload: tt.algo, ChecksumCacheTTL: time.Minute})
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, newUploadRequest(t, "sum.bin", content))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var response uploadResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Ошибка разбора ответа %q: %v", rec.Body.String(), err)
+			}
+			if response.Checksum != tt.expected || response.ChecksumAlgorithm != string(tt.algo) {
+				t.Errorf("Неверная сумма в ответе: %+v, ожидалось %s", response, tt.expected)
+			}
+
+			// Вычисленная при загрузке сумма попадает в кэш GET /files/{filename}/checksum
+			if sum, ok := s.checksums.get("sum.bin", string(tt.algo), time.Now()); !ok || sum.Checksum != tt.expected {
+				t.Errorf("Сумма не сохранена в кэше: %+v", sum)
+			}
+		})
+	}
+
+	// Без ComputeHashOnUpload ответ остается текстовым
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), ComputeHashOnUpload: HashNone})
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "sum.bin", content))
+	if rec.Header().Get("Content-Type") == "application/json" {
+		t.Errorf("Ответ без суммы не должен быть JSON: %s", rec.Body.String())
+	}
+
+	if err := (&ServerConfig{ComputeHashOnUpload: "crc32"}).validate(); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного алгоритма")
+	}
+}
@@ -0,0 +1,523 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxChunks ограничивает количество частей одного файла
+const maxChunks = 10000
+
+// defaultChunkSessionTTL время без новых частей, после которого сессия удаляется,
+// если ChunkSessionTTL не задан
+const defaultChunkSessionTTL = 24 * time.Hour
+
+// maxChunkSweepInterval наибольший интервал между проверками сессий
+const maxChunkSweepInterval = time.Hour
+
+// chunkSessionPattern допустимый идентификатор сессии загрузки по частям.
+// Идентификатор становится именем директории, поэтому разделители пути запрещены
+var chunkSessionPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// chunkUpload состояние загрузки файла по частям
+type chunkUpload struct {
+	total    int
+	received map[int]int64     // Номер части -> размер
+	updated  time.Time         // Время последнего обращения к сессии
+	quota    *quotaReservation // Место в квоте хранилища, занятое частями (nil без квоты)
+}
+
+// size возвращает общий размер принятых частей, кроме части except
+func (u *chunkUpload) size(except int) int64 {
+	var size int64
+	for index, n := range u.received {
+		if index != except {
+			size += n
+		}
+	}
+	return size
+}
+
+// chunkStore хранит принятые части во временной директории до сборки файла.
+// Сессии без новых частей дольше ttl удаляются фоновой проверкой
+type chunkStore struct {
+	dir    string
+	quota  *storageQuota // nil, если квота не задана
+	ttl    time.Duration // Отрицательное — сессии не удаляются
+	logger func() *slog.Logger
+
+	mu      sync.Mutex
+	uploads map[string]*chunkUpload
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// newChunkStore создает хранилище частей в директории dir. Части учитываются в квоте quota
+func newChunkStore(dir string, quota *storageQuota, ttl time.Duration, logger func() *slog.Logger) *chunkStore {
+	if ttl == 0 {
+		ttl = defaultChunkSessionTTL
+	}
+	return &chunkStore{dir: dir, quota: quota, ttl: ttl, logger: logger, uploads: make(map[string]*chunkUpload)}
+}
+
+// chunkPath возвращает путь части на диске
+func (c *chunkStore) chunkPath(session string, index int) string {
+	return filepath.Join(c.dir, session, strconv.Itoa(index))
+}
+
+// register проверяет, что количество частей совпадает с указанным ранее
+func (c *chunkStore) register(session string, total int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	upload, ok := c.uploads[session]
+	if !ok {
+		c.uploads[session] = &chunkUpload{
+			total:    total,
+			received: make(map[int]int64),
+			updated:  time.Now(),
+			quota:    c.quota.empty(),
+		}
+		return nil
+	}
+	if upload.total != total {
+		return fmt.Errorf("количество частей %d не совпадает с ранее указанным %d", total, upload.total)
+	}
+	return nil
+}
+
+// remaining возвращает, сколько байт может занять часть index, чтобы общий размер
+// частей сессии не превысил maxSize (0 — без ограничения)
+func (c *chunkStore) remaining(session string, index int, maxSize int64) (int64, error) {
+	if maxSize <= 0 {
+		return 0, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	upload, ok := c.uploads[session]
+	if !ok {
+		return 0, errChunkSessionNotFound
+	}
+	remaining := maxSize - upload.size(index)
+	if remaining <= 0 {
+		return 0, errFileTooLarge
+	}
+	return remaining, nil
+}
+
+// save записывает часть во временный файл и переносит ее на место,
+// поэтому повторная отправка части заменяет ее целиком. Перед переносом
+// общий размер частей сверяется с maxSize (0 — без ограничения), а место
+// части из резерва запроса reservation переходит в резерв сессии
+func (c *chunkStore) save(session string, index int, r io.Reader, maxSize int64, reservation *quotaReservation) (int64, error) {
+	if err := os.MkdirAll(filepath.Join(c.dir, session), 0755); err != nil {
+		return 0, fmt.Errorf("ошибка создания директории: %w", err)
+	}
+
+	chunkPath := c.chunkPath(session, index)
+	tmpPath, err := tempFilePath(chunkPath)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	written, err := io.CopyBuffer(dst, r, make([]byte, 64*1024))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, fmt.Errorf("ошибка записи части: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	upload, ok := c.uploads[session]
+	if !ok {
+		// Сессия удалена по истечении времени, пока принималась часть
+		return written, errChunkSessionNotFound
+	}
+	size := upload.size(index) + written
+	if maxSize > 0 && size > maxSize {
+		return written, errFileTooLarge
+	}
+	reservation.release()
+	if err := upload.quota.cover(size); err != nil {
+		return written, err
+	}
+	if err := os.Rename(tmpPath, chunkPath); err != nil {
+		return written, fmt.Errorf("ошибка сохранения части: %w", err)
+	}
+	upload.received[index] = written
+	upload.updated = time.Now()
+	return written, nil
+}
+
+// complete возвращает количество частей и их общий размер, если все части приняты
+func (c *chunkStore) complete(session string) (int, int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	upload, ok := c.uploads[session]
+	if !ok {
+		return 0, 0, errChunkSessionNotFound
+	}
+	// Сборка не должна начаться перед удалением сессии по истечении времени
+	upload.updated = time.Now()
+	if len(upload.received) != upload.total {
+		return 0, 0, fmt.Errorf("принято %d из %d частей", len(upload.received), upload.total)
+	}
+	return upload.total, upload.size(-1), nil
+}
+
+// remove удаляет части и состояние сессии и освобождает занятое ими место в квоте
+func (c *chunkStore) remove(session string) {
+	c.mu.Lock()
+	if upload, ok := c.uploads[session]; ok {
+		upload.quota.release()
+		delete(c.uploads, session)
+	}
+	c.mu.Unlock()
+	os.RemoveAll(filepath.Join(c.dir, session))
+}
+
+// start запускает периодическое удаление сессий без новых частей дольше ttl
+func (c *chunkStore) start() {
+	if c.ttl < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.loop(c.stop, c.done)
+}
+
+// loop удаляет устаревшие сессии, пока не закрыт stop. Директории сессий, оставшиеся
+// после предыдущего запуска сервера, удаляются сразу, если они старше ttl
+func (c *chunkStore) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	c.expire(time.Now())
+	ticker := time.NewTicker(min(c.ttl, maxChunkSweepInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c.expire(now)
+		}
+	}
+}
+
+// expire удаляет сессии, к которым не обращались дольше ttl на момент now, а также
+// директории неизвестных сессий, измененные раньше. Возвращает количество удаленных сессий
+func (c *chunkStore) expire(now time.Time) int {
+	c.mu.Lock()
+	var expired []string
+	for session, upload := range c.uploads {
+		if now.Sub(upload.updated) >= c.ttl {
+			expired = append(expired, session)
+		}
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.logger().Warn("Ошибка проверки сессий загрузки по частям", "dir", c.dir, "error", err)
+	}
+	for _, entry := range entries {
+		if _, ok := c.uploads[entry.Name()]; ok || !entry.IsDir() || !chunkSessionPattern.MatchString(entry.Name()) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) >= c.ttl {
+			expired = append(expired, entry.Name())
+		}
+	}
+	c.mu.Unlock()
+
+	for _, session := range expired {
+		c.remove(session)
+		c.logger().Info("Удалена устаревшая сессия загрузки по частям", "session", session)
+	}
+	return len(expired)
+}
+
+// close останавливает периодические проверки
+func (c *chunkStore) close() {
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// errChunkSessionNotFound возвращается для неизвестной сессии загрузки по частям
+var errChunkSessionNotFound = errors.New("сессия загрузки по частям не найдена")
+
+// chunkReader последовательно читает части файла, открывая их по одной
+type chunkReader struct {
+	store   *chunkStore
+	session string
+	total   int
+	index   int
+	current *os.File
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if c.current == nil {
+			if c.index == c.total {
+				return 0, io.EOF
+			}
+			file, err := os.Open(c.store.chunkPath(c.session, c.index))
+			if err != nil {
+				return 0, fmt.Errorf("ошибка открытия части %d: %w", c.index, err)
+			}
+			c.current = file
+		}
+
+		n, err := c.current.Read(p)
+		if err == io.EOF {
+			c.current.Close()
+			c.current = nil
+			c.index++
+			if n == 0 {
+				continue
+			}
+			return n, nil
+		}
+		return n, err
+	}
+}
+
+// Close закрывает открытую часть
+func (c *chunkReader) Close() error {
+	if c.current != nil {
+		return c.current.Close()
+	}
+	return nil
+}
+
+// handleChunk принимает одну часть файла:
+// POST /upload/chunk?session={id}&index={i}&total={n}, тело запроса — содержимое части
+func (s *HTTPServer) handleChunk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.httpError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session := r.URL.Query().Get("session")
+	if !chunkSessionPattern.MatchString(session) {
+		s.httpError(w, r, "Некорректный идентификатор сессии", http.StatusBadRequest)
+		return
+	}
+	total, err := queryInt(r, "total", 0)
+	if err != nil || total < 1 || total > maxChunks {
+		s.httpError(w, r, fmt.Sprintf("Параметр total должен быть от 1 до %d", maxChunks), http.StatusBadRequest)
+		return
+	}
+	index, err := queryInt(r, "index", -1)
+	if err != nil || index < 0 || index >= total {
+		s.httpError(w, r, "Некорректный номер части", http.StatusBadRequest)
+		return
+	}
+
+	maxFileSize := s.config.MaxFileSizeBytes
+	if maxFileSize > 0 && r.ContentLength > maxFileSize {
+		s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := s.chunks.register(session, total); err != nil {
+		s.httpError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Лимит размера относится к файлу целиком, поэтому часть ограничена остатком
+	// после уже принятых частей сессии
+	limit, err := s.chunks.remaining(session, index, maxFileSize)
+	if err == nil && limit > 0 && r.ContentLength > limit {
+		err = errFileTooLarge
+	}
+	if errors.Is(err, errFileTooLarge) {
+		s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Часть учитывается в квоте по мере приема, а после сохранения ее место переходит
+	// в резерв сессии и освобождается после сборки или удаления сессии
+	reservation, ok := s.reserveQuota(w, r, r.ContentLength)
+	if !ok {
+		return
+	}
+	defer reservation.release()
+
+	body := &uploadReader{r: r.Body, limit: limit, quota: reservation}
+	written, err := s.chunks.save(session, index, body, maxFileSize, reservation)
+	switch {
+	case errors.Is(err, errFileTooLarge):
+		s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, errQuotaExceeded):
+		s.httpError(w, r, fmt.Sprintf("Недостаточно места: квота хранилища %s", formatBytes(s.config.StorageQuotaBytes)), http.StatusInsufficientStorage)
+		return
+	case errors.Is(err, errChunkSessionNotFound):
+		s.httpError(w, r, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		s.httpError(w, r, fmt.Sprintf("Не удалось сохранить часть: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger().Debug("Часть принята", "session", session, "index", index, "total", total, "size", formatBytes(written))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(fmt.Sprintf("Часть %d из %d принята", index+1, total)))
+}
+
+// handleFinalize собирает файл из принятых частей и передает его в хранилище:
+// POST /upload/finalize?session={id}&filename={name}
+func (s *HTTPServer) handleFinalize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.httpError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session := r.URL.Query().Get("session")
+	if !chunkSessionPattern.MatchString(session) {
+		s.httpError(w, r, "Некорректный идентификатор сессии", http.StatusBadRequest)
+		return
+	}
+	filename := r.URL.Query().Get("filename")
+	if filename == "" {
+		s.httpError(w, r, "Не указано имя файла", http.StatusBadRequest)
+		return
+	}
+	if !s.checkUploadMetadata(w, r) {
+		return
+	}
+	storageName, err := expandPathTemplate(s.config.UploadPathTemplate, sanitizeFilename(filename), time.Now())
+	if err != nil {
+		s.httpError(w, r, fmt.Sprintf("Ошибка построения пути файла: %v", err), http.StatusInternalServerError)
+		return
+	}
+	storageName, err = s.withClientUploadDir(r, storageName)
+	if err != nil {
+		s.httpError(w, r, fmt.Sprintf("Некорректная директория загрузки: %v", err), http.StatusBadRequest)
+		return
+	}
+	auditFile(r, filename, 0, nil)
+	defer s.fileLocks.lock(storageName)()
+
+	total, size, err := s.chunks.complete(session)
+	switch {
+	case errors.Is(err, errChunkSessionNotFound):
+		s.httpError(w, r, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		s.httpError(w, r, fmt.Sprintf("Загрузка не завершена: %v", err), http.StatusConflict)
+		return
+	}
+
+	maxFileSize := s.config.MaxFileSizeBytes
+	if maxFileSize > 0 && size > maxFileSize {
+		s.chunks.remove(session)
+		s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Место в квоте уже занято частями сессии и освобождается при ее удалении после сборки
+	startTime := time.Now()
+	logger := s.logger().With("file", filename, "remote_addr", r.RemoteAddr, "chunk_session", session)
+
+	reader := &chunkReader{store: s.chunks, session: session, total: total}
+	defer reader.Close()
+	checked, contentType, err := s.checkMIMEType(reader)
+	if err != nil {
+		if errors.Is(err, errMIMETypeNotAllowed) {
+			s.chunks.remove(session)
+			s.httpError(w, r, fmt.Sprintf("Файл отклонен: %v", err), http.StatusUnsupportedMediaType)
+			return
+		}
+		s.httpError(w, r, fmt.Sprintf("Ошибка чтения частей: %v", err), http.StatusInternalServerError)
+		return
+	}
+	body, hasher := s.uploadHasher(checked)
+	metadata := map[string]string{
+		MetadataOriginalName: filename,
+		MetadataRemoteAddr:   r.RemoteAddr,
+	}
+	bytesReceived, err := s.storage.Save(r.Context(), storageName, body, metadata)
+	auditFile(r, storageName, bytesReceived, nil)
+	switch {
+	case errors.Is(err, ErrFileExists):
+		s.chunks.remove(session)
+		logger.Info("Файл уже существует, загрузка пропущена", "path", storageName)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf("Файл %s уже существует, загрузка пропущена", storageName)))
+		return
+	case errors.Is(err, ErrReservedName):
+		s.chunks.remove(session)
+		s.httpError(w, r, fmt.Sprintf("Файл отклонен: %v", err), http.StatusBadRequest)
+		return
+	case err != nil:
+		// Части сохраняются, чтобы сборку можно было повторить
+		s.httpError(w, r, fmt.Sprintf("Не удалось сохранить файл: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.chunks.remove(session)
+
+	storedName := storageName
+	if name := metadata[MetadataStoredName]; name != "" {
+		storedName = name
+	}
+	if err := s.saveUploadMetadata(r, storedName, contentType); err != nil {
+		s.httpError(w, r, fmt.Sprintf("Файл сохранен, но не удалось сохранить метаданные: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.checksums.invalidate(storedName)
+	auditFile(r, storedName, bytesReceived, hasher)
+	duration := time.Since(startTime)
+	s.metrics.observeUpload(bytesReceived, duration)
+	logger.Info("Файл собран из частей",
+		"path", storedName,
+		"chunks", total,
+		"size", formatBytes(bytesReceived),
+		"duration", formatDuration(duration))
+	s.notifyUpload(UploadEvent{
+		Filename:         filename,
+		SizeBytes:        bytesReceived,
+		SavedPath:        storedName,
+		UploadDurationMs: duration.Milliseconds(),
+	}, hasher)
+	s.runPostUpload(storedName)
+
+	w.WriteHeader(http.StatusOK)
+	if storedName != storageName {
+		w.Write([]byte(fmt.Sprintf("Файл %s успешно загружен как %s", filename, path.Base(storedName))))
+		return
+	}
+	w.Write([]byte(fmt.Sprintf("Файл %s успешно загружен", filename)))
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postChunk отправляет часть через обработчики сервера и возвращает код ответа
+func postChunk(handler http.Handler, query, body string) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload/chunk?"+query, strings.NewReader(body)))
+	return rec.Code
+}
+
+// finalize собирает файл через обработчики сервера и возвращает код ответа
+func finalize(handler http.Handler, query string) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload/finalize?"+query, nil))
+	return rec.Code
+}
+
+func TestChunkedUpload_OutOfOrder(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	chunkDir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: backend, ChunkDir: chunkDir, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	handler := s.Handler()
+
+	if code := postChunk(handler, "session=abc&index=2&total=3", "three"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := postChunk(handler, "session=abc&index=0&total=3", "one-"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	// Не все части приняты
+	if code := finalize(handler, "session=abc&filename=joined.bin"); code != http.StatusConflict {
+		t.Errorf("Ожидался статус 409, получен %d", code)
+	}
+
+	if code := postChunk(handler, "session=abc&index=1&total=3", "two-"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := finalize(handler, "session=abc&filename=../joined.bin"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	data, ok := backend.Contents("joined.bin")
+	if !ok || string(data) != "one-two-three" {
+		t.Errorf("Неверное содержимое собранного файла: %q", data)
+	}
+
+	// Части удаляются после сборки
+	entries, _ := os.ReadDir(chunkDir)
+	if len(entries) != 0 {
+		t.Errorf("Части не удалены после сборки: %v", entries)
+	}
+	if code := finalize(handler, "session=abc&filename=joined.bin"); code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404 для завершенной сессии, получен %d", code)
+	}
+}
+
+func TestChunkedUpload_InvalidRequests(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: NewMemoryStorageBackend(), ChunkDir: t.TempDir(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	handler := s.Handler()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"путь в идентификаторе сессии", "session=..%2Fetc&index=0&total=1"},
+		{"номер части вне диапазона", "session=abc&index=1&total=1"},
+		{"нулевое количество частей", "session=abc&index=0&total=0"},
+		{"нечисловой номер", "session=abc&index=x&total=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := postChunk(handler, tt.query, "data"); code != http.StatusBadRequest {
+				t.Errorf("Ожидался статус 400, получен %d", code)
+			}
+		})
+	}
+
+	// Количество частей не может меняться в пределах сессии
+	postChunk(handler, "session=abc&index=0&total=2", "data")
+	if code := postChunk(handler, "session=abc&index=1&total=3", "data"); code != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400 при смене количества частей, получен %d", code)
+	}
+
+	if code := finalize(handler, "session=abc"); code != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400 без имени файла, получен %d", code)
+	}
+}
+
+func TestChunkedUpload_SessionSizeLimit(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:          backend,
+		ChunkDir:         t.TempDir(),
+		MaxFileSizeBytes: 10,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	handler := s.Handler()
+
+	if code := postChunk(handler, "session=abc&index=0&total=2", "123456"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	// Каждая часть меньше лимита, но вместе они его превышают
+	if code := postChunk(handler, "session=abc&index=1&total=2", "78901"); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Ожидался статус 413, получен %d", code)
+	}
+
+	// Повторная отправка части заменяет ее размер в общем итоге
+	if code := postChunk(handler, "session=abc&index=0&total=2", "1234"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := postChunk(handler, "session=abc&index=1&total=2", "567890"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := finalize(handler, "session=abc&filename=joined.bin"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if data, ok := backend.Contents("joined.bin"); !ok || string(data) != "1234567890" {
+		t.Errorf("Неверное содержимое собранного файла: %q", data)
+	}
+}
+
+func TestChunkedUpload_Quota(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:         t.TempDir(),
+		ChunkDir:          t.TempDir(),
+		StorageQuotaBytes: 10,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	handler := s.Handler()
+
+	if code := postChunk(handler, "session=a&index=0&total=2", "123456"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	// Части незавершенной сессии уже занимают место в квоте
+	if code := postChunk(handler, "session=b&index=0&total=1", "123456"); code != http.StatusInsufficientStorage {
+		t.Fatalf("Ожидался статус 507, получен %d", code)
+	}
+
+	// Удаление сессии освобождает место
+	s.chunks.remove("a")
+	if code := postChunk(handler, "session=b&index=0&total=1", "123456"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200 после удаления сессии, получен %d", code)
+	}
+	if code := finalize(handler, "session=b&filename=b.bin"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if s.quota.reserved != 0 {
+		t.Errorf("Резерв квоты не освобожден после сборки: %d", s.quota.reserved)
+	}
+}
+
+func TestChunkStore_Expire(t *testing.T) {
+	dir := t.TempDir()
+	quota := newStorageQuota(t.TempDir(), 100)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := newChunkStore(dir, quota, time.Hour, func() *slog.Logger { return logger })
+
+	if err := store.register("active", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.save("active", 0, strings.NewReader("data"), 0, nil); err != nil {
+		t.Fatalf("Ошибка сохранения части: %v", err)
+	}
+	// Директория сессии, оставшаяся после предыдущего запуска сервера
+	stale := filepath.Join(dir, "stale")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed := store.expire(time.Now().Add(30 * time.Minute)); removed != 0 {
+		t.Errorf("Сессии удалены до истечения времени: %d", removed)
+	}
+	if removed := store.expire(time.Now().Add(2 * time.Hour)); removed != 2 {
+		t.Errorf("Ожидалось удаление 2 сессий, удалено %d", removed)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Части устаревших сессий не удалены: %v", entries)
+	}
+	if quota.reserved != 0 {
+		t.Errorf("Резерв квоты не освобожден: %d", quota.reserved)
+	}
+	if _, _, err := store.complete("active"); !errors.Is(err, errChunkSessionNotFound) {
+		t.Errorf("Ожидалась ошибка неизвестной сессии, получено %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPathHeaderName заголовок с поддиректорией загрузки, если ServerConfig.PathHeaderName не задан
+const DefaultPathHeaderName = "X-Upload-Path"
+
+// clientUploadDir возвращает поддиректорию UploadDir из заголовка PathHeaderName.
+// Без AllowClientPath заголовок игнорируется; путь очищается так же, как X-Relative-Path,
+// и не может выйти за пределы директории загрузки
+func (s *HTTPServer) clientUploadDir(r *http.Request) (string, error) {
+	if !s.config.AllowClientPath {
+		return "", nil
+	}
+	headerName := s.config.PathHeaderName
+	if headerName == "" {
+		headerName = DefaultPathHeaderName
+	}
+	value := strings.TrimSpace(r.Header.Get(headerName))
+	if value == "" {
+		return "", nil
+	}
+	dir, err := cleanRelativePath(value)
+	if err != nil {
+		return "", fmt.Errorf("заголовок %s: %w", headerName, err)
+	}
+	return dir, nil
+}
+
+// withClientUploadDir добавляет к имени файла в хранилище поддиректорию из заголовка PathHeaderName.
+// Имена в StorageBackend разделяются "/" независимо от ОС, поэтому используется path.Join
+func (s *HTTPServer) withClientUploadDir(r *http.Request, storageName string) (string, error) {
+	dir, err := s.clientUploadDir(r)
+	if err != nil || dir == "" {
+		return storageName, err
+	}
+	return path.Join(filepath.ToSlash(dir), storageName), nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleUpload_ClientPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		allow      bool
+		headerName string
+		header     string
+		value      string
+		wantStatus int
+		wantPath   string
+	}{
+		{"поддиректория", true, "", DefaultPathHeaderName, "tenants/acme", http.StatusOK, "tenants/acme/report.bin"},
+		{"свой заголовок", true, "X-Tenant-Dir", "X-Tenant-Dir", "globex", http.StatusOK, "globex/report.bin"},
+		{"очистка компонентов", true, "", DefaultPathHeaderName, "a b/./c", http.StatusOK, "a_b/c/report.bin"},
+		{"без AllowClientPath", false, "", DefaultPathHeaderName, "tenants/acme", http.StatusOK, "report.bin"},
+		{"выход за пределы", true, "", DefaultPathHeaderName, "../outside", http.StatusBadRequest, ""},
+		{"абсолютный путь", true, "", DefaultPathHeaderName, "/etc", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				UploadDir:       dir,
+				AllowClientPath: tt.allow,
+				PathHeaderName:  tt.headerName,
+				Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			req := newUploadRequest(t, "report.bin", []byte("report"))
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Ожидался статус %d, получен %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantPath == "" {
+				return
+			}
+			if content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.wantPath))); err != nil || string(content) != "report" {
+				t.Errorf("Файл не сохранен в %s: %v", tt.wantPath, err)
+			}
+		})
+	}
+}
+
+func TestHandleUpload_ClientPathStorageName(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:         backend,
+		AllowClientPath: true,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := newUploadRequest(t, "report.bin", []byte("report"))
+	req.Header.Set(DefaultPathHeaderName, "tenants/acme")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	// Имя в хранилище разделяется "/" на любой ОС
+	if content, ok := backend.Contents("tenants/acme/report.bin"); !ok || string(content) != "report" {
+		t.Errorf("Файл не сохранен под именем tenants/acme/report.bin: %q", content)
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// notModified проверяет заголовок If-None-Match запроса на загрузку: если файл storageName
+// уже есть в хранилище и его SHA-256 совпадает с одним из перечисленных ETag (или указан *),
+// загрузку можно пропустить. Возвращает ETag сохраненного файла. Без поддержки чтения
+// файлов хранилищем (FileOpener) заголовок игнорируется
+func (s *HTTPServer) notModified(r *http.Request, storageName string) (string, bool) {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return "", false
+	}
+	opener, ok := s.storage.(FileOpener)
+	if !ok {
+		return "", false
+	}
+	if exists, err := s.storage.Exists(storageName); err != nil || !exists {
+		return "", false
+	}
+
+	sum, err := s.fileChecksum(opener, storageName, defaultChecksumAlgo)
+	if err != nil {
+		s.logger().Warn("Ошибка вычисления контрольной суммы для If-None-Match", "path", storageName, "error", err)
+		return "", false
+	}
+	etag := `"` + sum.Checksum + `"`
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || strings.EqualFold(tag, etag) {
+			return etag, true
+		}
+	}
+	return "", false
+}
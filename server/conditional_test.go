@@ -0,0 +1,65 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleUpload_IfNoneMatch(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: backend, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+	content := []byte("unchanged content")
+	hash := sha256.Sum256(content)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+	// Файла еще нет: условие не выполняется, и файл сохраняется
+	req := newUploadRequest(t, "a.bin", content)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Первая загрузка: ожидался статус 200, получен %d", rec.Code)
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		content     string
+		status      int
+	}{
+		{"совпадающая сумма", etag, "other", http.StatusNotModified},
+		{"слабый ETag в списке", `"abc", W/` + etag, "other", http.StatusNotModified},
+		{"любой файл", "*", "other", http.StatusNotModified},
+		{"другая сумма", `"abc"`, "replaced", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, _ := backend.Contents("a.bin")
+			req := newUploadRequest(t, "a.bin", []byte(tt.content))
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Ожидался статус %d, получен %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+
+			got, _ := backend.Contents("a.bin")
+			if tt.status == http.StatusNotModified {
+				if string(got) != string(before) {
+					t.Errorf("Файл перезаписан при ответе 304: %q", got)
+				}
+				if rec.Header().Get("ETag") == "" {
+					t.Error("Ответ 304 без ETag")
+				}
+			} else if string(got) != tt.content {
+				t.Errorf("Файл не перезаписан: %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package server
+
+import "net/http"
+
+// DefaultCorrelationIDHeader заголовок с идентификатором корреляции, если
+// ServerConfig.CorrelationIDHeader не задан
+const DefaultCorrelationIDHeader = "X-Correlation-ID"
+
+// correlationID возвращает идентификатор корреляции из заголовка CorrelationIDHeader.
+// Значение, которое нельзя безопасно записать в лог и вернуть в ответе, игнорируется
+func (s *HTTPServer) correlationID(r *http.Request) string {
+	id := r.Header.Get(s.correlationIDHeader())
+	if !validRequestID(id) {
+		return ""
+	}
+	return id
+}
+
+// correlationIDHeader возвращает заголовок идентификатора корреляции с учетом значения по умолчанию
+func (s *HTTPServer) correlationIDHeader() string {
+	if s.config.CorrelationIDHeader != "" {
+		return s.config.CorrelationIDHeader
+	}
+	return DefaultCorrelationIDHeader
+}
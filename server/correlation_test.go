@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleUpload_CorrelationID(t *testing.T) {
+	var logs bytes.Buffer
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	req := newUploadRequest(t, "report.bin", []byte("report"))
+	req.Header.Set(DefaultCorrelationIDHeader, "job-42")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+
+	if got := rec.Header().Get(DefaultCorrelationIDHeader); got != "job-42" {
+		t.Errorf("Идентификатор не возвращен в заголовке ответа: %q", got)
+	}
+	var response uploadResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.CorrelationID != "job-42" {
+		t.Errorf("Идентификатор не возвращен в JSON-ответе: %s", rec.Body.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if !strings.Contains(line, "correlation_id=job-42") {
+			t.Errorf("В записи лога нет идентификатора корреляции: %s", line)
+		}
+	}
+
+	// Без заголовка ответ остается текстовым, а идентификатор с управляющими символами игнорируется
+	for _, id := range []string{"", "bad\x01id"} {
+		req := newUploadRequest(t, "plain.bin", []byte("plain"))
+		if id != "" {
+			req.Header.Set(DefaultCorrelationIDHeader, id)
+		}
+		rec := httptest.NewRecorder()
+		s.handleUpload(rec, req)
+		if rec.Header().Get(DefaultCorrelationIDHeader) != "" || !strings.HasPrefix(rec.Body.String(), "Файл plain.bin") {
+			t.Errorf("Для %q ожидался текстовый ответ без идентификатора: %s", id, rec.Body.String())
+		}
+	}
+}
+
+func TestCorrelationIDHeader_Custom(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: NewMemoryStorageBackend(), CorrelationIDHeader: "X-Trace"})
+
+	req := newUploadRequest(t, "report.bin", []byte("report"))
+	req.Header.Set("X-Trace", "trace-1")
+	req.Header.Set(DefaultCorrelationIDHeader, "ignored")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Header().Get("X-Trace") != "trace-1" || rec.Header().Get(DefaultCorrelationIDHeader) != "" {
+		t.Errorf("Ожидался идентификатор из X-Trace, заголовки ответа: %v", rec.Header())
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig параметры CORS (Cross-Origin Resource Sharing)
+type CORSConfig struct {
+	// AllowedOrigins источники вида https://app.example.com, которым разрешены запросы;
+	// "*" разрешает любой источник без учетных данных. Пустой список выключает CORS
+	AllowedOrigins []string
+
+	// AllowedMethods методы, разрешенные в предварительных запросах (пусто — GET, HEAD, POST)
+	AllowedMethods []string
+
+	// AllowedHeaders заголовки запроса, разрешенные в предварительных запросах, например
+	// Authorization или X-File-Size (пусто — любые запрошенные браузером)
+	AllowedHeaders []string
+
+	// ExposedHeaders заголовки ответа, доступные скрипту (пусто — X-Upload-Session-ID,
+	// X-Request-ID и заголовок идентификатора корреляции)
+	ExposedHeaders []string
+
+	MaxAge           int  // Время кэширования предварительного запроса браузером в секундах (0 — не передается)
+	AllowCredentials bool // Разрешить запросы с cookie и заголовком Authorization (только с явным списком источников)
+}
+
+// validate запрещает "*" вместе с AllowCredentials: иначе любой сайт мог бы выполнять
+// запросы с cookie и заголовком Authorization пользователя и читать ответы
+func (c CORSConfig) validate() error {
+	if c.AllowCredentials && containsFold(c.AllowedOrigins, "*") {
+		return fmt.Errorf("источник \"*\" нельзя использовать с AllowCredentials, укажите источники явно")
+	}
+	return nil
+}
+
+// defaultCORSMethods методы, разрешенные по умолчанию
+var defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// corsConfig возвращает настройки CORS сервера с заголовками ответа по умолчанию
+func (s *HTTPServer) corsConfig() CORSConfig {
+	config := s.config.CORS
+	if len(config.ExposedHeaders) == 0 {
+		config.ExposedHeaders = []string{SessionIDHeader, RequestIDHeader, s.correlationIDHeader()}
+	}
+	return config
+}
+
+// corsMiddleware добавляет заголовки Access-Control-* к ответам на запросы с разрешенных
+// источников и отвечает на предварительные запросы OPTIONS, не передавая их маршрутам,
+// поэтому они не требуют аутентификации. Запросы с других источников обрабатываются
+// без заголовков CORS, и браузер не передает ответ скрипту; предварительные запросы
+// с них, а также с неразрешенными методом или заголовками отклоняются статусом 403
+func corsMiddleware(config CORSConfig) Middleware {
+	if len(config.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	anyOrigin := false
+	for _, origin := range config.AllowedOrigins {
+		anyOrigin = anyOrigin || origin == "*"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Ответ зависит от источника и не должен кэшироваться для других
+			w.Header().Add("Vary", "Origin")
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !anyOrigin && !containsFold(config.AllowedOrigins, origin) {
+				if preflight {
+					http.Error(w, "Источник не разрешен", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Вместе с "*" AllowCredentials запрещен в ServerConfig.validate
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if len(config.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if !containsFold(methods, r.Header.Get("Access-Control-Request-Method")) {
+				http.Error(w, "Метод не разрешен", http.StatusForbidden)
+				return
+			}
+			requested := requestedHeaders(r)
+			if len(config.AllowedHeaders) > 0 {
+				for _, name := range requested {
+					if !containsFold(config.AllowedHeaders, name) {
+						http.Error(w, "Заголовок "+name+" не разрешен", http.StatusForbidden)
+						return
+					}
+				}
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if len(requested) > 0 {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+			}
+			if config.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// requestedHeaders возвращает заголовки из Access-Control-Request-Headers
+func requestedHeaders(r *http.Request) []string {
+	var names []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// containsFold проверяет наличие value в list без учета регистра
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
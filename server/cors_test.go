@@ -0,0 +1,135 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS_Preflight(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:   NewMemoryStorageBackend(),
+		AuthToken: "secret",
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CORS: CORSConfig{
+			AllowedOrigins:   []string{"https://app.example.com"},
+			AllowedHeaders:   []string{"Authorization", "X-File-Size"},
+			MaxAge:           600,
+			AllowCredentials: true,
+		},
+	})
+	handler := s.Handler()
+
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+		status  int
+	}{
+		{"разрешенный запрос", "https://app.example.com", http.MethodPost, "authorization, x-file-size", http.StatusNoContent},
+		{"регистр источника", "HTTPS://APP.EXAMPLE.COM", http.MethodPost, "", http.StatusNoContent},
+		{"чужой источник", "https://evil.example.com", http.MethodPost, "", http.StatusForbidden},
+		{"неразрешенный метод", "https://app.example.com", http.MethodPut, "", http.StatusForbidden},
+		{"неразрешенный заголовок", "https://app.example.com", http.MethodPost, "Authorization, X-Custom", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/upload", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			// Предварительный запрос не требует токена
+			if rec.Code != tt.status {
+				t.Fatalf("Ожидался статус %d, получен %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if tt.status != http.StatusNoContent {
+				return
+			}
+			h := rec.Header()
+			if h.Get("Access-Control-Allow-Origin") != tt.origin || h.Get("Access-Control-Allow-Credentials") != "true" {
+				t.Errorf("Неверные заголовки источника: %v", h)
+			}
+			if h.Get("Access-Control-Allow-Methods") != "GET, HEAD, POST" || h.Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("Неверные заголовки предварительного запроса: %v", h)
+			}
+			if h.Get("Access-Control-Allow-Headers") != tt.headers {
+				t.Errorf("Access-Control-Allow-Headers = %q, ожидалось %q", h.Get("Access-Control-Allow-Headers"), tt.headers)
+			}
+		})
+	}
+}
+
+func TestCORS_Upload(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CORS:    CORSConfig{AllowedOrigins: []string{"*"}},
+	})
+	handler := s.Handler()
+
+	req := newUploadRequest(t, "a.bin", []byte("data"))
+	req.Header.Set("Origin", "https://any.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, ожидалось *", got)
+	}
+	want := SessionIDHeader + ", " + RequestIDHeader + ", " + DefaultCorrelationIDHeader
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != want {
+		t.Errorf("Access-Control-Expose-Headers = %q, ожидалось %q", got, want)
+	}
+
+	// Без заголовка Origin ответ не содержит заголовков CORS
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newUploadRequest(t, "b.bin", []byte("data")))
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Ответ без Origin содержит Access-Control-Allow-Origin: %q", got)
+	}
+}
+
+func TestCORS_Disabled(t *testing.T) {
+	handler := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).Handler()
+
+	req := httptest.NewRequest(http.MethodOptions, "/upload", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("CORS выключен, но ответ содержит Access-Control-Allow-Origin: %q", got)
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CORSConfig
+		wantErr bool
+	}{
+		{"любой источник без учетных данных", CORSConfig{AllowedOrigins: []string{"*"}}, false},
+		{"явный источник с учетными данными", CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, false},
+		{"любой источник с учетными данными", CORSConfig{AllowedOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&ServerConfig{CORS: tt.config}).validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ожидалась ошибка: %v, получено %v", tt.wantErr, err)
+			}
+		})
+	}
+}
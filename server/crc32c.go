@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+)
+
+// ContentCRC32CHeader заголовок с ожидаемой контрольной суммой CRC32C содержимого файла:
+// 4 байта в порядке big-endian, закодированные base64 (как в Google Cloud Storage)
+const ContentCRC32CHeader = "X-Content-CRC32C"
+
+// crc32cTable таблица полинома Кастаньоли
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errChecksumMismatch возвращается при чтении последнего байта файла, если CRC32C не совпала
+var errChecksumMismatch = errors.New("контрольная сумма CRC32C не совпадает")
+
+// crc32cReader вычисляет CRC32C читаемых данных и сверяет ее с ожидаемой при достижении
+// конца. Ошибка вместо io.EOF не дает хранилищу сохранить поврежденный файл
+type crc32cReader struct {
+	r        io.Reader
+	hash     hash.Hash32
+	expected uint32
+}
+
+func (c *crc32cReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	if err == io.EOF {
+		if sum := c.hash.Sum32(); sum != c.expected {
+			return n, fmt.Errorf("%w: ожидалось %08x, получено %08x", errChecksumMismatch, c.expected, sum)
+		}
+	}
+	return n, err
+}
+
+// parseCRC32C разбирает значение заголовка X-Content-CRC32C
+func parseCRC32C(value string) (uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(raw) != 4 {
+		return 0, fmt.Errorf("ожидалось 4 байта в base64, получено %q", value)
+	}
+	return binary.BigEndian.Uint32(raw), nil
+}
+
+// verifyCRC32C оборачивает содержимое файла проверкой CRC32C из заголовка X-Content-CRC32C,
+// если проверка включена в конфигурации. Запрос без заголовка принимается без проверки
+func (s *HTTPServer) verifyCRC32C(r *http.Request, file io.Reader) (io.Reader, error) {
+	value := r.Header.Get(ContentCRC32CHeader)
+	if !s.config.VerifyCRC32C || value == "" {
+		return file, nil
+	}
+	expected, err := parseCRC32C(value)
+	if err != nil {
+		return nil, err
+	}
+	return &crc32cReader{r: file, hash: crc32.New(crc32cTable), expected: expected}, nil
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// crc32cHeader возвращает значение X-Content-CRC32C для данных
+func crc32cHeader(data []byte) string {
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.Checksum(data, crc32cTable))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestHandleUpload_VerifyCRC32C(t *testing.T) {
+	content := []byte("checked content")
+
+	tests := []struct {
+		name   string
+		verify bool
+		header string
+		status int
+		stored bool
+	}{
+		{"совпадает", true, crc32cHeader(content), http.StatusOK, true},
+		{"не совпадает", true, crc32cHeader([]byte("other")), http.StatusUnprocessableEntity, false},
+		{"некорректный заголовок", true, "not-base64!", http.StatusBadRequest, false},
+		{"без заголовка", true, "", http.StatusOK, true},
+		{"проверка отключена", false, crc32cHeader([]byte("other")), http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				UploadDir:    uploadDir,
+				VerifyCRC32C: tt.verify,
+				Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			req := newUploadRequest(t, "file.bin", content)
+			if tt.header != "" {
+				req.Header.Set(ContentCRC32CHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Ожидался статус %d, получен %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			// Поврежденный файл и временные файлы не остаются в директории
+			files := listFiles(t, uploadDir)
+			if stored := len(files) == 1; stored != tt.stored || len(files) > 1 {
+				t.Errorf("Неверное содержимое директории: %v", files)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashIndexDir директория внутри Dir, где LocalStorageBackend при дедупликации
+// хранит содержимое файлов под именами, равными SHA-256 в hex
+const hashIndexDir = ".hash_index"
+
+// MetadataDuplicate ключ метаданных, который хранилище устанавливает в "true",
+// если содержимое уже было сохранено ранее и файл связан с ним без копирования
+const MetadataDuplicate = "duplicate"
+
+// duplicateResponse ответ на загрузку, содержимое которой уже было сохранено
+type duplicateResponse struct {
+	Duplicate bool   `json:"duplicate"`
+	File      string `json:"file"` // Имя файла в хранилище
+
+	Checksum          string `json:"checksum,omitempty"` // Сумма ComputeHashOnUpload в hex
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+
+	ContentType   string `json:"content_type,omitempty"`   // Тип содержимого при DetectContentType
+	CorrelationID string `json:"correlation_id,omitempty"` // Идентификатор корреляции из запроса
+}
+
+// deduplicate добавляет временный файл в индекс по контрольной сумме sum. Если такое
+// содержимое уже есть, временный файл заменяется жесткой ссылкой на него и возвращается true.
+// Ссылки указывают на один и тот же inode, поэтому повторные загрузки не занимают место
+func (b *LocalStorageBackend) deduplicate(tmpPath, sum string) (bool, error) {
+	b.index.Lock()
+	defer b.index.Unlock()
+
+	indexDir := filepath.Join(b.Dir, hashIndexDir)
+	if err := os.MkdirAll(indexDir, 0755); err != nil {
+		return false, fmt.Errorf("ошибка создания индекса: %w", err)
+	}
+
+	indexPath := filepath.Join(indexDir, sum)
+	err := os.Link(tmpPath, indexPath)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, fs.ErrExist) {
+		return false, fmt.Errorf("ошибка добавления файла в индекс: %w", err)
+	}
+
+	if err := os.Remove(tmpPath); err != nil {
+		return false, fmt.Errorf("ошибка удаления временного файла: %w", err)
+	}
+	if err := os.Link(indexPath, tmpPath); err != nil {
+		return false, fmt.Errorf("ошибка связывания с существующим содержимым: %w", err)
+	}
+	return true, nil
+}
+
+// fileID идентификатор inode файла для учета жестких ссылок
+type fileID struct {
+	dev, ino uint64
+}
+
+// indexEntry возвращает путь записи индекса, с которой связан файл filePath, или "",
+// если файл не связан с индексом. Сумма вычисляется только для файлов с несколькими ссылками
+func (b *LocalStorageBackend) indexEntry(filePath string) string {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return ""
+	}
+	if nlink, _, ok := fileLinks(info); !ok || nlink < 2 {
+		return ""
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return ""
+	}
+
+	indexPath := filepath.Join(b.Dir, hashIndexDir, hex.EncodeToString(hasher.Sum(nil)))
+	indexInfo, err := os.Stat(indexPath)
+	if err != nil || !os.SameFile(info, indexInfo) {
+		return ""
+	}
+	return indexPath
+}
+
+// releaseIndexEntry удаляет запись индекса indexPath, если на ее содержимое больше
+// не ссылается ни один файл. Пустой indexPath пропускается
+func (b *LocalStorageBackend) releaseIndexEntry(indexPath string) error {
+	if indexPath == "" {
+		return nil
+	}
+
+	// Под блокировкой deduplicate не может связать новый файл с удаляемой записью
+	b.index.Lock()
+	defer b.index.Unlock()
+
+	info, err := os.Stat(indexPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка проверки индекса: %w", err)
+	}
+	if nlink, _, ok := fileLinks(info); ok && nlink == 1 {
+		if err := os.Remove(indexPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("ошибка удаления записи индекса: %w", err)
+		}
+	}
+	return nil
+}
+
+// isReservedName проверяет, что имя указывает внутрь индекса дедупликации:
+// запись туда позволила бы подменить содержимое других файлов
+func isReservedName(filename string) bool {
+	first, _, _ := strings.Cut(filename, "/")
+	return first == hashIndexDir
+}
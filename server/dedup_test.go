@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandleUpload_DeduplicateByHash(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:         dir,
+		DeduplicateByHash: true,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	uploadRecorded := func(filename, content string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.handleUpload(rec, newUploadRequest(t, filename, []byte(content)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	if rec := uploadRecorded("a.bin", "same content"); rec.Header().Get("Content-Type") == "application/json" {
+		t.Error("Первая загрузка не должна считаться дубликатом")
+	}
+
+	rec := uploadRecorded("b.bin", "same content")
+	var response duplicateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Ошибка разбора ответа %q: %v", rec.Body.String(), err)
+	}
+	if !response.Duplicate || response.File != "b.bin" {
+		t.Errorf("Неверный ответ для дубликата: %+v", response)
+	}
+
+	uploadRecorded("c.bin", "other content")
+
+	a, _ := os.Stat(filepath.Join(dir, "a.bin"))
+	b, _ := os.Stat(filepath.Join(dir, "b.bin"))
+	c, _ := os.Stat(filepath.Join(dir, "c.bin"))
+	if !os.SameFile(a, b) {
+		t.Error("Одинаковое содержимое должно храниться в одном файле")
+	}
+	if os.SameFile(a, c) {
+		t.Error("Разное содержимое не должно объединяться")
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "b.bin")); string(content) != "same content" {
+		t.Errorf("Неверное содержимое дубликата: %q", content)
+	}
+
+	index, _ := os.ReadDir(filepath.Join(dir, hashIndexDir))
+	if len(index) != 2 {
+		t.Errorf("Ожидалось 2 записи в индексе, получено %d", len(index))
+	}
+
+	// Размер директории учитывает одинаковое содержимое один раз, вместе с индексом
+	if size, _ := dirSize(dir); size != int64(len("same content")+len("other content")) {
+		t.Errorf("Неверный размер директории: %d", size)
+	}
+}
+
+func TestHandleUpload_DeduplicateReservedName(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:         dir,
+		DeduplicateByHash: true,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if code := upload(t, s, "a.bin", []byte("original")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	// Попытка подменить содержимое в индексе через относительный путь
+	sum := sha256.Sum256([]byte("original"))
+	req := newUploadRequest(t, "evil.bin", []byte("forged"))
+	req.Header.Set(RelativePathHeader, hashIndexDir+"/"+hex.EncodeToString(sum[:]))
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code == http.StatusOK {
+		t.Error("Запись в индекс дедупликации должна быть запрещена")
+	}
+
+	if code := upload(t, s, "b.bin", []byte("original")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "b.bin")); string(content) != "original" {
+		t.Errorf("Содержимое подменено: %q", content)
+	}
+}
+
+func TestHashIndex_ReservedWithoutDeduplicate(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:   dir,
+		AllowDelete: true,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Без дедупликации запись в индекс тоже запрещена: она стала бы доверенной после включения
+	req := newUploadRequest(t, "evil.bin", []byte("forged"))
+	req.Header.Set(RelativePathHeader, hashIndexDir+"/evil")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400, получен %d", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, hashIndexDir, "evil")); !os.IsNotExist(err) {
+		t.Error("Файл не должен записываться в индекс")
+	}
+
+	// Записи индекса нельзя удалить или прочитать через /files
+	entry := filepath.Join(dir, hashIndexDir, "entry")
+	os.MkdirAll(filepath.Dir(entry), 0755)
+	os.WriteFile(entry, []byte("data"), 0644)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "/files/"+hashIndexDir+"/entry", nil),
+		httptest.NewRequest(http.MethodGet, "/files/"+hashIndexDir+"/entry/checksum", nil),
+	} {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: ожидался статус 400, получен %d", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+	if err := NewLocalStorageBackend(dir, "").Delete(hashIndexDir + "/entry"); !errors.Is(err, ErrReservedName) {
+		t.Errorf("Ожидалась ошибка ErrReservedName, получено %v", err)
+	}
+	if _, err := os.Stat(entry); err != nil {
+		t.Errorf("Запись индекса удалена: %v", err)
+	}
+}
+
+func TestLocalStorageBackend_ReleaseHashIndex(t *testing.T) {
+	dir := t.TempDir()
+	backend := NewLocalStorageBackend(dir, CollisionOverwrite)
+	backend.Deduplicate = true
+	if _, _, ok := fileLinks(mustStat(t, dir)); !ok {
+		t.Skip("Число жестких ссылок на этой ОС не определяется")
+	}
+
+	save := func(name, content string) {
+		t.Helper()
+		if _, err := backend.Save(context.Background(), name, strings.NewReader(content), map[string]string{}); err != nil {
+			t.Fatalf("Ошибка сохранения %s: %v", name, err)
+		}
+	}
+	indexPath := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return filepath.Join(dir, hashIndexDir, hex.EncodeToString(sum[:]))
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	save("a.bin", "shared")
+	save("b.bin", "shared")
+
+	// Пока на содержимое ссылается другой файл, запись индекса остается
+	if err := backend.Delete("a.bin"); err != nil {
+		t.Fatalf("Ошибка удаления: %v", err)
+	}
+	if !exists(indexPath("shared")) {
+		t.Fatal("Запись индекса удалена, хотя на нее ссылается b.bin")
+	}
+	if err := backend.Delete("b.bin"); err != nil {
+		t.Fatalf("Ошибка удаления: %v", err)
+	}
+	if exists(indexPath("shared")) {
+		t.Error("Запись индекса осталась после удаления последнего файла")
+	}
+
+	// Перезапись последнего файла с содержимым освобождает запись индекса
+	save("c.bin", "old")
+	save("c.bin", "new")
+	if exists(indexPath("old")) {
+		t.Error("Запись индекса перезаписанного содержимого осталась")
+	}
+	if !exists(indexPath("new")) {
+		t.Error("Нет записи индекса нового содержимого")
+	}
+
+	// Квота учитывает индекс, но содержимое со ссылками — один раз
+	size, err := dirSize(dir)
+	if err != nil {
+		t.Fatalf("Ошибка подсчета размера: %v", err)
+	}
+	if size != int64(len("new")) {
+		t.Errorf("Ожидался размер %d, получен %d", len("new"), size)
+	}
+	if err := backend.Delete("c.bin"); err != nil {
+		t.Fatalf("Ошибка удаления: %v", err)
+	}
+	if size, _ := dirSize(dir); size != 0 {
+		t.Errorf("После удаления всех файлов занято %d байт", size)
+	}
+}
+
+// mustStat возвращает информацию о файле или завершает тест
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
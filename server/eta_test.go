@@ -0,0 +1,108 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpeedMeter_Warmup(t *testing.T) {
+	start := time.Now()
+	meter := newSpeedMeter(start, 3*time.Second, defaultEMAAlpha)
+
+	meter.add(start.Add(time.Second), 1000)
+	if _, ok := meter.eta(start.Add(time.Second), 1000, 10000); ok {
+		t.Error("Оценка не должна выдаваться во время прогрева")
+	}
+
+	meter.add(start.Add(3*time.Second), 3000)
+	eta, ok := meter.eta(start.Add(3*time.Second), 3000, 10000)
+	if !ok || eta != 7*time.Second {
+		t.Errorf("Ожидалась оценка 7s, получено %v (%v)", eta, ok)
+	}
+	if _, ok := meter.eta(start.Add(3*time.Second), 10000, 10000); ok {
+		t.Error("Для завершенной передачи оценка не нужна")
+	}
+}
+
+func TestSpeedMeter_RollingWindow(t *testing.T) {
+	start := time.Now()
+	meter := newSpeedMeter(start, 0, defaultEMAAlpha)
+
+	// Медленный старт: 100 байт/с первые 5 секунд, затем 1000 байт/с
+	var bytes int64
+	for i := 1; i <= 10; i++ {
+		if i <= 5 {
+			bytes += 100
+		} else {
+			bytes += 1000
+		}
+		meter.add(start.Add(time.Duration(i)*time.Second), bytes)
+	}
+
+	// Окно содержит только быструю часть передачи
+	if speed := meter.speed(); speed != 1000 {
+		t.Errorf("Ожидалась скорость 1000 байт/с, получено %.1f", speed)
+	}
+	if len(meter.samples) > int(speedWindow/time.Second)+1 {
+		t.Errorf("Устаревшие замеры не отброшены: %d", len(meter.samples))
+	}
+}
+
+func TestETASmoother_Update(t *testing.T) {
+	smoother := ETASmoother{Alpha: 0.5}
+	if got := smoother.Update(1000); got != 1000 {
+		t.Errorf("Первый замер должен приниматься как есть, получено %.1f", got)
+	}
+	if got := smoother.Update(2000); got != 1500 {
+		t.Errorf("Ожидалось 1500, получено %.1f", got)
+	}
+
+	// Некорректный коэффициент заменяется значением по умолчанию; первые 10 замеров
+	// усредняются, дальше вес нового замера 0.1
+	smoother = ETASmoother{Alpha: 2}
+	for i := 0; i < 10; i++ {
+		smoother.Update(1000)
+	}
+	if got := smoother.Update(2000); got != 1100 {
+		t.Errorf("Ожидалось 1100 при коэффициенте по умолчанию, получено %.1f", got)
+	}
+}
+
+func TestSpeedMeter_SmoothedETA(t *testing.T) {
+	start := time.Now()
+	meter := newSpeedMeter(start, 0, defaultEMAAlpha)
+
+	// Скорость колеблется между 500 и 1500 байт/с вокруг 1000 байт/с
+	const total = 100000
+	var bytes int64
+	var prevRaw, prevSmoothed time.Duration
+	var rawJump, smoothedJump time.Duration
+	for i := 1; i <= 30; i++ {
+		if i%2 == 0 {
+			bytes += 1500
+		} else {
+			bytes += 500
+		}
+		now := start.Add(time.Duration(i) * time.Second)
+		meter.add(now, bytes)
+		smoothed, ok := meter.eta(now, bytes, total)
+		if !ok {
+			t.Fatalf("Нет оценки на %d-й секунде", i)
+		}
+		raw := time.Duration(float64(total-bytes) / meter.speed() * float64(time.Second))
+
+		// Скачки оценки после того, как окно скорости заполнилось
+		if i > 10 {
+			rawJump = max(rawJump, (prevRaw - raw).Abs())
+			smoothedJump = max(smoothedJump, (prevSmoothed - smoothed).Abs())
+		}
+		prevRaw, prevSmoothed = raw, smoothed
+	}
+
+	if smoothedJump*3 > rawJump {
+		t.Errorf("Сглаженная оценка скачет на %v, оценка по окну — на %v", smoothedJump, rawJump)
+	}
+	if want := time.Duration(total-bytes) * time.Second / 1000; (prevSmoothed - want).Abs() > want/10 {
+		t.Errorf("Ожидалась оценка около %v, получено %v", want, prevSmoothed)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import "sync"
+
+// fileLocks блокировки имен файлов в хранилище: загрузки одного имени выполняются
+// по очереди, поэтому проверка коллизий, версионирование и запись не пересекаются.
+// Блокировка удаляется, когда ее не ждет ни одна загрузка, поэтому реестр
+// не растет с числом различных имен
+type fileLocks struct {
+	mu    sync.Mutex
+	locks map[string]*fileLock
+}
+
+// fileLock блокировка одного имени и число загрузок, удерживающих или ждущих ее
+type fileLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock захватывает блокировку имени name и возвращает функцию ее освобождения
+func (l *fileLocks) lock(name string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*fileLock)
+	}
+	entry, ok := l.locks[name]
+	if !ok {
+		entry = &fileLock{}
+		l.locks[name] = entry
+	}
+	entry.refs++
+	l.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(l.locks, name)
+		}
+	}
+}
+
+// active возвращает число имен с активными блокировками
+func (l *fileLocks) active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.locks)
+}
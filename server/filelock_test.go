@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHandleUpload_ConcurrentSameFilename(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir: dir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Каждая загрузка заполняет файл своим байтом: смешанная запись была бы заметна
+	contents := make([][]byte, 10)
+	for i := range contents {
+		contents[i] = bytes.Repeat([]byte{byte('a' + i)}, 256*1024)
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, len(contents))
+	for i, content := range contents {
+		wg.Add(1)
+		go func(i int, content []byte) {
+			defer wg.Done()
+			codes[i] = upload(t, s, "same.bin", content)
+		}(i, content)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Загрузка %d: ожидался статус 200, получен %d", i, code)
+		}
+	}
+
+	saved, err := os.ReadFile(filepath.Join(dir, "same.bin"))
+	if err != nil {
+		t.Fatalf("Ошибка чтения файла: %v", err)
+	}
+	matched := false
+	for _, content := range contents {
+		if bytes.Equal(saved, content) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		t.Errorf("Файл не совпадает ни с одной загрузкой: %d байт, начинается с %q", len(saved), saved[:min(len(saved), 8)])
+	}
+	if active := s.fileLocks.active(); active != 0 {
+		t.Errorf("После загрузок осталось блокировок: %d", active)
+	}
+}
+
+func TestFileLocks_Serializes(t *testing.T) {
+	var locks fileLocks
+	unlock := locks.lock("a.bin")
+
+	// Другое имя не ждет
+	locks.lock("b.bin")()
+
+	acquired := make(chan struct{})
+	go func() {
+		locks.lock("a.bin")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Блокировка того же имени захвачена дважды")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Блокировка не освобождена")
+	}
+	if active := locks.active(); active != 0 {
+		t.Errorf("Осталось блокировок: %d", active)
+	}
+}
@@ -0,0 +1,319 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры постраничного вывода списка файлов
+const (
+	defaultFilesPerPage = 50
+	maxFilesPerPage     = 1000
+)
+
+// FileInfo сведения о сохраненном файле
+type FileInfo struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Checksum string    `json:"checksum"` // SHA-256 в hex
+}
+
+// FileList страница списка файлов
+type FileList struct {
+	Files   []FileInfo `json:"files"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+	PerPage int        `json:"per_page"`
+}
+
+// FileLister хранилище, поддерживающее получение списка файлов.
+// Контрольная сумма заполняется только для файлов запрошенной страницы
+type FileLister interface {
+	// List возвращает сведения о файлах без контрольных сумм
+	List() ([]FileInfo, error)
+
+	// Checksum вычисляет SHA-256 файла в hex
+	Checksum(filename string) (string, error)
+}
+
+// List реализует FileLister. Возвращаются файлы всех поддиректорий с именами
+// относительно Dir через "/"; индекс дедупликации, временные файлы незавершенных
+// загрузок, версии и файлы метаданных пропускаются
+func (b *LocalStorageBackend) List() ([]FileInfo, error) {
+	var files []FileInfo
+	err := filepath.WalkDir(b.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Директория или файл удалены во время обхода
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != b.Dir && d.Name() == hashIndexDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".tmp.") {
+			return nil
+		}
+		if b.Versioning && isVersionName(d.Name()) {
+			// Версии доступны через /files/{filename}/versions
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), metadataSuffix) {
+			// Метаданные доступны через /files/{filename}/meta
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Файл мог быть удален после чтения директории
+			return nil
+		}
+		rel, err := filepath.Rel(b.Dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения директории: %w", err)
+	}
+	return files, nil
+}
+
+// Checksum реализует FileLister
+func (b *LocalStorageBackend) Checksum(filename string) (string, error) {
+	file, err := os.Open(b.path(filename))
+	if err != nil {
+		return "", fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// handleFiles отдает список файлов с постраничным выводом:
+// GET /files?page=1&per_page=50, файлы отсортированы по времени изменения (новые первыми)
+func (s *HTTPServer) handleFiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	lister, ok := s.storage.(FileLister)
+	if !ok {
+		s.requestError(w, r, "Хранилище не поддерживает получение списка файлов", http.StatusNotImplemented)
+		return
+	}
+
+	page, err := queryInt(r, "page", 1)
+	if err != nil || page < 1 {
+		s.requestError(w, r, "Некорректный параметр page", http.StatusBadRequest)
+		return
+	}
+	perPage, err := queryInt(r, "per_page", defaultFilesPerPage)
+	if err != nil || perPage < 1 || perPage > maxFilesPerPage {
+		s.requestError(w, r, fmt.Sprintf("Параметр per_page должен быть от 1 до %d", maxFilesPerPage), http.StatusBadRequest)
+		return
+	}
+
+	files, err := lister.List()
+	if err != nil {
+		s.requestError(w, r, fmt.Sprintf("Ошибка получения списка файлов: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].ModTime.Equal(files[j].ModTime) {
+			return files[i].ModTime.After(files[j].ModTime)
+		}
+		return files[i].Name < files[j].Name
+	})
+
+	result := FileList{Files: []FileInfo{}, Total: len(files), Page: page, PerPage: perPage}
+	start := (page - 1) * perPage
+	if start < len(files) {
+		end := start + perPage
+		if end > len(files) {
+			end = len(files)
+		}
+		result.Files = files[start:end]
+	}
+
+	for i := range result.Files {
+		checksum, err := lister.Checksum(result.Files[i].Name)
+		if err != nil {
+			s.requestError(w, r, fmt.Sprintf("Ошибка вычисления контрольной суммы: %v", err), http.StatusInternalServerError)
+			return
+		}
+		result.Files[i].Checksum = checksum
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// queryInt возвращает целочисленный параметр запроса или значение по умолчанию
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// FileDeleter хранилище, поддерживающее удаление файлов
+type FileDeleter interface {
+	// Delete удаляет файл; если файла нет, возвращает ошибку, для которой
+	// errors.Is(err, fs.ErrNotExist) истинно
+	Delete(filename string) error
+}
+
+// Delete реализует FileDeleter
+func (b *LocalStorageBackend) Delete(filename string) error {
+	if isReservedName(filename) {
+		return fmt.Errorf("%w: %s", ErrReservedName, filename)
+	}
+	info, err := os.Stat(b.path(filename))
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s не является файлом: %w", filename, fs.ErrNotExist)
+	}
+	indexPath := b.indexEntry(b.path(filename))
+	if err := os.Remove(b.path(filename)); err != nil {
+		return err
+	}
+	if err := b.releaseIndexEntry(indexPath); err != nil {
+		return err
+	}
+	// Метаданные удаляются вместе с файлом
+	if err := os.Remove(b.path(filename) + metadataSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("ошибка удаления метаданных: %w", err)
+	}
+	return nil
+}
+
+// Delete реализует FileDeleter
+func (b *MemoryStorageBackend) Delete(filename string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.files[filename]; !ok {
+		return fmt.Errorf("файл %s не найден: %w", filename, fs.ErrNotExist)
+	}
+	delete(b.files, filename)
+	delete(b.metadata, filename)
+	return nil
+}
+
+// handleFile обрабатывает запросы к отдельному файлу: DELETE /files/{filename},
+// GET /files/{filename}/meta, GET /files/{filename}/checksum
+// и GET /files/{filename}/versions[/{timestamp}], а при ServeUploads с путем по умолчанию
+// и GET /files/{filename}. {filename} — путь файла в хранилище, в том числе во вложенных
+// директориях UploadPathTemplate и AllowClientPath; он очищается так же, как X-Relative-Path
+func (s *HTTPServer) handleFile(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/files/")
+	if base, ok := strings.CutSuffix(name, "/meta"); ok {
+		if filename, ok := s.storageFileName(w, r, base); ok {
+			s.handleFileMetadata(w, r, filename)
+		}
+		return
+	}
+	if base, ok := strings.CutSuffix(name, "/checksum"); ok {
+		if filename, ok := s.storageFileName(w, r, base); ok {
+			s.handleFileChecksum(w, r, filename)
+		}
+		return
+	}
+	if base, ok := strings.CutSuffix(name, "/versions"); ok {
+		if filename, ok := s.storageFileName(w, r, base); ok {
+			s.handleVersions(w, r, filename, "")
+		}
+		return
+	}
+	if i := strings.LastIndex(name, "/versions/"); i >= 0 && !strings.Contains(name[i+len("/versions/"):], "/") {
+		if filename, ok := s.storageFileName(w, r, name[:i]); ok {
+			s.handleVersions(w, r, filename, name[i+len("/versions/"):])
+		}
+		return
+	}
+	if s.static != nil && s.serveUploadsPrefix() == DefaultServeUploadsPrefix && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		s.static.ServeHTTP(w, r)
+		return
+	}
+	filename, ok := s.storageFileName(w, r, name)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.config.AllowDelete {
+		s.requestError(w, r, "Удаление файлов запрещено", http.StatusForbidden)
+		return
+	}
+
+	deleter, ok := s.storage.(FileDeleter)
+	if !ok {
+		s.requestError(w, r, "Хранилище не поддерживает удаление файлов", http.StatusNotImplemented)
+		return
+	}
+
+	if err := deleter.Delete(filename); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.requestError(w, r, fmt.Sprintf("Файл %s не найден", filename), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrReservedName) {
+			s.requestError(w, r, fmt.Sprintf("Некорректное имя файла: %v", err), http.StatusBadRequest)
+			return
+		}
+		s.requestError(w, r, fmt.Sprintf("Ошибка удаления файла: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	s.checksums.invalidate(filename)
+	s.idempotency.forget(filename)
+	s.logger().Info("Файл удален", "file", filename, "remote_addr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// storageFileName возвращает имя файла в хранилище для пути из URL: путь очищается
+// так же, как X-Relative-Path при загрузке, а компоненты разделяются "/". Для пути,
+// выходящего за пределы хранилища или в индекс дедупликации, отвечает 400 и возвращает false
+func (s *HTTPServer) storageFileName(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	cleaned, err := cleanRelativePath(name)
+	if err != nil {
+		s.requestError(w, r, fmt.Sprintf("Некорректное имя файла: %v", err), http.StatusBadRequest)
+		return "", false
+	}
+	filename := filepath.ToSlash(cleaned)
+	if isReservedName(filename) {
+		s.requestError(w, r, fmt.Sprintf("Некорректное имя файла: %v", ErrReservedName), http.StatusBadRequest)
+		return "", false
+	}
+	return filename, true
+}
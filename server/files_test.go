@@ -0,0 +1,191 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHandleFiles_Pagination(t *testing.T) {
+	uploadDir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir})
+
+	// Файлы с разным временем изменения: c.bin самый новый
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		if code := upload(t, s, name, []byte(name)); code != http.StatusOK {
+			t.Fatalf("Ошибка загрузки %s: %d", name, code)
+		}
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(filepath.Join(uploadDir, name), modTime, modTime)
+	}
+	// Временные файлы и поддиректории не попадают в список
+	os.WriteFile(filepath.Join(uploadDir, ".tmp.123.d.bin"), []byte("partial"), 0644)
+	os.Mkdir(filepath.Join(uploadDir, "subdir"), 0755)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"c.bin", "b.bin", "a.bin"}},
+		{"?page=1&per_page=2", []string{"c.bin", "b.bin"}},
+		{"?page=2&per_page=2", []string{"a.bin"}},
+		{"?page=3&per_page=2", []string{}},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/files"+test.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Для %q ожидался статус 200, получен %d", test.query, rec.Code)
+		}
+
+		var list FileList
+		if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+			t.Fatalf("Ошибка разбора ответа: %v", err)
+		}
+		if list.Total != 3 {
+			t.Errorf("Для %q ожидалось total=3, получено %d", test.query, list.Total)
+		}
+		if len(list.Files) != len(test.expected) {
+			t.Fatalf("Для %q ожидалось %v, получено %v", test.query, test.expected, list.Files)
+		}
+		for i, file := range list.Files {
+			sum := sha256.Sum256([]byte(file.Name))
+			if file.Name != test.expected[i] || file.Checksum != hex.EncodeToString(sum[:]) || file.Size != 5 {
+				t.Errorf("Для %q неверный файл %d: %+v", test.query, i, file)
+			}
+		}
+	}
+}
+
+func TestHandleFiles_Nested(t *testing.T) {
+	uploadDir := t.TempDir()
+	backend := NewLocalStorageBackend(uploadDir, CollisionOverwrite)
+	backend.Deduplicate = true
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: backend,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := newUploadRequest(t, "nested.bin", []byte("nested"))
+	req.Header.Set(RelativePathHeader, "dir/sub/nested.bin")
+	req.Header.Set("X-Meta-Author", "alice")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	upload(t, s, "top.bin", []byte("top"))
+	os.WriteFile(filepath.Join(uploadDir, "dir", ".tmp.123.d.bin"), []byte("partial"), 0644)
+
+	// Индекс дедупликации, временные файлы и метаданные не попадают в список
+	var list FileList
+	if err := json.NewDecoder(getFile(t, s, "/files").Body).Decode(&list); err != nil {
+		t.Fatalf("Ошибка разбора ответа: %v", err)
+	}
+	names := make(map[string]string)
+	for _, file := range list.Files {
+		names[file.Name] = file.Checksum
+	}
+	sum := sha256.Sum256([]byte("nested"))
+	if list.Total != 2 || names["dir/sub/nested.bin"] != hex.EncodeToString(sum[:]) || names["top.bin"] == "" {
+		t.Errorf("Ожидались dir/sub/nested.bin и top.bin, получено %+v", list.Files)
+	}
+}
+
+func TestHandleFiles_Errors(t *testing.T) {
+	tests := []struct {
+		server *HTTPServer
+		target string
+		status int
+	}{
+		{NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()}), "/files?page=0", http.StatusBadRequest},
+		{NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()}), "/files?per_page=abc", http.StatusBadRequest},
+		{NewHTTPServerWithConfig(&ServerConfig{Backend: NewMemoryStorageBackend()}), "/files", http.StatusNotImplemented},
+		{NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), AuthToken: "secret"}), "/files", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		test.server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+		if rec.Code != test.status {
+			t.Errorf("Для %s ожидался статус %d, получен %d", test.target, test.status, rec.Code)
+		}
+	}
+}
+
+func TestHandleFile_DeleteTraversal(t *testing.T) {
+	root := t.TempDir()
+	uploadDir := filepath.Join(root, "uploads")
+	outside := filepath.Join(root, "secret.bin")
+	os.WriteFile(outside, []byte("secret"), 0644)
+
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, AllowDelete: true})
+	upload(t, s, "secret.bin", []byte("uploaded"))
+
+	// Путь с выходом вверх отклоняется, как X-Relative-Path при загрузке.
+	// Обработчик вызывается напрямую, так как ServeMux перенаправляет такие пути
+	req := httptest.NewRequest(http.MethodDelete, "/files/x", nil)
+	req.URL.Path = "/files/../secret.bin"
+	rec := httptest.NewRecorder()
+	s.handleFile(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Ожидался статус 400, получен %d", rec.Code)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("Удален файл вне директории загрузки: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(uploadDir, "secret.bin")); err != nil {
+		t.Errorf("Удален файл в директории загрузки: %v", err)
+	}
+}
+
+func TestHandleFile_NestedPath(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:       dir,
+		AllowDelete:     true,
+		AllowClientPath: true,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	handler := s.Handler()
+
+	req := newUploadRequest(t, "report.bin", []byte("report"))
+	req.Header.Set(DefaultPathHeaderName, "tenants/acme")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ошибка загрузки: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/files/tenants/acme/report.bin/checksum", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Контрольная сумма вложенного файла: ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/files/tenants/acme/report.bin", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Удаление вложенного файла: ожидался статус 204, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tenants", "acme", "report.bin")); !os.IsNotExist(err) {
+		t.Errorf("Вложенный файл не удален: %v", err)
+	}
+
+	// Файл с тем же именем на верхнем уровне не затрагивается
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/files/report.bin", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404 для файла верхнего уровня, получен %d", rec.Code)
+	}
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader заголовок с ключом идемпотентности запроса на загрузку
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader заголовок ответа, повторенного из кэша по ключу идемпотентности
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+// defaultIdempotencyCacheSize число ключей идемпотентности, хранимых по умолчанию
+const defaultIdempotencyCacheSize = 1000
+
+// errIdempotencyInProgress возвращается, если запрос с тем же ключом еще обрабатывается
+var errIdempotencyInProgress = errors.New("запрос с этим ключом идемпотентности еще обрабатывается")
+
+// cachedResponse ответ на запрос с ключом идемпотентности
+type cachedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// idempotencyEntry элемент кэша; response равен nil, пока запрос обрабатывается.
+// name — имя файла в хранилище, которому соответствует ответ
+type idempotencyEntry struct {
+	key      string
+	name     string
+	response *cachedResponse
+	expires  time.Time
+}
+
+// idempotencyCache LRU-кэш ответов по ключу идемпотентности. При превышении
+// size вытесняется ключ, к которому дольше всего не обращались
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	order   *list.List // Элементы *idempotencyEntry, недавно использованные в начале
+	entries map[string]*list.Element
+}
+
+// newIdempotencyCache создает кэш; при ttl <= 0 возвращает nil
+func newIdempotencyCache(ttl time.Duration, size int) *idempotencyCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = defaultIdempotencyCacheSize
+	}
+	return &idempotencyCache{ttl: ttl, size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// begin возвращает сохраненный ответ для ключа. Если ответа нет, ключ помечается
+// как обрабатываемый и вызывающий должен завершить его через finish
+func (c *idempotencyCache) begin(key string, now time.Time) (*cachedResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*idempotencyEntry)
+		switch {
+		case entry.response == nil:
+			return nil, errIdempotencyInProgress
+		case now.Before(entry.expires):
+			c.order.MoveToFront(elem)
+			return entry.response, nil
+		}
+		c.remove(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&idempotencyEntry{key: key})
+	for c.order.Len() > c.size {
+		// Обрабатываемые запросы не вытесняются: их ответ еще не получен
+		oldest := c.order.Back()
+		if oldest.Value.(*idempotencyEntry).response == nil {
+			break
+		}
+		c.remove(oldest)
+	}
+	return nil, nil
+}
+
+// finish сохраняет ответ для ключа, загрузившего файл name; при response == nil
+// ключ удаляется, и повторный запрос будет обработан заново
+func (c *idempotencyCache) finish(key, name string, response *cachedResponse, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if response == nil {
+		c.remove(elem)
+		return
+	}
+	entry := elem.Value.(*idempotencyEntry)
+	entry.name = name
+	entry.response = response
+	entry.expires = now.Add(c.ttl)
+}
+
+// forget удаляет сохраненные ответы на загрузку файла name: после удаления файла
+// повторный запрос с тем же ключом должен сохранить его заново
+func (c *idempotencyCache) forget(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if entry := elem.Value.(*idempotencyEntry); entry.response != nil && entry.name == name {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *idempotencyCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*idempotencyEntry).key)
+}
+
+// idempotencyNameKey ключ контекста запроса с именем файла в хранилище, которое
+// сообщает обработчик загрузки
+type idempotencyNameKey struct{}
+
+// recordIdempotentName сохраняет имя файла в хранилище для кэша идемпотентности.
+// Без ключа идемпотентности в запросе ничего не делает
+func recordIdempotentName(r *http.Request, name string) {
+	if target, ok := r.Context().Value(idempotencyNameKey{}).(*string); ok {
+		*target = name
+	}
+}
+
+// recordingResponseWriter запоминает статус, заголовки и тело ответа для кэша идемпотентности
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body = append(w.body, p...)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap позволяет http.ResponseController управлять исходным соединением
+func (w *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// withIdempotency выполняет handler не более одного раза для ключа из заголовка
+// Idempotency-Key в течение IdempotencyTTL. Повторный запрос получает сохраненный
+// ответ, а пока первый запрос обрабатывается — 429 с Retry-After, чтобы клиент
+// повторил его позже. Сохраняются только успешные ответы: после ошибки клиент может
+// повторить загрузку с тем же ключом. Удаление файла сбрасывает ответы на его загрузку
+func (s *HTTPServer) withIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if s.idempotency == nil || key == "" {
+			next(w, r)
+			return
+		}
+
+		cached, err := s.idempotency.begin(key, time.Now())
+		if err != nil {
+			w.Header().Set("Retry-After", "1")
+			s.httpError(w, r, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		if cached != nil {
+			s.logger().Info("Повтор запроса по ключу идемпотентности", "key", key, "remote_addr", r.RemoteAddr)
+			for name, values := range cached.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayHeader, "true")
+			w.WriteHeader(cached.status)
+			w.Write(cached.body)
+			return
+		}
+
+		recorder := &recordingResponseWriter{ResponseWriter: w}
+		var name string
+		var response *cachedResponse
+		defer func() { s.idempotency.finish(key, name, response, time.Now()) }()
+
+		next(recorder, r.WithContext(context.WithValue(r.Context(), idempotencyNameKey{}, &name)))
+		if recorder.status >= 200 && recorder.status < 300 {
+			response = &cachedResponse{status: recorder.status, header: recorder.header, body: recorder.body}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// idempotentUpload отправляет файл через Handler с заголовком Idempotency-Key
+func idempotentUpload(t *testing.T, s *HTTPServer, key, filename string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := newUploadRequest(t, filename, content)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:          backend,
+		IdempotencyTTL:   time.Minute,
+		MaxFileSizeBytes: 8,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Ошибка не сохраняется: повтор с тем же ключом обрабатывается заново
+	if rec := idempotentUpload(t, s, "key-1", "file.bin", make([]byte, 1024)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Ожидался статус 413, получен %d", rec.Code)
+	}
+
+	first := idempotentUpload(t, s, "key-1", "file.bin", []byte("first"))
+	if first.Code != http.StatusOK || first.Header().Get(IdempotentReplayHeader) != "" {
+		t.Fatalf("Первый успешный запрос должен обрабатываться: %d %v", first.Code, first.Header())
+	}
+
+	second := idempotentUpload(t, s, "key-1", "file.bin", []byte("second"))
+	if second.Code != http.StatusOK || second.Header().Get(IdempotentReplayHeader) != "true" {
+		t.Errorf("Ожидался повтор сохраненного ответа: %d %v", second.Code, second.Header())
+	}
+	if second.Body.String() != first.Body.String() || second.Header().Get(SessionIDHeader) != first.Header().Get(SessionIDHeader) {
+		t.Errorf("Повторный ответ отличается: %q, %q", second.Body.String(), first.Body.String())
+	}
+	if data, _ := backend.Contents("file.bin"); string(data) != "first" {
+		t.Errorf("Повторный запрос не должен перезаписывать файл: %q", data)
+	}
+
+	// Другой ключ обрабатывается как новый запрос
+	if rec := idempotentUpload(t, s, "key-2", "file.bin", []byte("second")); rec.Header().Get(IdempotentReplayHeader) != "" {
+		t.Error("Запрос с новым ключом не должен повторять ответ")
+	}
+	if data, _ := backend.Contents("file.bin"); string(data) != "second" {
+		t.Errorf("Ожидалось новое содержимое, получено %q", data)
+	}
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	backend := &blockingBackend{
+		MemoryStorageBackend: NewMemoryStorageBackend(),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:        backend,
+		IdempotencyTTL: time.Minute,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	done := make(chan int)
+	go func() { done <- idempotentUpload(t, s, "key", "slow.bin", []byte("data")).Code }()
+	<-backend.started
+
+	rec := idempotentUpload(t, s, "key", "slow.bin", []byte("data"))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Ожидался статус 429 с Retry-After, получен %d %v", rec.Code, rec.Header())
+	}
+	close(backend.release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("Ожидался статус 200, получен %d", code)
+	}
+}
+
+func TestIdempotency_ForgetsDeletedFile(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:        backend,
+		IdempotencyTTL: time.Minute,
+		AllowDelete:    true,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	if rec := idempotentUpload(t, s, "key", "file.bin", []byte("data")); rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/files/file.bin", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Ожидался статус 204, получен %d", rec.Code)
+	}
+
+	// После удаления файла тот же ключ обрабатывается заново и сохраняет файл
+	rec = idempotentUpload(t, s, "key", "file.bin", []byte("data"))
+	if rec.Code != http.StatusOK || rec.Header().Get(IdempotentReplayHeader) != "" {
+		t.Fatalf("Ожидалась новая загрузка: %d %v", rec.Code, rec.Header())
+	}
+	if data, ok := backend.Contents("file.bin"); !ok || string(data) != "data" {
+		t.Errorf("Файл не сохранен заново: %q", data)
+	}
+}
+
+func TestIdempotencyCache_EvictsAndExpires(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, 2)
+	now := time.Now()
+	response := &cachedResponse{status: http.StatusOK}
+
+	for _, key := range []string{"a", "b"} {
+		cache.begin(key, now)
+		cache.finish(key, key, response, now)
+	}
+	// Обращение к a делает вытесняемым b
+	if cached, _ := cache.begin("a", now); cached == nil {
+		t.Fatal("Ответ для a не найден")
+	}
+	cache.begin("c", now)
+	cache.finish("c", "c", response, now)
+
+	if cached, _ := cache.begin("b", now); cached != nil {
+		t.Error("Давно не использованный ключ b должен быть вытеснен")
+	}
+	if cached, _ := cache.begin("a", now.Add(2*time.Minute)); cached != nil {
+		t.Error("Ответ с истекшим TTL не должен возвращаться")
+	}
+	if newIdempotencyCache(0, 10) != nil {
+		t.Error("При нулевом TTL кэш не создается")
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ipList список адресов и подсетей для фильтрации клиентов
+type ipList []*net.IPNet
+
+// parseIPList разбирает адреса (192.0.2.1, 2001:db8::1) и подсети в нотации CIDR (10.0.0.0/8)
+func parseIPList(entries []string) (ipList, error) {
+	list := make(ipList, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("некорректная подсеть %q: %w", entry, err)
+			}
+			list = append(list, ipNet)
+			continue
+		}
+
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("некорректный IP-адрес %q", entry)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		list = append(list, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return list, nil
+}
+
+// contains проверяет, входит ли адрес в один из элементов списка
+func (l ipList) contains(ip net.IP) bool {
+	for _, ipNet := range l {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP возвращает адрес клиента. При TrustProxy адрес берется из X-Forwarded-For
+// справа налево: каждый прокси дописывает в конец адрес, с которого к нему пришел запрос,
+// а левые значения задает сам клиент и им верить нельзя. Адреса из TrustedProxies
+// (промежуточные прокси) пропускаются; без них используется последний адрес
+func (s *HTTPServer) clientIP(r *http.Request) net.IP {
+	if s.config.TrustProxy {
+		if ip := s.forwardedIP(r.Header.Values("X-Forwarded-For")); ip != nil {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// forwardedIP возвращает первый справа адрес из значений X-Forwarded-For, не входящий
+// в TrustedProxies, или nil, если такого нет или он некорректен
+func (s *HTTPServer) forwardedIP(values []string) net.IP {
+	trusted, err := parseIPList(s.config.TrustedProxies)
+	if err != nil {
+		trusted = nil
+	}
+
+	entries := strings.Split(strings.Join(values, ","), ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := strings.TrimSpace(entries[i])
+		if entry == "" {
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil || !trusted.contains(ip) {
+			return ip
+		}
+	}
+	return nil
+}
+
+// ipFilterMiddleware отклоняет запросы с адресов из BlockedIPs и, если задан
+// AllowedIPs, с адресов вне него. Блокировка имеет приоритет над разрешением.
+// Некорректные списки отклоняются в Start; при использовании Handler напрямую
+// такой фильтр отклоняет все запросы, чтобы ошибка конфигурации не открыла доступ
+func (s *HTTPServer) ipFilterMiddleware(next http.Handler) http.Handler {
+	if len(s.config.AllowedIPs) == 0 && len(s.config.BlockedIPs) == 0 {
+		return next
+	}
+
+	allowed, allowErr := parseIPList(s.config.AllowedIPs)
+	blocked, blockErr := parseIPList(s.config.BlockedIPs)
+	if allowErr != nil || blockErr != nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.httpError(w, r, "Некорректная конфигурация фильтра IP-адресов", http.StatusForbidden)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := s.clientIP(r)
+		if ip == nil || blocked.contains(ip) || (len(allowed) > 0 && !allowed.contains(ip)) {
+			s.httpError(w, r, "Доступ с этого адреса запрещен", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
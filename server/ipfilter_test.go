@@ -0,0 +1,101 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilterMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    []string
+		blocked    []string
+		trustProxy bool
+		remoteAddr string
+		forwarded  string
+		wantStatus int
+	}{
+		{"без списков", nil, nil, false, "203.0.113.5:1234", "", http.StatusOK},
+		{"адрес в allowlist", []string{"203.0.113.5"}, nil, false, "203.0.113.5:1234", "", http.StatusOK},
+		{"подсеть в allowlist", []string{"10.0.0.0/8"}, nil, false, "10.1.2.3:1234", "", http.StatusOK},
+		{"адрес вне allowlist", []string{"10.0.0.0/8"}, nil, false, "192.0.2.1:1234", "", http.StatusForbidden},
+		{"адрес в blocklist", nil, []string{"192.0.2.0/24"}, false, "192.0.2.1:1234", "", http.StatusForbidden},
+		{"адрес вне blocklist", nil, []string{"192.0.2.0/24"}, false, "198.51.100.1:1234", "", http.StatusOK},
+		{"блокировка важнее разрешения", []string{"10.0.0.0/8"}, []string{"10.0.0.1"}, false, "10.0.0.1:1234", "", http.StatusForbidden},
+		{"IPv6", []string{"2001:db8::/32"}, nil, false, "[2001:db8::1]:1234", "", http.StatusOK},
+		{"X-Forwarded-For без TrustProxy", []string{"10.0.0.0/8"}, nil, false, "192.0.2.1:1234", "10.0.0.1", http.StatusForbidden},
+		{"X-Forwarded-For с TrustProxy", []string{"10.0.0.0/8"}, nil, true, "192.0.2.1:1234", "10.0.0.1", http.StatusOK},
+		{"поддельный X-Forwarded-For через прокси", []string{"10.0.0.0/8"}, nil, true, "192.0.2.1:1234", "10.0.0.1, 203.0.113.5", http.StatusForbidden},
+		{"поддельный адрес не обходит blocklist", nil, []string{"203.0.113.5"}, true, "192.0.2.1:1234", "10.0.0.1, 203.0.113.5", http.StatusForbidden},
+		{"заблокированный клиент за прокси", nil, []string{"10.0.0.1"}, true, "192.0.2.1:1234", "10.0.0.1", http.StatusForbidden},
+		{"некорректный список", []string{"not-an-ip"}, nil, false, "10.0.0.1:1234", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				Backend:    NewMemoryStorageBackend(),
+				AllowedIPs: tt.allowed,
+				BlockedIPs: tt.blocked,
+				TrustProxy: tt.trustProxy,
+				Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Ожидался статус %d, получен %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestServerConfigValidate_IPLists(t *testing.T) {
+	config := &ServerConfig{AllowedIPs: []string{"10.0.0.0/33"}}
+	if err := config.validate(); err == nil {
+		t.Error("Ожидалась ошибка для некорректной подсети")
+	}
+
+	config = &ServerConfig{AllowedIPs: []string{"10.0.0.0/8", "192.0.2.1"}, BlockedIPs: []string{"::1"}}
+	if err := config.validate(); err != nil {
+		t.Errorf("Неожиданная ошибка: %v", err)
+	}
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name      string
+		trusted   []string
+		forwarded []string
+		want      string
+	}{
+		{"последнее значение", nil, []string{"10.0.0.1, 203.0.113.5"}, "203.0.113.5"},
+		{"промежуточные прокси пропускаются", []string{"172.16.0.0/12"}, []string{"10.0.0.1, 203.0.113.5, 172.16.0.2"}, "203.0.113.5"},
+		{"несколько заголовков", []string{"172.16.0.0/12"}, []string{"10.0.0.1", "203.0.113.5, 172.16.0.2"}, "203.0.113.5"},
+		{"некорректное значение", nil, []string{"10.0.0.1, unknown"}, "192.0.2.1"},
+		{"все адреса — прокси", []string{"172.16.0.0/12"}, []string{"172.16.0.3, 172.16.0.2"}, "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHTTPServerWithConfig(&ServerConfig{Backend: NewMemoryStorageBackend(), TrustProxy: true, TrustedProxies: tt.trusted})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := s.clientIP(req); got.String() != tt.want {
+				t.Errorf("Ожидался адрес %s, получен %s", tt.want, got)
+			}
+		})
+	}
+}
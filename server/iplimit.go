@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// acquireUploadSlot учитывает загрузку с адреса клиента (с учетом TrustProxy) и возвращает
+// функцию освобождения слота. Если с адреса уже идут MaxConcurrentUploadsPerIP загрузок,
+// возвращает false. Счетчики адресов не удаляются: удаление обнулившегося счетчика
+// гонкой с параллельным запросом позволило бы превысить лимит
+func (s *HTTPServer) acquireUploadSlot(r *http.Request) (func(), bool) {
+	limit := s.config.MaxConcurrentUploadsPerIP
+	if limit <= 0 {
+		return func() {}, true
+	}
+
+	key := r.RemoteAddr
+	if ip := s.clientIP(r); ip != nil {
+		key = ip.String()
+	}
+	value, _ := s.ipUploads.LoadOrStore(key, &atomic.Int32{})
+	active := value.(*atomic.Int32)
+	if active.Add(1) > int32(limit) {
+		active.Add(-1)
+		return nil, false
+	}
+	return func() { active.Add(-1) }, true
+}
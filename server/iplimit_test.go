@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHandleUpload_MaxConcurrentUploadsPerIP(t *testing.T) {
+	const limit, requests = 2, 5
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:                 t.TempDir(),
+		MaxConcurrentUploadsPerIP: limit,
+		Logger:                    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Тела запросов передаются через pipe: принятые загрузки ждут данных и удерживают слоты
+	type result struct {
+		index int
+		rec   *httptest.ResponseRecorder
+	}
+	results := make(chan result, requests)
+	writers := make([]*io.PipeWriter, requests)
+	forms := make([]*multipart.Writer, requests)
+	for i := 0; i < requests; i++ {
+		pr, pw := io.Pipe()
+		writers[i] = pw
+		forms[i] = multipart.NewWriter(pw)
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", pr)
+		req.Header.Set("Content-Type", forms[i].FormDataContentType())
+		req.RemoteAddr = "192.0.2.10:" + strconv.Itoa(40000+i)
+		go func(i int) {
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, req)
+			results <- result{i, rec}
+		}(i)
+	}
+
+	// Запросы сверх лимита отклоняются сразу, не читая тело
+	pending := make(map[int]bool)
+	for i := 0; i < requests; i++ {
+		pending[i] = true
+	}
+	for i := 0; i < requests-limit; i++ {
+		select {
+		case res := <-results:
+			if res.rec.Code != http.StatusTooManyRequests || res.rec.Header().Get("Retry-After") == "" {
+				t.Fatalf("Ожидался статус 429 с Retry-After, получен %d: %s", res.rec.Code, res.rec.Body.String())
+			}
+			delete(pending, res.index)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Запросы сверх лимита не отклонены")
+		}
+	}
+
+	// Запросы в пределах лимита завершаются успешно после передачи файла
+	for i := range pending {
+		go func(i int) {
+			part, _ := forms[i].CreateFormFile("file", "file"+strconv.Itoa(i)+".bin")
+			part.Write(bytes.Repeat([]byte("x"), 1024))
+			forms[i].Close()
+			writers[i].Close()
+		}(i)
+	}
+	for range pending {
+		res := <-results
+		if res.rec.Code != http.StatusOK {
+			t.Errorf("Ожидался статус 200, получен %d: %s", res.rec.Code, res.rec.Body.String())
+		}
+	}
+
+	// Слоты освобождены: следующая загрузка с того же адреса принимается
+	req := newUploadRequest(t, "after.bin", []byte("after"))
+	req.RemoteAddr = "192.0.2.10:50000"
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Ожидался статус 200 после освобождения слотов, получен %d", rec.Code)
+	}
+
+	// Другой адрес не ограничивается загрузками первого
+	if code := upload(t, s, "other.bin", []byte("other")); code != http.StatusOK {
+		t.Errorf("Ожидался статус 200 для другого адреса, получен %d", code)
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ServerLimits ответ GET /limits: ограничения, которые клиент может проверить до загрузки
+type ServerLimits struct {
+	MaxFileSizeBytes     int64    `json:"max_file_size_bytes"`    // 0 — без ограничения
+	MaxConcurrentUploads int      `json:"max_concurrent_uploads"` // Одновременных загрузок с одного адреса (0 — без ограничения)
+	AllowedMIMETypes     []string `json:"allowed_mime_types"`     // Пусто — любые
+}
+
+// handleLimits отдает ограничения загрузки: GET /limits
+func (s *HTTPServer) handleLimits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limits := ServerLimits{
+		MaxFileSizeBytes:     s.config.MaxFileSizeBytes,
+		MaxConcurrentUploads: s.config.MaxConcurrentUploadsPerIP,
+		AllowedMIMETypes:     s.config.AllowedMIMETypes,
+	}
+	if limits.AllowedMIMETypes == nil {
+		limits.AllowedMIMETypes = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limits)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHandleLimits(t *testing.T) {
+	tests := []struct {
+		config   *ServerConfig
+		expected string
+	}{
+		{&ServerConfig{}, `{"max_file_size_bytes":0,"max_concurrent_uploads":0,"allowed_mime_types":[]}`},
+		{
+			&ServerConfig{MaxFileSizeBytes: 1 << 20, MaxConcurrentUploadsPerIP: 2, AllowedMIMETypes: []string{"image/*", "application/pdf"}},
+			`{"max_file_size_bytes":1048576,"max_concurrent_uploads":2,"allowed_mime_types":["image/*","application/pdf"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		test.config.UploadDir = t.TempDir()
+		rec := httptest.NewRecorder()
+		NewHTTPServerWithConfig(test.config).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/limits", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+		}
+		var got, expected any
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		json.Unmarshal([]byte(test.expected), &expected)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Ожидалось %s, получено %s", test.expected, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/limits", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Для POST ожидался статус 405, получен %d", rec.Code)
+	}
+}
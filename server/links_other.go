@@ -0,0 +1,10 @@
+//go:build !unix
+
+package server
+
+import "io/fs"
+
+// fileLinks возвращает ok == false: число жестких ссылок на этой ОС не определяется
+func fileLinks(info fs.FileInfo) (nlink uint64, id fileID, ok bool) {
+	return 0, fileID{}, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package server
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileLinks возвращает число жестких ссылок на файл и идентификатор его inode.
+// ok равно false, если ОС их не сообщает
+func fileLinks(info fs.FileInfo) (nlink uint64, id fileID, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fileID{}, false
+	}
+	return uint64(stat.Nlink), fileID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// listen открывает Unix-сокет SocketPath, если он задан, иначе TCP-адрес listenAddr
+func (s *HTTPServer) listen() (net.Listener, error) {
+	if s.config.SocketPath == "" {
+		return net.Listen("tcp", s.listenAddr())
+	}
+	if err := removeStaleSocket(s.config.SocketPath); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", s.config.SocketPath)
+}
+
+// listenAddr возвращает TCP-адрес сервера: ListenAddr с портом Port. Адрес с портом,
+// например 127.0.0.1:8080, используется как есть; пустой адрес — все интерфейсы
+func (s *HTTPServer) listenAddr() string {
+	addr := s.config.ListenAddr
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(strings.Trim(addr, "[]"), s.port)
+}
+
+// uploadURL возвращает адрес загрузки для лога запуска: на всех интерфейсах — через localhost
+func uploadURL(scheme string, addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return fmt.Sprintf("%s://localhost/upload", scheme)
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s/upload", scheme, net.JoinHostPort(host, port))
+}
+
+// removeStaleSocket удаляет файл сокета, оставшийся после аварийной остановки.
+// Сокет, к которому удается подключиться, занят другим процессом и не удаляется
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%s существует и не является сокетом", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("сокет %s уже используется", path)
+	}
+	return os.Remove(path)
+}
+
+// removeSocket удаляет файл сокета после остановки сервера. Обычно его уже удалил
+// net.UnixListener при закрытии, поэтому отсутствие файла не считается ошибкой
+func (s *HTTPServer) removeSocket() {
+	if s.config.SocketPath == "" {
+		return
+	}
+	if err := os.Remove(s.config.SocketPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger().Warn("Не удалось удалить файл сокета", "path", s.config.SocketPath, "error", err)
+	}
+}
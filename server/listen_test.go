@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Сокет, оставшийся после аварийной остановки: файл есть, слушателя нет
+	stalePath := filepath.Join(dir, "stale.sock")
+	listener, err := net.Listen("unix", stalePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	if err := removeStaleSocket(stalePath); err != nil {
+		t.Errorf("Неожиданная ошибка: %v", err)
+	}
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Error("Устаревший сокет не удален")
+	}
+
+	// Занятый сокет не трогаем
+	livePath := filepath.Join(dir, "live.sock")
+	live, err := net.Listen("unix", livePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+	if err := removeStaleSocket(livePath); err == nil {
+		t.Error("Ожидалась ошибка для занятого сокета")
+	}
+
+	// Обычный файл не удаляется
+	filePath := filepath.Join(dir, "file")
+	os.WriteFile(filePath, []byte("data"), 0644)
+	if err := removeStaleSocket(filePath); err == nil {
+		t.Error("Ожидалась ошибка для обычного файла")
+	}
+
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("Для отсутствующего файла ошибки быть не должно: %v", err)
+	}
+}
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		listenAddr, port, expected string
+	}{
+		{"", "8080", ":8080"},
+		{"127.0.0.1", "8080", "127.0.0.1:8080"},
+		{"0.0.0.0", "9000", "0.0.0.0:9000"},
+		{"::1", "8080", "[::1]:8080"},
+		{"[::1]", "8080", "[::1]:8080"},
+		{"127.0.0.1:9090", "8080", "127.0.0.1:9090"},
+		{"[::1]:9090", "8080", "[::1]:9090"},
+	}
+	for _, test := range tests {
+		s := NewHTTPServerWithConfig(&ServerConfig{Port: test.port, ListenAddr: test.listenAddr})
+		if got := s.listenAddr(); got != test.expected {
+			t.Errorf("Для %q и порта %s ожидалось %q, получено %q", test.listenAddr, test.port, test.expected, got)
+		}
+	}
+}
+
+func TestUploadURL(t *testing.T) {
+	tests := map[string]string{
+		"0.0.0.0:8080":   "http://localhost:8080/upload",
+		"[::]:8080":      "http://localhost:8080/upload",
+		"127.0.0.1:8080": "http://127.0.0.1:8080/upload",
+		"[::1]:8080":     "http://[::1]:8080/upload",
+	}
+	for addr, expected := range tests {
+		tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := uploadURL("http", tcpAddr); got != expected {
+			t.Errorf("Для %s ожидалось %s, получено %s", addr, expected, got)
+		}
+	}
+}
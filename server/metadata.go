@@ -0,0 +1,192 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MetadataHeaderPrefix префикс заголовков с пользовательскими метаданными загрузки:
+// X-Meta-{Key}: {Value}. Ключи сохраняются в нижнем регистре
+const MetadataHeaderPrefix = "X-Meta-"
+
+// metadataSuffix окончание имени файла, в котором LocalStorageBackend хранит метаданные
+const metadataSuffix = ".meta.json"
+
+// errMetadataNotSupported возвращается, если хранилище не сохраняет метаданные
+var errMetadataNotSupported = errors.New("хранилище не поддерживает метаданные файлов")
+
+// MetadataStore хранилище, сохраняющее пользовательские метаданные файлов
+type MetadataStore interface {
+	// SaveMetadata сохраняет метаданные файла filename, заменяя прежние;
+	// пустые метаданные удаляют прежние
+	SaveMetadata(filename string, metadata map[string]string) error
+
+	// LoadMetadata возвращает метаданные файла; если их нет, возвращает ошибку,
+	// для которой errors.Is(err, fs.ErrNotExist) истинно
+	LoadMetadata(filename string) (map[string]string, error)
+}
+
+// uploadMetadata собирает метаданные из заголовков X-Meta-*
+func uploadMetadata(header http.Header) map[string]string {
+	var metadata map[string]string
+	for key, values := range header {
+		name, ok := strings.CutPrefix(http.CanonicalHeaderKey(key), MetadataHeaderPrefix)
+		if !ok || name == "" || len(values) == 0 {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[strings.ToLower(name)] = values[0]
+	}
+	return metadata
+}
+
+// SaveMetadata реализует MetadataStore: метаданные пишутся в файл {filename}.meta.json
+// рядом с файлом через временный файл, поэтому читатель не увидит неполный JSON
+func (b *LocalStorageBackend) SaveMetadata(filename string, metadata map[string]string) error {
+	metaPath := b.path(filename) + metadataSuffix
+	if len(metadata) == 0 {
+		if err := os.Remove(metaPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("ошибка удаления метаданных: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath, err := tempFilePath(metaPath)
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла метаданных: %w", err)
+	}
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("ошибка записи метаданных: %w", err)
+	}
+	if err := os.Rename(tmpPath, metaPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("ошибка сохранения метаданных: %w", err)
+	}
+	return nil
+}
+
+// LoadMetadata реализует MetadataStore
+func (b *LocalStorageBackend) LoadMetadata(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(b.path(filename) + metadataSuffix)
+	if err != nil {
+		return nil, err
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("ошибка разбора метаданных: %w", err)
+	}
+	return metadata, nil
+}
+
+// SaveMetadata реализует MetadataStore
+func (b *MemoryStorageBackend) SaveMetadata(filename string, metadata map[string]string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(metadata) == 0 {
+		delete(b.metadata, filename)
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	b.metadata[filename] = copied
+	return nil
+}
+
+// LoadMetadata реализует MetadataStore
+func (b *MemoryStorageBackend) LoadMetadata(filename string) (map[string]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	metadata, ok := b.metadata[filename]
+	if !ok {
+		return nil, fmt.Errorf("метаданные файла %s не найдены: %w", filename, fs.ErrNotExist)
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied, nil
+}
+
+// Metadata возвращает пользовательские метаданные, переданные при загрузке файла
+func (s *HTTPServer) Metadata(filename string) (map[string]string, error) {
+	store, ok := s.storage.(MetadataStore)
+	if !ok {
+		return nil, errMetadataNotSupported
+	}
+	return store.LoadMetadata(filepath.ToSlash(filename))
+}
+
+// checkUploadMetadata отклоняет загрузку с заголовками X-Meta-*, если хранилище
+// не сохраняет метаданные: проверка выполняется до приема файла, чтобы он не был
+// сохранен при ответе с ошибкой. Возвращает false, если ответ уже отправлен
+func (s *HTTPServer) checkUploadMetadata(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := s.storage.(MetadataStore); ok || len(uploadMetadata(r.Header)) == 0 {
+		return true
+	}
+	s.httpError(w, r, fmt.Sprintf("Хранилище не поддерживает метаданные файлов (заголовки %s*)", MetadataHeaderPrefix), http.StatusNotImplemented)
+	return false
+}
+
+// saveUploadMetadata сохраняет метаданные из заголовков X-Meta-* для загруженного файла,
+// заменяя метаданные прежней загрузки, даже если новых нет. Непустой contentType
+// (DetectContentType) сохраняется под ключом content_type, если хранилище поддерживает
+// метаданные; иначе он только возвращается в ответе
+func (s *HTTPServer) saveUploadMetadata(r *http.Request, filename, contentType string) error {
+	metadata := uploadMetadata(r.Header)
+	store, ok := s.storage.(MetadataStore)
+	if !ok {
+		if len(metadata) > 0 {
+			return errMetadataNotSupported
+		}
+		return nil
+	}
+	if contentType != "" {
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[MetadataContentType] = contentType
+	}
+	return store.SaveMetadata(filename, metadata)
+}
+
+// handleFileMetadata отдает метаданные файла: GET /files/{filename}/meta
+func (s *HTTPServer) handleFileMetadata(w http.ResponseWriter, r *http.Request, filename string) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	metadata, err := s.Metadata(filename)
+	switch {
+	case errors.Is(err, errMetadataNotSupported):
+		s.requestError(w, r, "Хранилище не поддерживает метаданные файлов", http.StatusNotImplemented)
+		return
+	case errors.Is(err, fs.ErrNotExist):
+		s.requestError(w, r, fmt.Sprintf("Метаданные файла %s не найдены", filename), http.StatusNotFound)
+		return
+	case err != nil:
+		s.requestError(w, r, fmt.Sprintf("Ошибка чтения метаданных: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(metadata)
+}
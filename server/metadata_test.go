@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleUpload_Metadata(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:   dir,
+		AllowDelete: true,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := newUploadRequest(t, "data.bin", []byte("data"))
+	req.Header.Set("X-Meta-Author", "alice")
+	req.Header.Set("x-meta-build", "7")
+	req.Header.Set("X-Other", "ignored")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+
+	rec = getFile(t, s, "/files/data.bin/meta")
+	var metadata map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("Ошибка разбора ответа: %v", err)
+	}
+	if len(metadata) != 2 || metadata["author"] != "alice" || metadata["build"] != "7" {
+		t.Errorf("Неверные метаданные: %v", metadata)
+	}
+
+	// Файл метаданных не попадает в список файлов
+	var list FileList
+	json.Unmarshal(getFile(t, s, "/files").Body.Bytes(), &list)
+	if list.Total != 1 {
+		t.Errorf("Ожидался 1 файл в списке, получено %+v", list.Files)
+	}
+
+	// И удаляется вместе с файлом
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/files/data.bin", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Ожидался статус 204, получен %d", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data.bin"+metadataSuffix)); !os.IsNotExist(err) {
+		t.Error("Файл метаданных не удален")
+	}
+	if rec := getFile(t, s, "/files/data.bin/meta"); rec.Code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404, получен %d", rec.Code)
+	}
+}
+
+func TestHandleUpload_NoMetadata(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: backend,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	upload(t, s, "plain.bin", []byte("data"))
+	if _, err := s.Metadata("plain.bin"); err == nil {
+		t.Error("Для загрузки без X-Meta-* метаданные не должны сохраняться")
+	}
+}
+
+func TestHandleUpload_MetadataReplaced(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir: dir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := newUploadRequest(t, "data.bin", []byte("old"))
+	req.Header.Set("X-Meta-Author", "alice")
+	s.handleUpload(httptest.NewRecorder(), req)
+
+	// Перезапись без X-Meta-* удаляет метаданные прежней загрузки
+	upload(t, s, "data.bin", []byte("new"))
+	if rec := getFile(t, s, "/files/data.bin/meta"); rec.Code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404, получен %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Файл с именем файла метаданных не принимается
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "data.bin"+metadataSuffix, []byte(`{"author":"mallory"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400, получен %d", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data.bin"+metadataSuffix)); !os.IsNotExist(err) {
+		t.Error("Файл метаданных не должен создаваться загрузкой")
+	}
+}
+
+func TestHandleUpload_MetadataNotSupported(t *testing.T) {
+	// Встраивание интерфейса скрывает MetadataStore хранилища в памяти
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: struct{ StorageBackend }{backend},
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := newUploadRequest(t, "data.bin", []byte("data"))
+	req.Header.Set("X-Meta-Author", "alice")
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("Ожидался статус 501, получен %d", rec.Code)
+	}
+	if _, ok := backend.Contents("data.bin"); ok {
+		t.Error("Отклоненный файл не должен сохраняться")
+	}
+
+	// Без X-Meta-* файл принимается
+	upload(t, s, "data.bin", []byte("data"))
+	if _, ok := backend.Contents("data.bin"); !ok {
+		t.Error("Файл не сохранен")
+	}
+}
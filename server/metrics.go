@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// uploadDurationBuckets границы корзин гистограммы длительности загрузки в секундах
+var uploadDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600}
+
+// metrics счетчики сервера в формате Prometheus
+type metrics struct {
+	mu sync.Mutex
+
+	uploadBytes uint64            // http_upload_bytes_total
+	uploadFiles uint64            // http_upload_files_total
+	errors      map[string]uint64 // http_upload_errors_total по типу ошибки
+
+	durationCounts []uint64 // Количество наблюдений в каждой корзине (не накопительно)
+	durationSum    float64
+	durationCount  uint64
+}
+
+// newMetrics создает пустой набор метрик
+func newMetrics() *metrics {
+	return &metrics{
+		errors:         make(map[string]uint64),
+		durationCounts: make([]uint64, len(uploadDurationBuckets)),
+	}
+}
+
+// files возвращает количество успешно сохраненных файлов
+func (m *metrics) files() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.uploadFiles
+}
+
+// observeUpload учитывает успешно принятый файл
+func (m *metrics) observeUpload(bytes int64, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.uploadBytes += uint64(bytes)
+	m.uploadFiles++
+
+	seconds := duration.Seconds()
+	m.durationSum += seconds
+	m.durationCount++
+	for i, bound := range uploadDurationBuckets {
+		if seconds <= bound {
+			m.durationCounts[i]++
+			break
+		}
+	}
+}
+
+// observeError учитывает ошибку обработки загрузки
+func (m *metrics) observeError(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.errors[errorType(status)]++
+}
+
+// errorType возвращает тип ошибки для метки метрики по HTTP-статусу
+func errorType(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusRequestEntityTooLarge:
+		return "too_large"
+	case http.StatusInternalServerError:
+		return "internal"
+	default:
+		return fmt.Sprintf("status_%d", status)
+	}
+}
+
+// writeTo выводит метрики в текстовом формате Prometheus
+func (m *metrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP http_upload_bytes_total Total number of bytes received in successful uploads.")
+	fmt.Fprintln(w, "# TYPE http_upload_bytes_total counter")
+	fmt.Fprintf(w, "http_upload_bytes_total %d\n", m.uploadBytes)
+
+	fmt.Fprintln(w, "# HELP http_upload_files_total Total number of successfully uploaded files.")
+	fmt.Fprintln(w, "# TYPE http_upload_files_total counter")
+	fmt.Fprintf(w, "http_upload_files_total %d\n", m.uploadFiles)
+
+	fmt.Fprintln(w, "# HELP http_upload_errors_total Total number of failed uploads by error type.")
+	fmt.Fprintln(w, "# TYPE http_upload_errors_total counter")
+	types := make([]string, 0, len(m.errors))
+	for errType := range m.errors {
+		types = append(types, errType)
+	}
+	sort.Strings(types)
+	for _, errType := range types {
+		fmt.Fprintf(w, "http_upload_errors_total{type=%q} %d\n", errType, m.errors[errType])
+	}
+
+	fmt.Fprintln(w, "# HELP http_upload_duration_seconds Duration of successful uploads in seconds.")
+	fmt.Fprintln(w, "# TYPE http_upload_duration_seconds histogram")
+	var cumulative uint64
+	for i, bound := range uploadDurationBuckets {
+		cumulative += m.durationCounts[i]
+		fmt.Fprintf(w, "http_upload_duration_seconds_bucket{le=%q} %d\n", formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "http_upload_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.durationCount)
+	fmt.Fprintf(w, "http_upload_duration_seconds_sum %s\n", formatFloat(m.durationSum))
+	fmt.Fprintf(w, "http_upload_duration_seconds_count %d\n", m.durationCount)
+}
+
+// formatFloat форматирует число без лишних нулей
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// handleMetrics отдает метрики сервера
+func (s *HTTPServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.writeTo(w)
+}
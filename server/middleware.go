@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// RequestIDHeader заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength наибольшая длина идентификатора запроса, принимаемого от клиента
+const maxRequestIDLength = 128
+
+// Middleware оборачивает обработчик сервера дополнительной логикой
+type Middleware func(http.Handler) http.Handler
+
+// AddMiddleware добавляет middleware вокруг всех маршрутов сервера. Middleware
+// применяются в порядке добавления: первое добавленное получает запрос первым.
+// Фильтр IP-адресов остается внешним слоем. Действует для Start и последующих вызовов Handler
+func (s *HTTPServer) AddMiddleware(m Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middlewares = append(s.middlewares, m)
+}
+
+// applyMiddlewares оборачивает handler добавленными middleware
+func (s *HTTPServer) applyMiddlewares(handler http.Handler) http.Handler {
+	s.mu.Lock()
+	middlewares := append([]Middleware(nil), s.middlewares...)
+	s.mu.Unlock()
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// requestIDKey ключ контекста запроса с его идентификатором
+type requestIDKey struct{}
+
+// RequestIDFromContext возвращает идентификатор запроса, назначенный RequestIDMiddleware
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDMiddleware назначает запросу идентификатор: берет его из заголовка X-Request-ID
+// клиента или создает UUID. Идентификатор возвращается в заголовке ответа X-Request-ID
+// и доступен обработчикам через RequestIDFromContext
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			var err error
+			if id, err = newSessionID(); err != nil {
+				http.Error(w, "Ошибка создания идентификатора запроса", http.StatusInternalServerError)
+				return
+			}
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// validRequestID проверяет, что идентификатор от клиента можно вернуть в заголовке и записать в лог
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// LoggingMiddleware записывает в лог каждый запрос: метод, путь, статус,
+// размер ответа и длительность. nil — slog.Default()
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger
+			if log == nil {
+				log = slog.Default()
+			}
+
+			startTime := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			attrs := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", recorder.written,
+				"duration", formatDuration(time.Since(startTime)),
+				"remote_addr", r.RemoteAddr,
+			}
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				attrs = append(attrs, "request_id", id)
+			}
+			log.Info("HTTP-запрос", attrs...)
+		})
+	}
+}
+
+// RecoverMiddleware перехватывает панику обработчика, записывает ее в лог со стеком
+// и отвечает 500, если ответ еще не начат. http.ErrAbortHandler пробрасывается дальше
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			slog.Default().Error("Паника при обработке запроса",
+				"path", r.URL.Path,
+				"remote_addr", r.RemoteAddr,
+				"panic", p,
+				"stack", string(debug.Stack()))
+			if recorder.status == 0 {
+				http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// statusRecorder запоминает статус и размер ответа
+type statusRecorder struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// Unwrap позволяет http.ResponseController управлять исходным соединением
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
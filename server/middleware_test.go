@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddMiddleware_Order(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()})
+
+	var calls []string
+	for _, name := range []string{"first", "second"} {
+		name := name
+		s.AddMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || strings.Join(calls, ",") != "first,second" {
+		t.Errorf("Неверный порядок middleware: %v, статус %d", calls, rec.Code)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	generated := rec.Header().Get(RequestIDHeader)
+	if len(generated) != 36 || seen != generated {
+		t.Errorf("Идентификатор не создан: заголовок %q, контекст %q", generated, seen)
+	}
+
+	for _, tc := range []struct{ incoming, want string }{
+		{"client-id-1", "client-id-1"},
+		{"bad id\n", ""},
+		{strings.Repeat("x", maxRequestIDLength+1), ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, tc.incoming)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		got := rec.Header().Get(RequestIDHeader)
+		if tc.want != "" && got != tc.want || tc.want == "" && (got == tc.incoming || len(got) != 36) {
+			t.Errorf("Заголовок %q: получен идентификатор %q", tc.incoming, got)
+		}
+	}
+}
+
+func TestLoggingAndRecoverMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()})
+	s.AddMiddleware(RequestIDMiddleware)
+	s.AddMiddleware(LoggingMiddleware(slog.New(slog.NewTextHandler(&logs, nil))))
+	s.AddMiddleware(RecoverMiddleware)
+	s.AddMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/panic" {
+				panic("сбой обработчика")
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	handler := s.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Паника должна завершаться статусом 500, получен %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Ожидался статус 200, получен %d", rec.Code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Ожидалось 2 записи лога, получено %d: %s", len(lines), logs.String())
+	}
+	if !strings.Contains(lines[0], "path=/panic") || !strings.Contains(lines[0], "status=500") {
+		t.Errorf("Неверная запись о панике: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "status=200") || !strings.Contains(lines[1], "request_id="+rec.Header().Get(RequestIDHeader)) {
+		t.Errorf("Неверная запись о запросе: %s", lines[1])
+	}
+}
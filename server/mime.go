@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLen количество байт, по которым http.DetectContentType определяет тип
+const sniffLen = 512
+
+// errMIMETypeNotAllowed возвращается, если тип содержимого не входит в AllowedMIMETypes
+var errMIMETypeNotAllowed = errors.New("тип содержимого не разрешен")
+
+// checkMIMEType определяет тип содержимого по первым 512 байтам и проверяет его
+// по AllowedMIMETypes. Возвращает reader, который снова начинается с прочитанных байт,
+// и тип для DetectContentType. Если список пуст и DetectContentType выключен,
+// данные не читаются, а тип пустой
+func (s *HTTPServer) checkMIMEType(r io.Reader) (io.Reader, string, error) {
+	if len(s.config.AllowedMIMETypes) == 0 && !s.config.DetectContentType {
+		return r, "", nil
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, "", err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if len(s.config.AllowedMIMETypes) > 0 && !mimeTypeAllowed(contentType, s.config.AllowedMIMETypes) {
+		return nil, "", fmt.Errorf("%w: %s", errMIMETypeNotAllowed, contentType)
+	}
+	if !s.config.DetectContentType {
+		contentType = ""
+	}
+	return io.MultiReader(bytes.NewReader(head), r), contentType, nil
+}
+
+// mimeTypeAllowed сравнивает тип без параметров (charset и т.п.) со списком.
+// Элемент списка вида image/* разрешает все подтипы
+func mimeTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pngHeader сигнатура PNG, по которой http.DetectContentType определяет image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestHandleUpload_AllowedMIMETypes(t *testing.T) {
+	large := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 2*sniffLen)...)
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		content    []byte
+		wantStatus int
+	}{
+		{"пустой список принимает все", nil, []byte("<?php echo 1; ?>"), http.StatusOK},
+		{"разрешенный тип", []string{"image/png"}, pngHeader, http.StatusOK},
+		{"файл больше 512 байт", []string{"image/png"}, large, http.StatusOK},
+		{"шаблон подтипов", []string{"image/*"}, pngHeader, http.StatusOK},
+		{"тип с параметрами", []string{"text/plain"}, []byte("hello"), http.StatusOK},
+		{"запрещенный тип", []string{"image/png"}, []byte("<?php echo 1; ?>"), http.StatusUnsupportedMediaType},
+		{"исполняемый файл", []string{"image/*", "text/plain"}, []byte("MZ\x90\x00\x03\x00"), http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := NewMemoryStorageBackend()
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				Backend:          backend,
+				AllowedMIMETypes: tt.allowed,
+				Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			if code := upload(t, s, "file.bin", tt.content); code != tt.wantStatus {
+				t.Fatalf("Ожидался статус %d, получен %d", tt.wantStatus, code)
+			}
+
+			content, ok := backend.Contents("file.bin")
+			if tt.wantStatus != http.StatusOK {
+				if ok {
+					t.Error("Отклоненный файл сохранен в хранилище")
+				}
+				return
+			}
+			if !bytes.Equal(content, tt.content) {
+				t.Errorf("Содержимое искажено: получено %d байт из %d", len(content), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestHandleUpload_DetectContentType(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:           backend,
+		DetectContentType: true,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "image.bin", pngHeader))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	var response uploadResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Ошибка разбора ответа %q: %v", rec.Body.String(), err)
+	}
+	if response.ContentType != "image/png" {
+		t.Errorf("Ожидался тип image/png в ответе, получено %q", response.ContentType)
+	}
+
+	// Тип сохраняется в метаданных вместе с X-Meta-*
+	metadata, err := s.Metadata("image.bin")
+	if err != nil {
+		t.Fatalf("Ошибка чтения метаданных: %v", err)
+	}
+	if metadata[MetadataContentType] != "image/png" {
+		t.Errorf("Ожидался тип image/png в метаданных, получено %v", metadata)
+	}
+	if content, _ := backend.Contents("image.bin"); !bytes.Equal(content, pngHeader) {
+		t.Errorf("Содержимое искажено: %q", content)
+	}
+}
+
+func TestHandleUpload_DetectContentTypeDisabled(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "image.bin", pngHeader))
+	if rec.Header().Get("Content-Type") == "application/json" {
+		t.Errorf("Без DetectContentType ответ не должен быть JSON: %s", rec.Body.String())
+	}
+	if _, err := s.Metadata("image.bin"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Без DetectContentType метаданные не сохраняются: %v", err)
+	}
+}
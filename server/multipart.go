@@ -0,0 +1,97 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// maxFormFieldsSize наибольший суммарный размер полей формы, переданных перед файлом
+const maxFormFieldsSize = 1 << 20
+
+// errFormFieldsTooLarge возвращается, если поля формы превышают maxFormFieldsSize
+var errFormFieldsTooLarge = errors.New("поля формы превышают 1 MB")
+
+// uploadForm часть формы с файлом и поля, переданные перед ней
+type uploadForm struct {
+	file     *multipart.Part   // Содержимое файла читается потоком из тела запроса
+	filename string            // Имя файла без директорий, как у multipart.FileHeader
+	fields   map[string]string // Поля формы перед файлом (nil, если их нет)
+}
+
+// readUploadForm читает multipart-тело запроса до части file, не буферизуя файл ни в памяти,
+// ни во временном файле, как это делает r.ParseMultipartForm: содержимое файла читается
+// из uploadForm.file по мере записи в хранилище. Поля формы после файла не читаются,
+// другие файлы перед ним пропускаются. Если части file нет, возвращается http.ErrMissingFile
+func readUploadForm(r *http.Request) (*uploadForm, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, http.ErrNotMultipart
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, http.ErrMissingBoundary
+	}
+
+	reader := multipart.NewReader(r.Body, boundary)
+	form := &uploadForm{}
+	fieldsSize := int64(0)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, http.ErrMissingFile
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FormName() == "file" && part.FileName() != "" {
+			form.file, form.filename = part, part.FileName()
+			return form, nil
+		}
+		if part.FileName() != "" || part.FormName() == "" {
+			if _, err := io.Copy(io.Discard, part); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldsSize-fieldsSize+1))
+		if err != nil {
+			return nil, err
+		}
+		fieldsSize += int64(len(value))
+		if fieldsSize > maxFormFieldsSize {
+			return nil, errFormFieldsTooLarge
+		}
+		if form.fields == nil {
+			form.fields = make(map[string]string)
+		}
+		// Как и в multipart.Form, учитывается первое значение поля
+		if _, ok := form.fields[part.FormName()]; !ok {
+			form.fields[part.FormName()] = string(value)
+		}
+	}
+}
+
+// isMaxBytesError сообщает, превышен ли лимит http.MaxBytesReader
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// uploadFormError возвращает сообщение и статус ответа на ошибку чтения тела запроса
+func (s *HTTPServer) uploadFormError(err error) (string, int) {
+	switch {
+	case isMaxBytesError(err):
+		return fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(s.config.MaxFileSizeBytes)), http.StatusRequestEntityTooLarge
+	case errors.Is(err, errBodyReadTimeout):
+		return fmt.Sprintf("Файл не получен за %s", s.config.BodyReadTimeout), http.StatusRequestTimeout
+	case errors.Is(err, http.ErrMissingFile):
+		return fmt.Sprintf("Ошибка получения файла: %v", err), http.StatusBadRequest
+	}
+	return fmt.Sprintf("Ошибка парсинга формы: %v", err), http.StatusBadRequest
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// zeroReader бесконечный поток нулевых байт
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// discardBackend хранилище, которое только считает принятые байты
+type discardBackend struct{}
+
+func (discardBackend) Save(_ context.Context, _ string, r io.Reader, _ map[string]string) (int64, error) {
+	return io.Copy(io.Discard, r)
+}
+
+func (discardBackend) Exists(string) (bool, error) { return false, nil }
+
+func TestHandleUpload_StreamingMemory(t *testing.T) {
+	const fileSize = 100 << 20
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: discardBackend{},
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Тело запроса формируется на лету, чтобы сам тест не держал файл в памяти
+	var head bytes.Buffer
+	writer := multipart.NewWriter(&head)
+	writer.CreateFormFile("file", "large.bin")
+	tail := "\r\n--" + writer.Boundary() + "--\r\n"
+	body := io.MultiReader(&head, io.LimitReader(zeroReader{}, fileSize), strings.NewReader(tail))
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	s.handleUpload(rec, req)
+	runtime.ReadMemStats(&after)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+	// Все выделения памяти за время приема, а не только пиковое использование кучи
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 2<<20 {
+		t.Errorf("При приеме 100 MB выделено %s памяти, ожидалось меньше 2 MB", formatBytes(int64(allocated)))
+	}
+}
+
+func TestReadUploadForm(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("description", "Отчет")
+	writer.WriteField("description", "повтор")
+	other, _ := writer.CreateFormFile("attachment", "other.bin")
+	other.Write([]byte("пропускается"))
+	part, _ := writer.CreateFormFile("file", "../dir/report.bin")
+	part.Write([]byte("report"))
+	writer.WriteField("after", "не читается")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	form, err := readUploadForm(req)
+	if err != nil {
+		t.Fatalf("Ошибка чтения формы: %v", err)
+	}
+	content, _ := io.ReadAll(form.file)
+	if form.filename != "report.bin" || string(content) != "report" {
+		t.Errorf("Неверный файл: %q, %q", form.filename, content)
+	}
+	if len(form.fields) != 1 || form.fields["description"] != "Отчет" {
+		t.Errorf("Неверные поля формы: %v", form.fields)
+	}
+}
+
+func TestReadUploadForm_Errors(t *testing.T) {
+	var noFile bytes.Buffer
+	writer := multipart.NewWriter(&noFile)
+	writer.WriteField("description", "без файла")
+	writer.Close()
+
+	var largeFields bytes.Buffer
+	large := multipart.NewWriter(&largeFields)
+	large.WriteField("a", strings.Repeat("x", maxFormFieldsSize/2))
+	large.WriteField("b", strings.Repeat("x", maxFormFieldsSize/2+1))
+	large.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{"не multipart", "application/octet-stream", "data", http.ErrNotMultipart},
+		{"нет границы", "multipart/form-data", "data", http.ErrMissingBoundary},
+		{"нет файла", writer.FormDataContentType(), noFile.String(), http.ErrMissingFile},
+		{"большие поля", large.FormDataContentType(), largeFields.String(), errFormFieldsTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if _, err := readUploadForm(req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHandleUpload_TruncatedBody(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Тело обрывается посреди файла, без закрывающей границы
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "cut.bin")
+	part.Write(bytes.Repeat([]byte("x"), 10000))
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Ожидался статус 400, получен %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// defaultOrphanedFileTTL возраст, после которого временный файл считается брошенным
+const defaultOrphanedFileTTL = 24 * time.Hour
+
+// maxOrphanScanInterval наибольший интервал между проверками директорий
+const maxOrphanScanInterval = time.Hour
+
+// tempFileName совпадает с именами, которые создает tempFilePath
+var tempFileName = regexp.MustCompile(`^\.tmp\.[0-9a-f]{32}\.`)
+
+// orphanCleaner периодически удаляет временные файлы, оставшиеся после аварийного
+// завершения сервера: обычно они удаляются сразу после записи или ошибки
+type orphanCleaner struct {
+	dirs   []string
+	ttl    time.Duration
+	logger func() *slog.Logger
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newOrphanCleaner создает очистку директорий dirs. Возвращает nil, если ttl отрицателен
+func newOrphanCleaner(ttl time.Duration, logger func() *slog.Logger, dirs ...string) *orphanCleaner {
+	if ttl < 0 {
+		return nil
+	}
+	if ttl == 0 {
+		ttl = defaultOrphanedFileTTL
+	}
+	return &orphanCleaner{dirs: dirs, ttl: ttl, logger: logger}
+}
+
+// start выполняет первую проверку и запускает периодические в фоне
+func (c *orphanCleaner) start() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.loop(c.stop, c.done)
+}
+
+// loop проверяет директории, пока не закрыт stop. Файлы, брошенные до запуска сервера,
+// удаляются сразу
+func (c *orphanCleaner) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	c.clean(time.Now())
+	ticker := time.NewTicker(min(c.ttl, maxOrphanScanInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c.clean(now)
+		}
+	}
+}
+
+// clean удаляет временные файлы старше ttl на момент now и возвращает их количество
+func (c *orphanCleaner) clean(now time.Time) int {
+	removed := 0
+	for _, dir := range c.dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !tempFileName.MatchString(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				// Файл мог быть переименован, пока шел обход
+				return nil
+			}
+			age := now.Sub(info.ModTime())
+			if age < c.ttl {
+				return nil
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				c.logger().Warn("Не удалось удалить временный файл", "path", path, "error", err)
+				return nil
+			}
+			removed++
+			c.logger().Info("Удален брошенный временный файл", "path", path, "age", age.Round(time.Second))
+			return nil
+		})
+		if err != nil {
+			c.logger().Warn("Ошибка проверки временных файлов", "dir", dir, "error", err)
+		}
+	}
+	return removed
+}
+
+// close останавливает периодические проверки
+func (c *orphanCleaner) close() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
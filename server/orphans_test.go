@@ -0,0 +1,118 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOrphanCleaner_Clean(t *testing.T) {
+	uploadDir := t.TempDir()
+	chunkDir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-25 * time.Hour)
+
+	write := func(path string, modTime time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orphan := filepath.Join(uploadDir, "tenants", ".tmp.0123456789abcdef0123456789abcdef.a.bin")
+	chunkOrphan := filepath.Join(chunkDir, "session", ".tmp.fedcba9876543210fedcba9876543210.0")
+	fresh := filepath.Join(uploadDir, ".tmp.00000000000000000000000000000000.b.bin")
+	stored := filepath.Join(uploadDir, "c.bin")
+	similar := filepath.Join(uploadDir, ".tmp.notes.txt")
+	write(orphan, old)
+	write(chunkOrphan, old)
+	write(fresh, now.Add(-time.Hour))
+	write(stored, old)
+	write(similar, old)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cleaner := newOrphanCleaner(0, func() *slog.Logger { return logger }, chunkDir, uploadDir, filepath.Join(uploadDir, "missing"))
+	if removed := cleaner.clean(now); removed != 2 {
+		t.Errorf("Ожидалось удаление 2 файлов, удалено %d", removed)
+	}
+
+	for _, path := range []string{orphan, chunkOrphan} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Брошенный файл %s не удален", path)
+		}
+	}
+	for _, path := range []string{fresh, stored, similar} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Файл %s не должен удаляться: %v", path, err)
+		}
+	}
+}
+
+func TestOrphanCleaner_Disabled(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), OrphanedFileTTL: -1})
+	if s.orphans != nil {
+		t.Error("Очистка не должна создаваться при отрицательном OrphanedFileTTL")
+	}
+	// Вызовы на nil безопасны
+	s.orphans.start()
+	s.orphans.close()
+}
+
+func TestOrphanCleaner_StartClose(t *testing.T) {
+	dir := t.TempDir()
+	orphan := filepath.Join(dir, ".tmp.0123456789abcdef0123456789abcdef.a.bin")
+	if err := os.WriteFile(orphan, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(orphan, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cleaner := newOrphanCleaner(time.Minute, func() *slog.Logger { return logger }, dir)
+	cleaner.start()
+	cleaner.close()
+
+	// Первая проверка выполняется сразу при запуске
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Error("Брошенный файл не удален при запуске")
+	}
+	cleaner.close()
+}
+
+func TestOrphanCleaner_KeepsUploadedTempName(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir: dir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Клиент не может сохранить файл под именем, которое очистка примет за временное
+	name := ".tmp.0123456789abcdef0123456789abcdef.a.bin"
+	if code := upload(t, s, name, []byte("data")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	old := time.Now().Add(-25 * time.Hour)
+	stored := filepath.Join(dir, "_"+name[1:])
+	if err := os.Chtimes(stored, old, old); err != nil {
+		t.Fatalf("Файл не сохранен под измененным именем: %v", err)
+	}
+
+	if removed := s.orphans.clean(time.Now()); removed != 0 {
+		t.Errorf("Загруженный файл удален очисткой: удалено %d", removed)
+	}
+	if _, err := os.Stat(stored); err != nil {
+		t.Errorf("Загруженный файл удален: %v", err)
+	}
+}
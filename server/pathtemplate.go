@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultUploadPathTemplate шаблон пути по умолчанию: файл сохраняется под своим именем
+const defaultUploadPathTemplate = "{filename}"
+
+// pathTemplateToken маркер подстановки в UploadPathTemplate
+var pathTemplateToken = regexp.MustCompile(`\{[^{}]*\}`)
+
+// validatePathTemplate проверяет, что шаблон содержит только известные маркеры
+// и {filename}: без него все загрузки попадали бы в один файл
+func validatePathTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+	for _, token := range pathTemplateToken.FindAllString(template, -1) {
+		switch token {
+		case "{year}", "{month}", "{day}", "{hour}", "{filename}", "{ext}":
+		default:
+			return fmt.Errorf("неизвестный маркер %s в шаблоне пути %q", token, template)
+		}
+	}
+	if !strings.Contains(template, "{filename}") {
+		return fmt.Errorf("шаблон пути %q не содержит {filename}", template)
+	}
+	return nil
+}
+
+// expandPathTemplate подставляет в шаблон дату now и имя файла relPath (путь
+// относительно директории загрузки) и возвращает путь в хранилище через "/".
+// {ext} — расширение без точки. Результат проходит ту же проверку, что и путь
+// от клиента: выход за пределы директории отклоняется, компоненты очищаются sanitizeFilename
+func expandPathTemplate(template, relPath string, now time.Time) (string, error) {
+	if template == "" {
+		template = defaultUploadPathTemplate
+	}
+	if err := validatePathTemplate(template); err != nil {
+		return "", err
+	}
+
+	filename := filepath.ToSlash(relPath)
+	replacer := strings.NewReplacer(
+		"{year}", fmt.Sprintf("%04d", now.Year()),
+		"{month}", fmt.Sprintf("%02d", now.Month()),
+		"{day}", fmt.Sprintf("%02d", now.Day()),
+		"{hour}", fmt.Sprintf("%02d", now.Hour()),
+		"{filename}", filename,
+		"{ext}", strings.TrimPrefix(path.Ext(filename), "."),
+	)
+	// Пустые компоненты, например от {ext} файла без расширения, отбрасываются
+	var parts []string
+	for _, part := range strings.Split(replacer.Replace(template), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	expanded, err := cleanRelativePath(strings.Join(parts, "/"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(expanded), nil
+}
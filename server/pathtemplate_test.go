@@ -0,0 +1,81 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExpandPathTemplate(t *testing.T) {
+	now := time.Date(2025, time.June, 5, 7, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		template string
+		relPath  string
+		expected string
+		wantErr  bool
+	}{
+		{"по умолчанию", "", "report.pdf", "report.pdf", false},
+		{"по дате", "{year}/{month}/{day}/{filename}", "report.pdf", "2025/06/05/report.pdf", false},
+		{"по часу и расширению", "{ext}/{year}{month}{day}-{hour}/{filename}", "report.pdf", "pdf/20250605-07/report.pdf", false},
+		{"без расширения", "{ext}/{filename}", "README", "README", false},
+		{"относительный путь клиента", "{year}/{filename}", "docs/q3/report.pdf", "2025/docs/q3/report.pdf", false},
+		{"очистка символов", "архив {year}/{filename}", "report.pdf", "______2025/report.pdf", false},
+		{"выход за пределы", "../{filename}", "report.pdf", "", true},
+		{"неизвестный маркер", "{minute}/{filename}", "report.pdf", "", true},
+		{"без имени файла", "{year}/{month}", "report.pdf", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPathTemplate(tt.template, tt.relPath, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandPathTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("expandPathTemplate() = %q, ожидалось %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServerConfig_ValidatePathTemplate(t *testing.T) {
+	config := &ServerConfig{UploadPathTemplate: "{year}/{name}"}
+	if err := config.validate(); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного маркера")
+	}
+	config.UploadPathTemplate = "{year}/{month}/{filename}"
+	if err := config.validate(); err != nil {
+		t.Errorf("Неожиданная ошибка: %v", err)
+	}
+}
+
+func TestHandleUpload_PathTemplate(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:          dir,
+		UploadPathTemplate: "{year}/{month}/{day}/{filename}",
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	before := time.Now()
+	if code := upload(t, s, "report.pdf", []byte("report")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	// Загрузка могла прийтись на смену суток
+	for _, day := range []time.Time{before, time.Now()} {
+		path := filepath.Join(dir, day.Format("2006"), day.Format("01"), day.Format("02"), "report.pdf")
+		if content, err := os.ReadFile(path); err == nil {
+			if string(content) != "report" {
+				t.Errorf("Неверное содержимое файла: %q", content)
+			}
+			return
+		}
+	}
+	t.Error("Файл не сохранен в директорию с датой загрузки")
+}
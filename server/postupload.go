@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PostUploadPathPlaceholder заменяется в аргументах PostUploadCommand путем к сохраненному файлу
+const PostUploadPathPlaceholder = "{path}"
+
+// defaultPostUploadTimeout ограничивает время выполнения команды, если PostUploadTimeout не задан
+const defaultPostUploadTimeout = 5 * time.Minute
+
+// maxCommandOutput наибольший объем stdout и stderr команды, записываемый в лог
+const maxCommandOutput = 4096
+
+// limitedBuffer сохраняет первые maxCommandOutput байт вывода и отбрасывает остальное,
+// не прерывая команду
+type limitedBuffer struct {
+	buf       []byte
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := min(len(p), maxCommandOutput-len(b.buf))
+	b.buf = append(b.buf, p[:n]...)
+	if n < len(p) {
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	s := strings.TrimSpace(string(b.buf))
+	if b.truncated {
+		s += "..."
+	}
+	return s
+}
+
+// postUploadPath возвращает путь, подставляемый в PostUploadCommand: путь на диске
+// для LocalStorageBackend или имя файла в остальных хранилищах
+func (s *HTTPServer) postUploadPath(storedName string) string {
+	if local, ok := s.storage.(*LocalStorageBackend); ok {
+		return local.path(storedName)
+	}
+	return storedName
+}
+
+// runPostUpload запускает PostUploadCommand для сохраненного файла в отдельной горутине.
+// Файл уже сохранен, поэтому ошибка команды не влияет на ответ клиенту и только записывается в лог
+func (s *HTTPServer) runPostUpload(storedName string) {
+	if len(s.config.PostUploadCommand) == 0 {
+		return
+	}
+
+	filePath := s.postUploadPath(storedName)
+	s.webhooks.Add(1)
+	go func() {
+		defer s.webhooks.Done()
+		s.execPostUpload(filePath)
+	}()
+}
+
+// execPostUpload выполняет PostUploadCommand для файла filePath и записывает результат в лог
+func (s *HTTPServer) execPostUpload(filePath string) error {
+	args := make([]string, len(s.config.PostUploadCommand))
+	for i, arg := range s.config.PostUploadCommand {
+		args[i] = strings.ReplaceAll(arg, PostUploadPathPlaceholder, filePath)
+	}
+
+	timeout := s.config.PostUploadTimeout
+	if timeout <= 0 {
+		timeout = defaultPostUploadTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr limitedBuffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	logger := s.logger().With(
+		"command", args[0],
+		"path", filePath,
+		"duration", formatDuration(time.Since(start)),
+		"stdout", stdout.String(),
+		"stderr", stderr.String())
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logger.Warn("Команда после загрузки прервана по таймауту", "timeout", timeout)
+		return ctx.Err()
+	}
+	if err != nil {
+		logger.Warn("Команда после загрузки завершилась с ошибкой", "error", err)
+		return err
+	}
+	logger.Info("Команда после загрузки выполнена")
+	return nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requireCommands пропускает тест, если нужных программ нет в PATH
+func requireCommands(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("Программа %s не найдена: %v", name, err)
+		}
+	}
+}
+
+func TestPostUploadCommand(t *testing.T) {
+	requireCommands(t, "cp", "false")
+
+	tests := []struct {
+		name    string
+		command []string
+		marker  bool
+	}{
+		{"успешная команда", []string{"cp", PostUploadPathPlaceholder, PostUploadPathPlaceholder + ".done"}, true},
+		{"команда с ошибкой", []string{"false"}, false},
+		{"программа не найдена", []string{"httpBinaryClient-missing-command"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				UploadDir:         dir,
+				PostUploadCommand: tt.command,
+				Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			// Ошибка команды не влияет на ответ: файл уже сохранен
+			content := []byte("post upload")
+			if code := upload(t, s, "a.bin", content); code != http.StatusOK {
+				t.Fatalf("Ожидался статус 200, получен %d", code)
+			}
+			s.webhooks.Wait()
+
+			data, err := os.ReadFile(filepath.Join(dir, "a.bin.done"))
+			if tt.marker {
+				if err != nil || string(data) != string(content) {
+					t.Errorf("Команда не выполнена для сохраненного файла: %q, %v", data, err)
+				}
+			} else if err == nil {
+				t.Error("Файл-маркер не ожидался")
+			}
+		})
+	}
+}
+
+func TestPostUploadCommand_Timeout(t *testing.T) {
+	requireCommands(t, "sleep")
+
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:           NewMemoryStorageBackend(),
+		PostUploadCommand: []string{"sleep", "5"},
+		PostUploadTimeout: 50 * time.Millisecond,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	start := time.Now()
+	if err := s.execPostUpload("a.bin"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ожидалась ошибка таймаута, получено %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Команда не прервана по таймауту: %v", elapsed)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	var b limitedBuffer
+	b.Write([]byte(strings.Repeat("a", maxCommandOutput-1)))
+	if n, err := b.Write([]byte("bcd")); n != 3 || err != nil {
+		t.Fatalf("Write должен принимать весь вывод: %d, %v", n, err)
+	}
+	if got := b.String(); len(got) != maxCommandOutput+3 || !strings.HasSuffix(got, "ab...") {
+		t.Errorf("Неверный усеченный вывод: %d байт, окончание %q", len(got), got[len(got)-5:])
+	}
+}
+
+func TestValidatePostUploadCommand(t *testing.T) {
+	config := &ServerConfig{PostUploadCommand: []string{""}}
+	if err := config.validate(); err == nil {
+		t.Error("Ожидалась ошибка для пустой программы")
+	}
+}
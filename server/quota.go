@@ -0,0 +1,166 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// quotaWarnRatio доля квоты, после которой сервер предупреждает о заполнении хранилища
+const quotaWarnRatio = 0.9
+
+// errQuotaExceeded возвращается, если загрузка не помещается в квоту хранилища
+var errQuotaExceeded = errors.New("превышена квота хранилища")
+
+// storageQuota ограничивает суммарный размер файлов в директории загрузки.
+// Занятое место складывается из файлов на диске и резервов незавершенных загрузок,
+// поэтому одновременные запросы не могут вместе превысить квоту
+type storageQuota struct {
+	dir   string
+	limit int64
+
+	mu       sync.Mutex
+	disk     int64 // Размер директории при последнем подсчете
+	reserved int64 // Байты, зарезервированные незавершенными загрузками
+}
+
+// newStorageQuota создает квоту limit байт для директории dir (nil при limit <= 0)
+func newStorageQuota(dir string, limit int64) *storageQuota {
+	if limit <= 0 {
+		return nil
+	}
+	return &storageQuota{dir: dir, limit: limit}
+}
+
+// quotaReservation место, зарезервированное одной загрузкой
+type quotaReservation struct {
+	quota *storageQuota
+	n     int64
+}
+
+// reserve пересчитывает размер директории и резервирует n байт.
+// Возвращает резерв и занятое с его учетом место
+func (q *storageQuota) reserve(n int64) (*quotaReservation, int64, error) {
+	if q == nil {
+		return nil, 0, nil
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	disk, err := dirSize(q.dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	q.disk = disk
+	if q.disk+q.reserved+n > q.limit {
+		return nil, q.disk + q.reserved, errQuotaExceeded
+	}
+	q.reserved += n
+	return &quotaReservation{quota: q, n: n}, q.disk + q.reserved, nil
+}
+
+// empty возвращает пустой резерв, расширяемый через cover (nil без квоты)
+func (q *storageQuota) empty() *quotaReservation {
+	if q == nil {
+		return nil
+	}
+	return &quotaReservation{quota: q}
+}
+
+// cover расширяет резерв до size байт, если принято больше, чем было зарезервировано
+// (размер файла заранее неизвестен или данные сжаты). Директория не пересчитывается
+func (r *quotaReservation) cover(size int64) error {
+	if r == nil || size <= r.n {
+		return nil
+	}
+
+	q := r.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delta := size - r.n
+	if q.disk+q.reserved+delta > q.limit {
+		return errQuotaExceeded
+	}
+	q.reserved += delta
+	r.n = size
+	return nil
+}
+
+// release освобождает резерв после завершения загрузки
+func (r *quotaReservation) release() {
+	if r == nil {
+		return
+	}
+	r.quota.mu.Lock()
+	r.quota.reserved -= r.n
+	r.quota.mu.Unlock()
+	r.n = 0
+}
+
+// dirSize возвращает суммарный размер файлов в директории и ее поддиректориях.
+// Временные файлы незавершенных загрузок не учитываются: их место покрывают резервы.
+// Содержимое с несколькими жесткими ссылками (индекс дедупликации, версии) учитывается один раз
+func dirSize(dir string) (int64, error) {
+	var total int64
+	seen := make(map[fileID]struct{})
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp.") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil // Файл удален во время обхода
+			}
+			return err
+		}
+		// Жесткие ссылки (индекс дедупликации, версии) занимают место один раз
+		if nlink, id, ok := fileLinks(info); ok && nlink > 1 {
+			if _, counted := seen[id]; counted {
+				return nil
+			}
+			seen[id] = struct{}{}
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// reserveQuota резервирует n байт квоты для запроса. Если место закончилось,
+// отвечает 507 и возвращает false. При заполнении квоты на 90% пишет предупреждение
+func (s *HTTPServer) reserveQuota(w http.ResponseWriter, r *http.Request, n int64) (*quotaReservation, bool) {
+	reservation, used, err := s.quota.reserve(n)
+	switch {
+	case errors.Is(err, errQuotaExceeded):
+		s.httpError(w, r, fmt.Sprintf("Недостаточно места: квота хранилища %s, занято %s",
+			formatBytes(s.quota.limit), formatBytes(used)), http.StatusInsufficientStorage)
+		return nil, false
+	case err != nil:
+		s.httpError(w, r, fmt.Sprintf("Ошибка проверки квоты хранилища: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if s.quota != nil && float64(used) >= quotaWarnRatio*float64(s.quota.limit) {
+		s.logger().Warn("Хранилище почти заполнено",
+			"used", formatBytes(used),
+			"quota", formatBytes(s.quota.limit))
+	}
+	return reservation, true
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandleUpload_StorageQuota(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "existing.bin"), make([]byte, 6000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:         dir,
+		StorageQuotaBytes: 10000,
+		Logger:            slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	if code := upload(t, s, "first.bin", make([]byte, 3000)); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if !strings.Contains(logs.String(), "Хранилище почти заполнено") {
+		t.Error("Ожидалось предупреждение о заполнении квоты на 90%")
+	}
+
+	if code := upload(t, s, "second.bin", make([]byte, 3000)); code != http.StatusInsufficientStorage {
+		t.Fatalf("Ожидался статус 507, получен %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "second.bin")); !os.IsNotExist(err) {
+		t.Error("Файл сверх квоты сохранен")
+	}
+}
+
+func TestHandleUpload_StorageQuotaUnknownSize(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:         dir,
+		StorageQuotaBytes: 1000,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Без Content-Length квота проверяется по мере приема данных
+	req := newUploadRequest(t, "big.bin", make([]byte, 4000))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+
+	if rec.Code != http.StatusInsufficientStorage {
+		t.Fatalf("Ожидался статус 507, получен %d", rec.Code)
+	}
+	if size, _ := dirSize(dir); size != 0 {
+		t.Errorf("После отклоненной загрузки в директории осталось %d байт", size)
+	}
+}
+
+func TestStorageQuota_Reservations(t *testing.T) {
+	q := newStorageQuota(t.TempDir(), 1000)
+
+	first, _, err := q.reserve(600)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	// Одновременная загрузка учитывает резерв первой
+	if _, _, err := q.reserve(600); !errors.Is(err, errQuotaExceeded) {
+		t.Fatalf("Ожидалась ошибка квоты, получено %v", err)
+	}
+	if err := first.cover(900); err != nil {
+		t.Fatalf("Неожиданная ошибка расширения резерва: %v", err)
+	}
+	if err := first.cover(1100); !errors.Is(err, errQuotaExceeded) {
+		t.Fatalf("Ожидалась ошибка квоты при расширении, получено %v", err)
+	}
+
+	first.release()
+	if _, _, err := q.reserve(1000); err != nil {
+		t.Errorf("После освобождения резерва ожидался успех: %v", err)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "a.bin"), make([]byte, 100), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b.bin"), make([]byte, 50), 0644)
+	os.WriteFile(filepath.Join(dir, ".tmp.0123.c.bin"), make([]byte, 1000), 0644)
+
+	size, err := dirSize(dir)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	if size != 150 {
+		t.Errorf("Ожидалось 150 байт, получено %d", size)
+	}
+
+	if size, err := dirSize(filepath.Join(dir, "missing")); err != nil || size != 0 {
+		t.Errorf("Для отсутствующей директории ожидалось 0 без ошибки, получено %d, %v", size, err)
+	}
+}
@@ -1,126 +1,734 @@
 package server
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// RelativePathHeader заголовок с относительным путем файла для сохранения структуры директорий
+const RelativePathHeader = "X-Relative-Path"
+
 // ProgressCallback функция для отслеживания прогресса приема
 type ProgressCallback func(bytesReceived, totalBytes int64, percentage float64)
 
+// APIKeyHeader заголовок для передачи API-ключа
+const APIKeyHeader = "X-API-Key"
+
+// multipartOverhead допустимый объем служебных данных multipart сверх размера файла
+const multipartOverhead = 64 * 1024
+
+// Политики обработки коллизий имен файлов
+const (
+	CollisionOverwrite = "overwrite" // Перезаписать существующий файл
+	CollisionSkip      = "skip"      // Оставить существующий файл, новый отбросить
+	CollisionRename    = "rename"    // Сохранить новый файл под именем name_N.ext
+)
+
+// maxRenameAttempts ограничивает перебор имен для политики rename
+const maxRenameAttempts = 10000
+
+// ServerConfig конфигурация сервера
+type ServerConfig struct {
+	Port             string
+	ListenAddr       string // Адрес интерфейса для Port, например 127.0.0.1 (пусто — все интерфейсы); адрес с портом заменяет Port
+	SocketPath       string // Путь Unix-сокета; если задан, сервер слушает его вместо TCP-порта
+	AuthToken        string // Если задан, запросы на загрузку должны содержать этот токен
+	MaxFileSizeBytes int64  // Максимальный размер файла (0 — без ограничения)
+	UploadDir        string // Директория для сохранения файлов
+	CollisionPolicy  string // overwrite, skip или rename
+	EnableMetrics    bool   // Включить эндпоинт GET /metrics в формате Prometheus
+	TracingEnabled   bool   // Создавать span OpenTelemetry для каждого запроса на загрузку
+	AllowDelete      bool   // Разрешить удаление файлов через DELETE /files/{filename}
+
+	// UploadPathTemplate путь сохраняемого файла относительно UploadDir с маркерами {year},
+	// {month}, {day}, {hour} (время приема запроса), {filename} (имя или относительный путь
+	// файла) и {ext} (расширение без точки). Например, {year}/{month}/{day}/{filename}
+	// сохраняет файл в uploads/2025/06/15/report.pdf. Пусто — {filename}
+	UploadPathTemplate string
+
+	// AllowClientPath разрешает клиенту выбрать поддиректорию UploadDir заголовком PathHeaderName
+	// (пусто — X-Upload-Path), например отдельную директорию для каждого арендатора. Путь очищается
+	// и не может выйти за пределы UploadDir; без AllowClientPath заголовок игнорируется
+	AllowClientPath bool
+	PathHeaderName  string
+
+	// ServeUploads раздает файлы UploadDir по GET {ServeUploadsPrefix}{путь} (пусто — /files/),
+	// чтобы скачать загруженный файл браузером или curl. Листинг директорий запрещен.
+	// Файлы читаются из UploadDir, поэтому пользовательский Backend не раздается
+	ServeUploads       bool
+	ServeUploadsPrefix string
+
+	// CorrelationIDHeader заголовок с идентификатором корреляции, который клиент передает
+	// во всех попытках одной загрузки (пусто — X-Correlation-ID). Идентификатор записывается
+	// в лог загрузки, возвращается в том же заголовке ответа и в поле correlation_id JSON-ответа
+	CorrelationIDHeader string
+
+	// StorageQuotaBytes максимальный суммарный размер файлов в UploadDir (0 — без ограничения).
+	// Загрузка, которая не помещается в квоту, отклоняется со статусом 507
+	StorageQuotaBytes int64
+
+	// EnableVersioning при перезаписи сохраняет прежний файл как {name}.v{timestamp}{ext}.
+	// Версии доступны через GET /files/{filename}/versions. Действует для политики overwrite
+	EnableVersioning bool
+
+	// DeduplicateByHash хранит одинаковое содержимое один раз: повторно загруженный файл
+	// становится жесткой ссылкой на уже сохраненный, а ответ содержит {"duplicate": true}
+	DeduplicateByHash bool
+
+	TLSCertFile string // Сертификат сервера в формате PEM; если задан, сервер принимает HTTPS
+	TLSKeyFile  string // Закрытый ключ сертификата сервера
+	ClientCA    string // Сертификат CA в формате PEM; если задан, сервер требует сертификат клиента (mTLS)
+
+	// AllowedMIMETypes типы содержимого, которые сервер принимает (пусто — любые).
+	// Тип определяется по первым 512 байтам файла через http.DetectContentType,
+	// а не по заголовкам клиента; допускаются шаблоны вида image/*
+	AllowedMIMETypes []string
+
+	// DetectContentType определяет тип содержимого каждого файла тем же способом, сохраняет его
+	// в метаданных файла (content_type) и возвращает в JSON-ответе на загрузку
+	DetectContentType bool
+
+	// AllowedIPs адреса и подсети CIDR, которым разрешен доступ (пусто — всем).
+	// BlockedIPs запрещенные адреса и подсети; блокировка имеет приоритет над разрешением
+	AllowedIPs []string
+	BlockedIPs []string
+	TrustProxy bool // Определять адрес клиента по X-Forwarded-For (только за доверенным прокси)
+
+	// TrustedProxies адреса и подсети CIDR промежуточных прокси, которые при TrustProxy
+	// пропускаются в X-Forwarded-For справа налево (пусто — доверяется только последнему значению)
+	TrustedProxies []string
+
+	// CORS разрешает запросы из браузера со страниц других источников, например загрузку
+	// из веб-интерфейса на отдельном домене (пустой AllowedOrigins — CORS выключен)
+	CORS CORSConfig
+
+	// MaxConcurrentUploadsPerIP наибольшее число одновременных загрузок с одного адреса клиента
+	// (0 — без ограничения). Лишние запросы отклоняются со статусом 429 и Retry-After: 1
+	MaxConcurrentUploadsPerIP int
+
+	// ReadHeaderTimeout время на чтение заголовков запроса (0 — без ограничения).
+	// BodyReadTimeout время на чтение тела одного запроса на загрузку (0 — без ограничения):
+	// защищает от клиентов, передающих данные по байту (slow-loris), не ограничивая
+	// остальные запросы, как это сделал бы http.Server.ReadTimeout. Медленный клиент
+	// получает статус 408
+	ReadHeaderTimeout time.Duration
+	BodyReadTimeout   time.Duration
+
+	// ETAWarmupPeriod время от начала приема, в течение которого оставшееся время
+	// в логе прогресса не оценивается: скорость в начале передачи занижена медленным стартом TCP
+	ETAWarmupPeriod time.Duration
+
+	// EMAAlpha коэффициент экспоненциального сглаживания скорости для оценки оставшегося
+	// времени, от 0 до 1: чем меньше, тем стабильнее оценка и медленнее реакция на смену скорости
+	EMAAlpha float64
+
+	// VerifyCRC32C сверяет CRC32C принятого файла со значением из заголовка X-Content-CRC32C.
+	// При несовпадении файл не сохраняется, а клиент получает статус 422
+	VerifyCRC32C bool
+
+	// IdempotencyTTL время, в течение которого повторный запрос на загрузку с тем же
+	// заголовком Idempotency-Key получает сохраненный ответ без повторной обработки
+	// (0 — заголовок игнорируется). IdempotencyCacheSize число хранимых ключей (0 — 1000)
+	IdempotencyTTL       time.Duration
+	IdempotencyCacheSize int
+
+	// ChecksumCacheTTL время, в течение которого GET /files/{filename}/checksum
+	// отдает ранее вычисленную сумму, не перечитывая файл (0 — без кэша).
+	// Загрузка и удаление файла сбрасывают его суммы досрочно
+	ChecksumCacheTTL time.Duration
+
+	// ComputeHashOnUpload вычисляет контрольную сумму принимаемого файла (none, md5 или sha256;
+	// пусто — none) и возвращает ее в JSON-ответе на загрузку в полях checksum и checksum_algorithm,
+	// чтобы клиент мог сверить ее с локальной. Сумма вычисляется по распакованным данным
+	ComputeHashOnUpload HashAlgorithm
+
+	// AuditLogPath файл журнала аудита (пусто — журнал не ведется): после каждого запроса
+	// на загрузку, успешного или нет, в него дописывается строка JSON с AuditRecord.
+	// Файл больше AuditLogMaxSizeMB (0 — 100MB) переименовывается в {AuditLogPath}.{timestamp}
+	AuditLogPath      string
+	AuditLogMaxSizeMB int
+
+	// WebhookURL адрес, на который после успешной загрузки отправляется POST с UploadEvent.
+	// Тело подписывается HMAC-SHA256 с ключом WebhookSecret в заголовке X-Signature
+	WebhookURL    string
+	WebhookSecret string
+
+	// PostUploadCommand команда, запускаемая после каждой успешной загрузки, например
+	// ["clamscan", "{path}"]; {path} в аргументах заменяется путем к сохраненному файлу
+	// (для хранилищ, кроме LocalStorageBackend, — именем файла в хранилище). Команда выполняется
+	// в фоне не дольше PostUploadTimeout (0 — 5m), ее вывод и ошибка записываются в лог
+	// и не влияют на ответ клиенту
+	PostUploadCommand []string
+	PostUploadTimeout time.Duration
+
+	// ChunkDir директория для частей файлов, загружаемых через /upload/chunk
+	// (по умолчанию httpBinaryClient-chunks во временной директории ОС)
+	ChunkDir string
+
+	// ChunkSessionTTL время без новых частей, после которого сессия загрузки по частям
+	// удаляется вместе с частями и освобождает место в квоте (0 — 24h, отрицательное — не удаляются)
+	ChunkSessionTTL time.Duration
+
+	// OrphanedFileTTL возраст, после которого временные файлы незавершенных загрузок
+	// в UploadDir и ChunkDir удаляются фоновой проверкой (0 — 24h, отрицательное — не удаляются).
+	// Такие файлы остаются только после аварийного завершения сервера
+	OrphanedFileTTL time.Duration
+
+	// Backend хранилище принятых файлов (nil — LocalStorageBackend в UploadDir
+	// с политикой CollisionPolicy). Пользовательское хранилище само отвечает за коллизии имен
+	Backend StorageBackend
+
+	Logger *slog.Logger // Логгер сервера (nil — slog.Default())
+}
+
+// DefaultServerConfig возвращает конфигурацию по умолчанию
+func DefaultServerConfig() *ServerConfig {
+	return &ServerConfig{
+		Port:              "8080",
+		UploadDir:         "uploads",
+		CollisionPolicy:   CollisionOverwrite,
+		ReadHeaderTimeout: 10 * time.Second,
+		ETAWarmupPeriod:   defaultETAWarmupPeriod,
+		EMAAlpha:          defaultEMAAlpha,
+		ChecksumCacheTTL:  5 * time.Minute,
+		AuditLogMaxSizeMB: defaultAuditLogMaxSizeMB,
+		OrphanedFileTTL:   defaultOrphanedFileTTL,
+		ChunkSessionTTL:   defaultChunkSessionTTL,
+	}
+}
+
+// validate проверяет корректность конфигурации
+func (c *ServerConfig) validate() error {
+	switch c.CollisionPolicy {
+	case "", CollisionOverwrite, CollisionSkip, CollisionRename:
+	default:
+		return fmt.Errorf("неизвестная политика коллизий: %s", c.CollisionPolicy)
+	}
+	switch c.ComputeHashOnUpload {
+	case "", HashNone, HashMD5, HashSHA256:
+	default:
+		return fmt.Errorf("неизвестный алгоритм ComputeHashOnUpload: %s", c.ComputeHashOnUpload)
+	}
+	if err := validatePathTemplate(c.UploadPathTemplate); err != nil {
+		return fmt.Errorf("UploadPathTemplate: %w", err)
+	}
+	if _, err := parseIPList(c.AllowedIPs); err != nil {
+		return fmt.Errorf("AllowedIPs: %w", err)
+	}
+	if _, err := parseIPList(c.BlockedIPs); err != nil {
+		return fmt.Errorf("BlockedIPs: %w", err)
+	}
+	if _, err := parseIPList(c.TrustedProxies); err != nil {
+		return fmt.Errorf("TrustedProxies: %w", err)
+	}
+	if err := c.CORS.validate(); err != nil {
+		return fmt.Errorf("CORS: %w", err)
+	}
+	if len(c.PostUploadCommand) > 0 && c.PostUploadCommand[0] == "" {
+		return fmt.Errorf("PostUploadCommand: не указана программа")
+	}
+	return nil
+}
+
 // HTTPServer HTTP-сервер для приема файлов
 type HTTPServer struct {
-	server *http.Server
-	port   string
+	mu       sync.Mutex // Защищает server и middlewares: Shutdown вызывается из другой горутины
+	server   *http.Server
+	port     string
+	config   *ServerConfig
+	storage  StorageBackend
+	metrics  *metrics
+	sessions sync.Map // Сессии загрузок: идентификатор -> *uploadSession
+	chunks   *chunkStore
+	quota    *storageQuota  // nil, если квота не задана
+	webhooks sync.WaitGroup // Недоставленные webhook и команды PostUploadCommand, которые ждет Shutdown
+
+	activeUploads atomic.Int64 // Запросы, обрабатываемые в handleUpload
+	ipUploads     sync.Map     // Загрузки по адресам клиентов: адрес -> *atomic.Int32
+	fileLocks     fileLocks    // Загрузки одного имени файла выполняются по очереди
+	startTime     time.Time
+	idempotency   *idempotencyCache // nil, если IdempotencyTTL не задан
+	checksums     *checksumCache    // nil, если ChecksumCacheTTL не задан
+	audit         *auditLog         // nil, если AuditLogPath не задан
+	orphans       *orphanCleaner    // nil, если OrphanedFileTTL отрицателен
+	middlewares   []Middleware      // Пользовательские middleware вокруг маршрутов (AddMiddleware)
+	static        http.Handler      // Раздача файлов при ServeUploads (nil, если выключена)
 }
 
 // NewHTTPServer создает новый HTTP-сервер
 func NewHTTPServer(port string) *HTTPServer {
-	return &HTTPServer{
-		port: port,
+	config := DefaultServerConfig()
+	config.Port = port
+	return NewHTTPServerWithConfig(config)
+}
+
+// NewHTTPServerWithConfig создает новый HTTP-сервер с кастомной конфигурацией
+func NewHTTPServerWithConfig(config *ServerConfig) *HTTPServer {
+	if config == nil {
+		config = DefaultServerConfig()
+	}
+	if config.UploadDir == "" {
+		config.UploadDir = "uploads"
+	}
+	if config.CollisionPolicy == "" {
+		config.CollisionPolicy = CollisionOverwrite
+	}
+	storage := config.Backend
+	if storage == nil {
+		local := NewLocalStorageBackend(config.UploadDir, config.CollisionPolicy)
+		local.Versioning = config.EnableVersioning
+		local.Deduplicate = config.DeduplicateByHash
+		storage = local
+	}
+	chunkDir := config.ChunkDir
+	if chunkDir == "" {
+		chunkDir = filepath.Join(os.TempDir(), "httpBinaryClient-chunks")
+	}
+	s := &HTTPServer{
+		port:    config.Port,
+		config:  config,
+		storage: storage,
+		metrics: newMetrics(),
+		quota:   newStorageQuota(config.UploadDir, config.StorageQuotaBytes),
+
+		startTime:   time.Now(),
+		idempotency: newIdempotencyCache(config.IdempotencyTTL, config.IdempotencyCacheSize),
+		checksums:   newChecksumCache(config.ChecksumCacheTTL),
+		audit:       newAuditLog(config.AuditLogPath, config.AuditLogMaxSizeMB),
+	}
+	s.chunks = newChunkStore(chunkDir, s.quota, config.ChunkSessionTTL, s.logger)
+	if config.ServeUploads {
+		s.static = s.staticFiles()
 	}
+	tempDirs := []string{chunkDir}
+	if local, ok := storage.(*LocalStorageBackend); ok {
+		tempDirs = append(tempDirs, local.Dir)
+	}
+	s.orphans = newOrphanCleaner(config.OrphanedFileTTL, s.logger, tempDirs...)
+	return s
 }
 
-// Start запускает HTTP-сервер
+// Start запускает HTTP-сервер. После Shutdown возвращает nil
 func (s *HTTPServer) Start() error {
+	if err := s.config.validate(); err != nil {
+		return err
+	}
+
+	tlsConfig, err := s.config.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	defer s.removeSocket()
+
+	s.orphans.start()
+	s.chunks.start()
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: s.config.ReadHeaderTimeout,
+	}
+	s.mu.Lock()
+	s.server = srv
+	s.mu.Unlock()
+
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	if s.config.SocketPath != "" {
+		s.logger().Info("Сервер запущен",
+			"socket", s.config.SocketPath,
+			"upload_url", fmt.Sprintf("%s://localhost/upload", scheme),
+			"client_auth", s.config.ClientCA != "")
+	} else {
+		s.logger().Info("Сервер запущен",
+			"addr", listener.Addr().String(),
+			"upload_url", uploadURL(scheme, listener.Addr()),
+			"client_auth", s.config.ClientCA != "")
+	}
+
+	if tlsConfig != nil {
+		// Сертификат уже загружен в TLSConfig
+		err = srv.ServeTLS(listener, "", "")
+	} else {
+		err = srv.Serve(listener)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler возвращает обработчик всех маршрутов сервера. Используется в Start
+// и позволяет встроить сервер в httptest.Server или другой mux
+func (s *HTTPServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Обработчик для загрузки файлов
-	mux.HandleFunc("/upload", s.handleUpload)
+	mux.HandleFunc("/upload", s.traced(s.audited(s.requireAuth(s.withIdempotency(s.handleUpload)))))
+	mux.HandleFunc("/upload/status/", s.requireAuth(s.handleUploadStatus))
+	mux.HandleFunc("/upload/chunk", s.requireAuth(s.handleChunk))
+	mux.HandleFunc("/upload/finalize", s.audited(s.requireAuth(s.handleFinalize)))
+
+	// Список сохраненных файлов
+	mux.HandleFunc("/files", s.requireAuth(s.handleFiles))
+	mux.HandleFunc("/files/", s.requireAuth(s.handleFile))
+
+	// Раздача загруженных файлов; по пути /files/ файлы отдает handleFile
+	if s.static != nil && s.serveUploadsPrefix() != DefaultServeUploadsPrefix {
+		mux.Handle(s.serveUploadsPrefix(), s.requireAuth(s.static.ServeHTTP))
+	}
+
+	// Состояние сервера: активные загрузки и время работы
+	mux.HandleFunc("/status", s.handleStatus)
+
+	// Ограничения загрузки для проверки на клиенте
+	mux.HandleFunc("/limits", s.handleLimits)
+
+	// Метрики в формате Prometheus
+	if s.config.EnableMetrics {
+		mux.HandleFunc("/metrics", s.handleMetrics)
+	}
 
 	// Простой обработчик для проверки работы сервера
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("HTTP File Upload Server is running"))
 	})
 
-	s.server = &http.Server{
-		Addr:    ":" + s.port,
-		Handler: mux,
+	// Фильтр IP-адресов — внешний слой: запрещенные клиенты не доходят до обработчиков
+	// и пользовательских middleware
+	return s.ipFilterMiddleware(corsMiddleware(s.corsConfig())(s.applyMiddlewares(mux)))
+}
+
+// Shutdown останавливает HTTP-сервер: перестает принимать новые соединения
+// и ждет завершения обрабатываемых запросов, пока ActiveUploads не станет равным нулю.
+// Если ctx истекает раньше, оставшиеся соединения закрываются принудительно
+// и возвращается ошибка контекста
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	var err error
+	if srv != nil {
+		err = srv.Shutdown(ctx)
+		if ctx.Err() != nil {
+			srv.Close()
+		}
+	}
+
+	// Handler мог быть встроен в другой сервер, поэтому загрузки ждем и без собственного
+	if waitErr := s.waitUploads(ctx); waitErr != nil && err == nil {
+		err = waitErr
 	}
 
-	fmt.Printf("Сервер запущен на порту %s\n", s.port)
-	fmt.Printf("Для загрузки файлов используйте: http://localhost:%s/upload\n", s.port)
+	// Даем отправиться webhook и выполниться командам о завершенных загрузках
+	delivered := make(chan struct{})
+	go func() {
+		s.webhooks.Wait()
+		close(delivered)
+	}()
+	select {
+	case <-delivered:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
 
-	return s.server.ListenAndServe()
+	s.orphans.close()
+	s.chunks.close()
+	if auditErr := s.audit.close(); auditErr != nil && err == nil {
+		err = auditErr
+	}
+	return err
 }
 
-// Stop останавливает HTTP-сервер
-func (s *HTTPServer) Stop() error {
-	if s.server != nil {
-		return s.server.Close()
+// logger возвращает логгер сервера
+func (s *HTTPServer) logger() *slog.Logger {
+	if s.config.Logger != nil {
+		return s.config.Logger
+	}
+	return slog.Default()
+}
+
+// httpError записывает ошибку обработки загрузки в лог, учитывает ее в метриках
+// и отправляет клиенту
+func (s *HTTPServer) httpError(w http.ResponseWriter, r *http.Request, msg string, status int) {
+	attrs := []any{
+		"path", r.URL.Path,
+		"remote_addr", r.RemoteAddr,
+		"status", status,
+		"error", msg,
+	}
+	if id := s.correlationID(r); id != "" {
+		attrs = append(attrs, "correlation_id", id)
+	}
+	s.logger().Error("Ошибка обработки запроса", attrs...)
+	s.metrics.observeError(status)
+	recordSpanError(r, msg, status)
+	recordAuditError(r, msg)
+	http.Error(w, msg, status)
+}
+
+// requestError отвечает клиенту ошибкой на запрос, не связанный с приемом файла
+// (список файлов, метаданные, удаление и т.п.). В отличие от httpError, ошибка
+// не учитывается в метриках загрузок, а ошибки клиента (4xx) пишутся в лог
+// с уровнем Warn
+func (s *HTTPServer) requestError(w http.ResponseWriter, r *http.Request, msg string, status int) {
+	attrs := []any{
+		"path", r.URL.Path,
+		"remote_addr", r.RemoteAddr,
+		"status", status,
+		"error", msg,
+	}
+	if id := s.correlationID(r); id != "" {
+		attrs = append(attrs, "correlation_id", id)
+	}
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	s.logger().Log(r.Context(), level, "Ошибка обработки запроса", attrs...)
+	recordSpanError(r, msg, status)
+	http.Error(w, msg, status)
+}
+
+// requireAuth проверяет токен запроса, если он задан в конфигурации
+func (s *HTTPServer) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config.AuthToken != "" && !s.validToken(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.httpError(w, r, "Требуется аутентификация", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
 	}
-	return nil
+}
+
+// validToken сравнивает токен из заголовков Authorization или X-API-Key с ожидаемым
+func (s *HTTPServer) validToken(r *http.Request) bool {
+	token := r.Header.Get(APIKeyHeader)
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) == 1
 }
 
 // handleUpload обрабатывает загрузку файлов
 func (s *HTTPServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	defer s.trackUpload()()
+
 	if r.Method != "POST" {
-		http.Error(w, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		s.httpError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Парсим multipart форму
-	err := r.ParseMultipartForm(32 << 20) // 32MB max memory
+	release, ok := s.acquireUploadSlot(r)
+	if !ok {
+		w.Header().Set("Retry-After", "1")
+		s.httpError(w, r, fmt.Sprintf("Слишком много одновременных загрузок с адреса клиента (не более %d)", s.config.MaxConcurrentUploadsPerIP), http.StatusTooManyRequests)
+		return
+	}
+	defer release()
+
+	defer s.limitBodyReadTime(w, r)()
+
+	// Сессия позволяет отслеживать загрузку через GET /upload/status/{sessionID}
+	sessionID, session, err := s.startSession()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Ошибка парсинга формы: %v", err), http.StatusBadRequest)
+		s.httpError(w, r, fmt.Sprintf("Ошибка создания сессии загрузки: %v", err), http.StatusInternalServerError)
 		return
 	}
+	defer s.endSession(sessionID, session)
+	w.Header().Set(SessionIDHeader, sessionID)
+	correlationID := s.correlationID(r)
+	if correlationID != "" {
+		w.Header().Set(s.correlationIDHeader(), correlationID)
+	}
 
-	// Получаем файл из формы
-	file, header, err := r.FormFile("file")
+	// Размер файла, объявленный клиентом; multipart-запрос часто передается без Content-Length
+	declaredSize, err := parseFileSizeHeader(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Ошибка получения файла: %v", err), http.StatusBadRequest)
+		s.httpError(w, r, fmt.Sprintf("Некорректный заголовок %s: %v", FileSizeHeader, err), http.StatusBadRequest)
+		return
+	}
+	if !s.checkUploadMetadata(w, r) {
 		return
 	}
-	defer file.Close()
 
-	// Создаем директорию для сохранения файлов
-	uploadDir := "uploads"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		http.Error(w, fmt.Sprintf("Ошибка создания директории: %v", err), http.StatusInternalServerError)
+	// Отклоняем заведомо слишком большие запросы до чтения данных
+	maxFileSize := s.config.MaxFileSizeBytes
+	if maxFileSize > 0 {
+		if declaredSize > maxFileSize {
+			s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if r.ContentLength > maxFileSize+multipartOverhead {
+			s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+multipartOverhead)
+	}
+
+	// Резервируем место под запрос; если размер неизвестен, резерв растет по мере приема
+	reservation, ok := s.reserveQuota(w, r, r.ContentLength)
+	if !ok {
 		return
 	}
+	defer reservation.release()
 
-	// Создаем файл для сохранения
-	filePath := filepath.Join(uploadDir, header.Filename)
-	dst, err := os.Create(filePath)
+	// Форма читается потоком до части с файлом: файл не буферизуется в памяти
+	// или во временном файле, а сразу передается в хранилище
+	form, err := readUploadForm(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Ошибка создания файла: %v", err), http.StatusInternalServerError)
+		msg, status := s.uploadFormError(err)
+		s.httpError(w, r, msg, status)
 		return
 	}
-	defer dst.Close()
+	formFile := form.file
+	defer formFile.Close()
+	session.setFilename(form.filename)
+	auditFile(r, form.filename, 0, nil)
 
-	// Получаем размер файла (если доступен)
-	contentLength := r.ContentLength
-	if contentLength <= 0 {
-		// Если размер не известен, попробуем получить из заголовка
-		if header.Size > 0 {
-			contentLength = header.Size
+	// Сжатое клиентом содержимое распаковываем на лету
+	var file io.Reader = formFile
+	compressed := r.Header.Get("Content-Encoding") == "gzip"
+	if compressed {
+		gz, err := gzip.NewReader(formFile)
+		if err != nil {
+			s.httpError(w, r, fmt.Sprintf("Ошибка распаковки файла: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		file = gz
+	}
+
+	// Тип проверяется до записи в хранилище, поэтому отклоненный файл не сохраняется
+	file, contentType, err := s.checkMIMEType(file)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, errMIMETypeNotAllowed) {
+			status = http.StatusUnsupportedMediaType
+		}
+		s.httpError(w, r, fmt.Sprintf("Файл отклонен: %v", err), status)
+		return
+	}
+
+	// Контрольная сумма сверяется по распакованным данным до фиксации файла в хранилище
+	file, err = s.verifyCRC32C(r, file)
+	if err != nil {
+		s.httpError(w, r, fmt.Sprintf("Некорректный заголовок %s: %v", ContentCRC32CHeader, err), http.StatusBadRequest)
+		return
+	}
+
+	// Определяем имя файла в хранилище с учетом структуры директорий клиента
+	relPath := sanitizeFilename(form.filename)
+	if headerPath := r.Header.Get(RelativePathHeader); headerPath != "" {
+		relPath, err = cleanRelativePath(headerPath)
+		if err != nil {
+			s.httpError(w, r, fmt.Sprintf("Некорректный относительный путь: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	storageName, err := expandPathTemplate(s.config.UploadPathTemplate, relPath, time.Now())
+	if err != nil {
+		s.httpError(w, r, fmt.Sprintf("Ошибка построения пути файла: %v", err), http.StatusInternalServerError)
+		return
+	}
+	storageName, err = s.withClientUploadDir(r, storageName)
+	if err != nil {
+		s.httpError(w, r, fmt.Sprintf("Некорректная директория загрузки: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	recordIdempotentName(r, storageName)
+
+	// Параллельная загрузка того же имени ждет завершения текущей
+	defer s.fileLocks.lock(storageName)()
+
+	// Клиент с ConditionalUpload передает SHA-256 файла в If-None-Match: неизмененный файл не перезаписывается
+	if etag, ok := s.notModified(r, storageName); ok {
+		// Как и при политике skip, тело дочитывается, чтобы клиент получил ответ
+		io.Copy(io.Discard, r.Body)
+		session.finish(SessionComplete)
+		s.logger().Info("Файл не изменился, загрузка пропущена", "path", storageName, "remote_addr", r.RemoteAddr)
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// При политике skip не принимаем файл, который все равно будет отброшен
+	if s.config.CollisionPolicy == CollisionSkip {
+		exists, err := s.storage.Exists(storageName)
+		if err != nil {
+			s.httpError(w, r, fmt.Sprintf("Ошибка проверки файла: %v", err), http.StatusInternalServerError)
+			return
 		}
+		if exists {
+			// Клиент считает загрузку неудачной, если соединение закроется до отправки всего
+			// тела, поэтому файл дочитывается без сохранения (с учетом лимита размера запроса)
+			io.Copy(io.Discard, r.Body)
+			session.finish(SessionComplete)
+			s.logger().Info("Файл уже существует, загрузка пропущена", "path", storageName, "remote_addr", r.RemoteAddr)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf("Файл %s уже существует, загрузка пропущена", storageName)))
+			return
+		}
+	}
+
+	// Получаем размер файла (если доступен); форма читается потоком, поэтому
+	// точный размер части с файлом известен только из X-File-Size
+	contentLength := r.ContentLength
+	if compressed {
+		// Размер распакованных данных заранее неизвестен
+		contentLength = 0
+	}
+	if declaredSize > 0 {
+		// Объявленный размер точнее: Content-Length включает разметку формы
+		contentLength = declaredSize
 	}
 
 	// Время начала загрузки
 	startTime := time.Now()
 
-	fmt.Printf("\n=== НАЧАЛО ЗАГРУЗКИ ===\n")
-	fmt.Printf("Файл: %s\n", header.Filename)
-	fmt.Printf("Размер: %s\n", formatBytes(contentLength))
-	fmt.Printf("Время начала: %s\n", startTime.Format("15:04:05"))
-	fmt.Printf("IP клиента: %s\n", r.RemoteAddr)
-	fmt.Printf("User-Agent: %s\n", r.UserAgent())
-	fmt.Printf("========================\n\n")
+	logger := s.logger().With("file", form.filename, "remote_addr", r.RemoteAddr, "session_id", sessionID)
+	if correlationID != "" {
+		logger = logger.With("correlation_id", correlationID)
+	}
+	logger.Info("Начало загрузки",
+		"size", formatBytes(contentLength),
+		"user_agent", r.UserAgent())
 
 	// Создаем прогресс-бар с дополнительной информацией
 	var mu sync.Mutex
 	var lastUpdate time.Time
-	var bytesReceived int64
-	var lastBytesReceived int64
-	var lastUpdateTime time.Time
+	meter := newSpeedMeter(startTime, s.config.ETAWarmupPeriod, s.config.EMAAlpha)
 
 	progressCallback := func(bytesReceived, totalBytes int64, percentage float64) {
 		mu.Lock()
@@ -130,70 +738,85 @@ func (s *HTTPServer) handleUpload(w http.ResponseWriter, r *http.Request) {
 
 		// Обновляем прогресс не чаще чем раз в секунду
 		if now.Sub(lastUpdate) >= time.Second {
-			// Вычисляем скорость передачи
-			timeDiff := now.Sub(lastUpdateTime).Seconds()
-			if timeDiff > 0 {
-				bytesDiff := bytesReceived - lastBytesReceived
-				speed := float64(bytesDiff) / timeDiff
-
-				// Вычисляем оставшееся время
-				var eta string
-				if speed > 0 && totalBytes > bytesReceived {
-					remainingBytes := totalBytes - bytesReceived
-					etaSeconds := float64(remainingBytes) / speed
-					eta = formatDuration(time.Duration(etaSeconds) * time.Second)
-				} else {
-					eta = "вычисляется..."
-				}
-
-				// Вычисляем прошедшее время
-				elapsed := now.Sub(startTime)
-
-				fmt.Printf("\r[%s] Прием: %.2f%% (%s / %s) | Скорость: %s/s | Прошло: %s | Осталось: %s",
-					now.Format("15:04:05"),
-					percentage,
-					formatBytes(bytesReceived),
-					formatBytes(totalBytes),
-					formatBytes(int64(speed)),
-					formatDuration(elapsed),
-					eta)
-
-				lastUpdate = now
-				lastBytesReceived = bytesReceived
-				lastUpdateTime = now
-			}
-		}
-	}
-
-	// Буфер для чтения данных
-	buffer := make([]byte, 64*1024) // 64KB буфер
+			// Скорость усредняется по последним секундам передачи
+			meter.add(now, bytesReceived)
+			speed := meter.speed()
 
-	// Читаем и записываем файл по частям
-	for {
-		n, err := file.Read(buffer)
-		if n > 0 {
-			_, writeErr := dst.Write(buffer[:n])
-			if writeErr != nil {
-				http.Error(w, fmt.Sprintf("Ошибка записи файла: %v", writeErr), http.StatusInternalServerError)
-				return
+			// Оставшееся время не показываем, пока скорость не установится, и оцениваем
+			// по сглаженной скорости, чтобы оно не скакало от обновления к обновлению
+			eta := "вычисляется..."
+			if remaining, ok := meter.eta(now, bytesReceived, totalBytes); ok {
+				eta = formatDuration(remaining)
 			}
 
-			bytesReceived += int64(n)
+			logger.Debug("Прием",
+				"percentage", fmt.Sprintf("%.2f", percentage),
+				"received", formatBytes(bytesReceived),
+				"total", formatBytes(totalBytes),
+				"speed", formatBytes(int64(speed))+"/s",
+				"elapsed", formatDuration(now.Sub(startTime)),
+				"eta", eta)
 
-			// Вызываем callback для отображения прогресса
-			if contentLength > 0 {
-				percentage := float64(bytesReceived) / float64(contentLength) * 100
-				progressCallback(bytesReceived, contentLength, percentage)
-			}
+			lastUpdate = now
 		}
+	}
 
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Ошибка чтения файла: %v", err), http.StatusInternalServerError)
-			return
-		}
+	// Передаем файл в хранилище, считая принятые байты и проверяя лимит размера
+	file, hasher := s.uploadHasher(file)
+	file, checksum := s.uploadChecksum(file)
+	body := &uploadReader{r: file, limit: maxFileSize, total: contentLength, declared: declaredSize, progress: progressCallback, session: session, quota: reservation}
+	metadata := map[string]string{
+		MetadataOriginalName: form.filename,
+		MetadataContentType:  formFile.Header.Get("Content-Type"),
+		MetadataRemoteAddr:   r.RemoteAddr,
+	}
+	bytesReceived, err := s.storage.Save(r.Context(), storageName, body, metadata)
+	auditFile(r, storageName, bytesReceived, nil)
+	switch {
+	case errors.Is(err, errFileTooLarge):
+		s.httpError(w, r, fmt.Sprintf("Размер файла превышает лимит %s", formatBytes(maxFileSize)), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, errQuotaExceeded):
+		s.httpError(w, r, fmt.Sprintf("Недостаточно места: квота хранилища %s", formatBytes(s.config.StorageQuotaBytes)), http.StatusInsufficientStorage)
+		return
+	case errors.Is(err, errSizeMismatch):
+		s.httpError(w, r, fmt.Sprintf("Файл отклонен: %v", err), http.StatusBadRequest)
+		return
+	case errors.Is(err, errChecksumMismatch):
+		s.httpError(w, r, fmt.Sprintf("Файл поврежден при передаче: %v", err), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, errBodyReadTimeout), isMaxBytesError(err):
+		msg, status := s.uploadFormError(err)
+		s.httpError(w, r, msg, status)
+		return
+	case errors.Is(err, ErrReservedName):
+		s.httpError(w, r, fmt.Sprintf("Файл отклонен: %v", err), http.StatusBadRequest)
+		return
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// Тело запроса оборвалось посреди файла
+		s.httpError(w, r, fmt.Sprintf("Файл получен не полностью: %v", err), http.StatusBadRequest)
+		return
+	case errors.Is(err, ErrFileExists):
+		session.finish(SessionComplete)
+		logger.Info("Файл уже существует, загрузка пропущена", "path", storageName)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf("Файл %s уже существует, загрузка пропущена", storageName)))
+		return
+	case err != nil:
+		s.httpError(w, r, fmt.Sprintf("Не удалось сохранить файл: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	storedName := storageName
+	if name := metadata[MetadataStoredName]; name != "" {
+		storedName = name
+	}
+	recordIdempotentName(r, storedName)
+
+	// Метаданные из заголовков X-Meta-* сохраняются рядом с файлом
+	if err := s.saveUploadMetadata(r, storedName, contentType); err != nil {
+		s.httpError(w, r, fmt.Sprintf("Файл сохранен, но не удалось сохранить метаданные: %v", err), http.StatusInternalServerError)
+		return
 	}
 
 	// Время окончания загрузки
@@ -206,19 +829,199 @@ func (s *HTTPServer) handleUpload(w http.ResponseWriter, r *http.Request) {
 		avgSpeed = float64(bytesReceived) / totalDuration.Seconds()
 	}
 
-	fmt.Printf("\n\n=== ЗАГРУЗКА ЗАВЕРШЕНА ===\n")
-	fmt.Printf("Файл: %s\n", header.Filename)
-	fmt.Printf("Путь сохранения: %s\n", filePath)
-	fmt.Printf("Размер принятых данных: %s\n", formatBytes(bytesReceived))
-	fmt.Printf("Время начала: %s\n", startTime.Format("15:04:05"))
-	fmt.Printf("Время окончания: %s\n", endTime.Format("15:04:05"))
-	fmt.Printf("Общее время: %s\n", formatDuration(totalDuration))
-	fmt.Printf("Средняя скорость: %s/s\n", formatBytes(int64(avgSpeed)))
-	fmt.Printf("==========================\n\n")
+	session.finish(SessionComplete)
+	s.checksums.invalidate(storedName)
+	auditFile(r, storedName, bytesReceived, hasher)
+	s.metrics.observeUpload(bytesReceived, totalDuration)
+	logger.Info("Загрузка завершена",
+		"path", storedName,
+		"size", formatBytes(bytesReceived),
+		"duration", formatDuration(totalDuration),
+		"avg_speed", formatBytes(int64(avgSpeed))+"/s")
+	s.notifyUpload(UploadEvent{
+		Filename:         form.filename,
+		SizeBytes:        bytesReceived,
+		SavedPath:        storedName,
+		UploadDurationMs: totalDuration.Milliseconds(),
+	}, hasher)
+	s.runPostUpload(storedName)
 
 	// Отправляем ответ клиенту
+	var sum string
+	if checksum != nil {
+		sum = hex.EncodeToString(checksum.Sum(nil))
+		s.checksums.put(FileChecksum{Filename: storedName, Algo: string(s.config.ComputeHashOnUpload), Checksum: sum, ComputedAt: time.Now()})
+	}
+	if metadata[MetadataDuplicate] == "true" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(duplicateResponse{
+			Duplicate:         true,
+			File:              storedName,
+			Checksum:          sum,
+			ChecksumAlgorithm: checksumAlgorithm(sum, s.config.ComputeHashOnUpload),
+			ContentType:       contentType,
+			CorrelationID:     correlationID,
+		})
+		return
+	}
+	message := fmt.Sprintf("Файл %s успешно загружен", form.filename)
+	if storedName != storageName {
+		message = fmt.Sprintf("Файл %s успешно загружен как %s", form.filename, path.Base(storedName))
+	}
+	if fields := form.fields; len(fields) > 0 || sum != "" || contentType != "" || correlationID != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(uploadResponse{
+			File:              storedName,
+			Message:           message,
+			Fields:            fields,
+			Checksum:          sum,
+			ChecksumAlgorithm: checksumAlgorithm(sum, s.config.ComputeHashOnUpload),
+			ContentType:       contentType,
+			CorrelationID:     correlationID,
+		})
+		return
+	}
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Файл %s успешно загружен", header.Filename)))
+	w.Write([]byte(message))
+}
+
+// uploadResponse ответ на загрузку с дополнительными полями формы, контрольной суммой,
+// типом содержимого или идентификатором корреляции
+type uploadResponse struct {
+	File    string            `json:"file"` // Имя файла в хранилище
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"` // Принятые поля формы, кроме файла
+
+	Checksum          string `json:"checksum,omitempty"` // Сумма ComputeHashOnUpload в hex
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+
+	ContentType   string `json:"content_type,omitempty"`   // Тип содержимого при DetectContentType
+	CorrelationID string `json:"correlation_id,omitempty"` // Идентификатор корреляции из запроса
+}
+
+// checksumAlgorithm возвращает имя алгоритма для ответа или пустую строку, если сумма не вычислялась
+func checksumAlgorithm(sum string, algo HashAlgorithm) string {
+	if sum == "" {
+		return ""
+	}
+	return string(algo)
+}
+
+// errFileTooLarge возвращается uploadReader при превышении лимита размера файла
+var errFileTooLarge = errors.New("размер файла превышает лимит")
+
+// errSizeMismatch возвращается uploadReader, если размер файла отличается от X-File-Size
+var errSizeMismatch = errors.New("размер файла не совпадает с заголовком " + FileSizeHeader)
+
+// FileSizeHeader заголовок с размером файла в байтах. Сервер использует его для прогресса
+// вместо Content-Length, который включает разметку формы или неизвестен, и сверяет с принятым
+const FileSizeHeader = "X-File-Size"
+
+// parseFileSizeHeader возвращает размер из заголовка X-File-Size (0, если заголовка нет)
+func parseFileSizeHeader(r *http.Request) (int64, error) {
+	value := r.Header.Get(FileSizeHeader)
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("ожидалось неотрицательное число, получено %q", value)
+	}
+	return size, nil
+}
+
+// uploadReader считает принятые байты, проверяет лимит размера и сообщает о прогрессе
+type uploadReader struct {
+	r        io.Reader
+	n        int64
+	limit    int64 // Максимальный размер (0 — без ограничения)
+	total    int64 // Ожидаемый размер для прогресса (0 — неизвестен)
+	declared int64 // Размер из X-File-Size, с которым сверяется принятый (0 — не проверяется)
+	progress ProgressCallback
+	session  *uploadSession    // Сессия, в которой обновляется число принятых байт (может быть nil)
+	quota    *quotaReservation // Резерв квоты хранилища, расширяемый по мере приема (может быть nil)
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	u.n += int64(n)
+	if u.session != nil {
+		u.session.bytesReceived.Store(u.n)
+	}
+	if u.limit > 0 && u.n > u.limit {
+		return n, errFileTooLarge
+	}
+	if u.declared > 0 && (u.n > u.declared || (err == io.EOF && u.n != u.declared)) {
+		return n, fmt.Errorf("%w: ожидалось %d байт, получено %d", errSizeMismatch, u.declared, u.n)
+	}
+	if err := u.quota.cover(u.n); err != nil {
+		return n, err
+	}
+	if n > 0 && u.total > 0 && u.progress != nil {
+		u.progress(u.n, u.total, float64(u.n)/float64(u.total)*100)
+	}
+	return n, err
+}
+
+// cleanRelativePath проверяет относительный путь из заголовка и приводит его к виду ОС.
+// Каждый компонент пути очищается через sanitizeFilename
+func cleanRelativePath(relPath string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(relPath))
+	if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." ||
+		strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("путь %q выходит за пределы директории загрузки", relPath)
+	}
+
+	parts := strings.Split(filepath.ToSlash(cleaned), "/")
+	for i, part := range parts {
+		parts[i] = sanitizeFilename(part)
+	}
+	return filepath.Join(parts...), nil
+}
+
+// SanitizeFilename приводит имя файла от клиента к безопасному виду так же,
+// как обработчик загрузки (см. sanitizeFilename). Используется другими транспортами,
+// сохраняющими файлы в StorageBackend
+func SanitizeFilename(name string) string {
+	return sanitizeFilename(name)
+}
+
+// maxFilenameLength максимальная длина имени файла
+const maxFilenameLength = 255
+
+// sanitizeFilename приводит имя файла от клиента к безопасному виду: убирает
+// компоненты директорий и нулевые байты, заменяет символы вне [a-zA-Z0-9._-]
+// на подчеркивание и ограничивает длину 255 символами. Имя вида временного файла
+// (см. tempFileName) получает "_" вместо начальной точки, иначе его удалила бы
+// очистка брошенных временных файлов
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\x00", "")
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base(name)
+
+	var b strings.Builder
+	for _, r := range name {
+		if r == '.' || r == '_' || r == '-' ||
+			('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	name = b.String()
+	if len(name) > maxFilenameLength {
+		name = name[:maxFilenameLength]
+	}
+	if name == "." || name == ".." {
+		return "unnamed"
+	}
+	if tempFileName.MatchString(name) {
+		name = "_" + name[1:]
+	}
+	return name
 }
 
 // formatBytes форматирует байты в читаемый вид
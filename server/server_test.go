@@ -0,0 +1,553 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newUploadRequest создает multipart-запрос на загрузку файла
+func newUploadRequest(t *testing.T, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("Ошибка создания поля формы: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// upload выполняет загрузку через обработчик сервера и возвращает код ответа
+func upload(t *testing.T, s *HTTPServer, filename string, content []byte) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, filename, content))
+	return rec.Code
+}
+
+// listFiles возвращает отсортированный список файлов директории с содержимым
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Ошибка чтения директории: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		content, _ := os.ReadFile(filepath.Join(dir, entry.Name()))
+		files = append(files, entry.Name()+"="+string(content))
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"обычное имя", "binary_1MB.bin", "binary_1MB.bin"},
+		{"выход вверх", "../../etc/passwd", "passwd"},
+		{"выход вверх windows", "..\\..\\windows\\system32\\cmd.exe", "cmd.exe"},
+		{"абсолютный путь", "/etc/shadow", "shadow"},
+		{"только родительская директория", "..", "unnamed"},
+		{"пустое имя", "", "unnamed"},
+		{"нулевой байт", "file\x00.bin", "file.bin"},
+		{"пробелы и спецсимволы", "my file (1).bin", "my_file__1_.bin"},
+		{"unicode", "отчёт.pdf", "_____.pdf"},
+		{"emoji", "🚀.bin", "_.bin"},
+		{"длинное имя", strings.Repeat("a", 300), strings.Repeat("a", 255)},
+		{"имя временного файла", ".tmp.0123456789abcdef0123456789abcdef.a.bin", "_tmp.0123456789abcdef0123456789abcdef.a.bin"},
+		{"похожее на временное", ".tmp.notes.txt", ".tmp.notes.txt"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := sanitizeFilename(test.input); result != test.expected {
+				t.Errorf("Для %q ожидалось %q, получено %q", test.input, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestCleanRelativePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"subdir/file.bin", filepath.Join("subdir", "file.bin"), false},
+		{"a/./b/../c.bin", filepath.Join("a", "c.bin"), false},
+		{"папка/файл.bin", filepath.Join("_____", "____.bin"), false},
+		{"../escape.bin", "", true},
+		{"a/../../escape.bin", "", true},
+		{"/etc/passwd", "", true},
+		{".", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := cleanRelativePath(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Для %q ожидалась ошибка: %v, получено: %v", test.input, test.wantErr, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("Для %q ожидалось %q, получено %q", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestCollisionPolicy(t *testing.T) {
+	tests := []struct {
+		policy   string
+		expected []string
+	}{
+		{CollisionOverwrite, []string{"data.bin=second"}},
+		{CollisionSkip, []string{"data.bin=first"}},
+		{CollisionRename, []string{"data.bin=first", "data_1.bin=second"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, CollisionPolicy: test.policy})
+
+			for _, content := range []string{"first", "second"} {
+				if code := upload(t, s, "data.bin", []byte(content)); code != http.StatusOK {
+					t.Fatalf("Ожидался статус 200, получен %d", code)
+				}
+			}
+
+			files := listFiles(t, uploadDir)
+			if strings.Join(files, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("Ожидались файлы %v, получены %v", test.expected, files)
+			}
+		})
+	}
+}
+
+func TestCollisionPolicy_Concurrent(t *testing.T) {
+	const uploads = 10
+
+	tests := []struct {
+		policy        string
+		expectedFiles int
+	}{
+		{CollisionOverwrite, 1},
+		{CollisionSkip, 1},
+		{CollisionRename, uploads},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, CollisionPolicy: test.policy})
+
+			var wg sync.WaitGroup
+			for i := 0; i < uploads; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					if code := upload(t, s, "same.bin", []byte(fmt.Sprintf("content-%d", i))); code != http.StatusOK {
+						t.Errorf("Ожидался статус 200, получен %d", code)
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			files := listFiles(t, uploadDir)
+			if len(files) != test.expectedFiles {
+				t.Errorf("Ожидалось %d файлов, получено %d: %v", test.expectedFiles, len(files), files)
+			}
+			for _, file := range files {
+				if !strings.Contains(file, "=content-") {
+					t.Errorf("Файл с неполным содержимым или временный файл: %s", file)
+				}
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), EnableMetrics: true, MaxFileSizeBytes: 16})
+
+	if code := upload(t, s, "ok.bin", []byte("0123456789")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := upload(t, s, "big.bin", bytes.Repeat([]byte("x"), 32)); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Ожидался статус 413, получен %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, expected := range []string{
+		"http_upload_bytes_total 10\n",
+		"http_upload_files_total 1\n",
+		`http_upload_errors_total{type="too_large"} 1` + "\n",
+		`http_upload_duration_seconds_bucket{le="+Inf"} 1` + "\n",
+		"http_upload_duration_seconds_count 1\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("В метриках отсутствует строка %q:\n%s", expected, body)
+		}
+	}
+}
+
+func TestMetrics_IgnoresFileRequests(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:     t.TempDir(),
+		EnableMetrics: true,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	// Ошибки запросов, не принимающих файл, не считаются ошибками загрузки
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/files/missing.bin/meta", nil),
+		httptest.NewRequest(http.MethodDelete, "/files/missing.bin", nil),
+		httptest.NewRequest(http.MethodPost, "/files", nil),
+		httptest.NewRequest(http.MethodGet, "/upload/status/unknown", nil),
+	} {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code < 400 {
+			t.Fatalf("%s %s: ожидалась ошибка, получен %d", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if strings.Contains(rec.Body.String(), "http_upload_errors_total{") {
+		t.Errorf("Ошибки запросов к файлам не должны учитываться:\n%s", rec.Body.String())
+	}
+}
+
+func TestTraced_ExtractsTraceparent(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	s := NewHTTPServerWithConfig(&ServerConfig{TracingEnabled: true})
+	var received string
+	handler := s.traced(func(w http.ResponseWriter, r *http.Request) {
+		received = trace.SpanContextFromContext(r.Context()).TraceID().String()
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler(httptest.NewRecorder(), req)
+
+	if received != traceID {
+		t.Errorf("Ожидался trace id %s, получен %s", traceID, received)
+	}
+}
+
+// blockingBackend хранилище, которое сигнализирует о начале сохранения
+// и ждет разрешения на его завершение
+type blockingBackend struct {
+	*MemoryStorageBackend
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingBackend) Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error) {
+	close(b.started)
+	<-b.release
+	return b.MemoryStorageBackend.Save(ctx, filename, r, metadata)
+}
+
+// freePort возвращает свободный TCP-порт
+func freePort(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Ошибка поиска свободного порта: %v", err)
+	}
+	defer listener.Close()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+// startServer запускает сервер и ждет, пока он начнет принимать соединения
+func startServer(t *testing.T, s *HTTPServer) chan error {
+	t.Helper()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Start()
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		network, address := "tcp", "127.0.0.1:"+s.port
+		if s.config.SocketPath != "" {
+			network, address = "unix", s.config.SocketPath
+		}
+		conn, err := net.Dial(network, address)
+		if err == nil {
+			conn.Close()
+			return errChan
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Сервер не запустился: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestShutdown_WaitsForUploads(t *testing.T) {
+	backend := &blockingBackend{
+		MemoryStorageBackend: NewMemoryStorageBackend(),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	s := NewHTTPServerWithConfig(&ServerConfig{Port: freePort(t), Backend: backend, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	serverErr := startServer(t, s)
+
+	// Загрузка начинается до остановки сервера
+	req := newUploadRequest(t, "report.bin", []byte("payload"))
+	req.RequestURI = ""
+	req.URL, _ = url.Parse("http://127.0.0.1:" + s.port + "/upload")
+	respChan := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("Ошибка загрузки: %v", err)
+		}
+		respChan <- resp
+	}()
+	<-backend.started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- s.Shutdown(context.Background())
+	}()
+
+	// Shutdown не завершается, пока загрузка не закончена
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown завершился до окончания загрузки: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(backend.release)
+
+	if resp := <-respChan; resp != nil {
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Ожидался статус 200, получен %d", resp.StatusCode)
+		}
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Errorf("Ошибка остановки сервера: %v", err)
+	}
+	if err := <-serverErr; err != nil {
+		t.Errorf("Start вернул ошибку после Shutdown: %v", err)
+	}
+	if data, ok := backend.Contents("report.bin"); !ok || string(data) != "payload" {
+		t.Errorf("Файл не сохранен: %q", data)
+	}
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	backend := &blockingBackend{
+		MemoryStorageBackend: NewMemoryStorageBackend(),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	defer close(backend.release)
+	s := NewHTTPServerWithConfig(&ServerConfig{Port: freePort(t), Backend: backend, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	serverErr := startServer(t, s)
+
+	req := newUploadRequest(t, "report.bin", []byte("payload"))
+	req.RequestURI = ""
+	req.URL, _ = url.Parse("http://127.0.0.1:" + s.port + "/upload")
+	go func() {
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-backend.started
+
+	// Загрузка не успевает завершиться: соединение закрывается по истечении контекста
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ожидалась ошибка context.DeadlineExceeded, получено %v", err)
+	}
+	if err := <-serverErr; err != nil {
+		t.Errorf("Start вернул ошибку после Shutdown: %v", err)
+	}
+}
+
+func TestHandleUpload_FileSizeHeader(t *testing.T) {
+	content := []byte("declared content")
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"совпадает", strconv.Itoa(len(content)), http.StatusOK},
+		{"меньше принятого", strconv.Itoa(len(content) - 1), http.StatusBadRequest},
+		{"больше принятого", strconv.Itoa(len(content) + 1), http.StatusBadRequest},
+		{"больше лимита", "1048576", http.StatusRequestEntityTooLarge},
+		{"некорректный", "-5", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			s := NewHTTPServerWithConfig(&ServerConfig{
+				UploadDir:        uploadDir,
+				MaxFileSizeBytes: 1024,
+				Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+			})
+
+			req := newUploadRequest(t, "sized.bin", content)
+			req.Header.Set(FileSizeHeader, tt.header)
+			rec := httptest.NewRecorder()
+			s.handleUpload(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Ожидался статус %d, получен %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if files := listFiles(t, uploadDir); (len(files) == 1) != (tt.status == http.StatusOK) {
+				t.Errorf("Неверное содержимое директории: %v", files)
+			}
+		})
+	}
+}
+
+func TestHandleUpload_ExtraFields(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir: t.TempDir(),
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("description", "Отчет")
+	writer.WriteField("tags", "q3,finance")
+	part, _ := writer.CreateFormFile("file", "report.bin")
+	part.Write([]byte("report"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response uploadResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Ошибка разбора ответа %q: %v", rec.Body.String(), err)
+	}
+	if response.File != "report.bin" || len(response.Fields) != 2 ||
+		response.Fields["description"] != "Отчет" || response.Fields["tags"] != "q3,finance" {
+		t.Errorf("Неверный ответ: %+v", response)
+	}
+
+	// Без дополнительных полей ответ остается текстовым
+	rec = httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "plain.bin", []byte("plain")))
+	if rec.Header().Get("Content-Type") == "application/json" {
+		t.Errorf("Ответ без полей не должен быть JSON: %s", rec.Body.String())
+	}
+}
+
+// fuzzBoundary граница multipart-формы в FuzzHandleUpload
+const fuzzBoundary = "fuzzboundary"
+
+// fuzzUploadBody формирует корректное тело запроса на загрузку для начального корпуса
+func fuzzUploadBody(filename string, content []byte) []byte {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.SetBoundary(fuzzBoundary)
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write(content)
+	writer.Close()
+	return body.Bytes()
+}
+
+func FuzzHandleUpload(f *testing.F) {
+	f.Add(fuzzUploadBody("test.bin", []byte("hello")))
+	f.Add(fuzzUploadBody("empty.bin", nil))
+	f.Add(fuzzUploadBody("large.bin", bytes.Repeat([]byte{0xAB}, 64*1024)))
+	f.Add(fuzzUploadBody("../../etc/passwd", []byte("escape")))
+	f.Add(fuzzUploadBody("dir/sub/..\\..\\win.txt", []byte("escape")))
+	f.Add(fuzzUploadBody("имя файла.txt", []byte("unicode")))
+	f.Add(fuzzUploadBody(".", []byte("dot")))
+	f.Add([]byte("--" + fuzzBoundary + "\r\nContent-Disposition: form-data; name=\"file\"\r\n\r\n"))
+	f.Add([]byte{})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f.Fuzz(func(t *testing.T, body []byte) {
+		root, err := os.MkdirTemp("", "fuzz-upload-")
+		if err != nil {
+			t.Fatalf("Ошибка создания директории: %v", err)
+		}
+		t.Cleanup(func() { os.RemoveAll(root) })
+		uploadDir := filepath.Join(root, "uploads")
+
+		s := NewHTTPServerWithConfig(&ServerConfig{
+			UploadDir:        uploadDir,
+			MaxFileSizeBytes: 1 << 20,
+			ChunkDir:         filepath.Join(root, "chunks"),
+			Logger:           logger,
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+fuzzBoundary)
+		rec := httptest.NewRecorder()
+		s.handleUpload(rec, req)
+
+		if rec.Code < 100 || rec.Code > 599 {
+			t.Errorf("Некорректный статус ответа: %d", rec.Code)
+		}
+
+		// Все созданные файлы находятся в директории загрузки, временных файлов не осталось
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || path == root {
+				return err
+			}
+			rel, _ := filepath.Rel(uploadDir, path)
+			if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				t.Errorf("Файл создан вне директории загрузки: %s", path)
+			}
+			if strings.HasPrefix(d.Name(), ".tmp.") {
+				t.Errorf("Остался временный файл: %s", path)
+			}
+			return nil
+		})
+	})
+}
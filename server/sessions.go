@@ -0,0 +1,115 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SessionIDHeader заголовок ответа с идентификатором сессии загрузки
+const SessionIDHeader = "X-Upload-Session-ID"
+
+// Состояния сессии загрузки
+const (
+	SessionInProgress = "in_progress"
+	SessionComplete   = "complete"
+	SessionFailed     = "failed"
+)
+
+// sessionRetention время, в течение которого завершенная сессия доступна
+// через GET /upload/status/{sessionID}
+const sessionRetention = time.Hour
+
+// UploadStatus состояние сессии загрузки, возвращаемое GET /upload/status/{sessionID}
+type UploadStatus struct {
+	State         string `json:"state"`
+	BytesReceived int64  `json:"bytes_received"`
+	Filename      string `json:"filename"`
+}
+
+// uploadSession состояние одной загрузки. Байты обновляются при чтении тела,
+// поэтому хранятся атомарно; остальные поля защищены мьютексом
+type uploadSession struct {
+	bytesReceived atomic.Int64
+
+	mu       sync.Mutex
+	state    string
+	filename string
+}
+
+// status возвращает снимок состояния сессии
+func (u *uploadSession) status() UploadStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return UploadStatus{State: u.state, BytesReceived: u.bytesReceived.Load(), Filename: u.filename}
+}
+
+// setFilename запоминает имя принимаемого файла
+func (u *uploadSession) setFilename(name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.filename = name
+}
+
+// finish переводит сессию в итоговое состояние, если она еще не завершена
+func (u *uploadSession) finish(state string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.state == SessionInProgress {
+		u.state = state
+	}
+}
+
+// newSessionID возвращает случайный UUID версии 4
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	id[6] = id[6]&0x0f | 0x40 // Версия 4
+	id[8] = id[8]&0x3f | 0x80 // Вариант RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+// startSession регистрирует новую сессию загрузки
+func (s *HTTPServer) startSession() (string, *uploadSession, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return "", nil, err
+	}
+	session := &uploadSession{state: SessionInProgress}
+	s.sessions.Store(id, session)
+	return id, session, nil
+}
+
+// endSession завершает сессию: незавершенная успешно сессия считается
+// неудачной. Сессия удаляется через sessionRetention
+func (s *HTTPServer) endSession(id string, session *uploadSession) {
+	session.finish(SessionFailed)
+	time.AfterFunc(sessionRetention, func() {
+		s.sessions.Delete(id)
+	})
+}
+
+// handleUploadStatus отдает состояние сессии загрузки: GET /upload/status/{sessionID}
+func (s *HTTPServer) handleUploadStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/upload/status/")
+	value, ok := s.sessions.Load(id)
+	if !ok {
+		s.requestError(w, r, fmt.Sprintf("Сессия %s не найдена", id), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value.(*uploadSession).status())
+}
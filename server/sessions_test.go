@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// uploadStatus запрашивает состояние сессии через обработчики сервера
+func uploadStatus(t *testing.T, handler http.Handler, sessionID string) (int, UploadStatus) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/upload/status/"+sessionID, nil))
+	var status UploadStatus
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatalf("Ошибка разбора ответа: %v", err)
+		}
+	}
+	return rec.Code, status
+}
+
+func TestUploadSession(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:          NewMemoryStorageBackend(),
+		MaxFileSizeBytes: 4,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	handler := s.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newUploadRequest(t, "ok.bin", []byte("data")))
+	sessionID := rec.Header().Get(SessionIDHeader)
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(sessionID) {
+		t.Fatalf("Некорректный идентификатор сессии: %q", sessionID)
+	}
+
+	code, status := uploadStatus(t, handler, sessionID)
+	if code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if status != (UploadStatus{State: SessionComplete, BytesReceived: 4, Filename: "ok.bin"}) {
+		t.Errorf("Неверное состояние успешной загрузки: %+v", status)
+	}
+
+	// Превышение лимита размера завершает сессию с ошибкой
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, newUploadRequest(t, "big.bin", []byte("too large")))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Ожидался статус 413, получен %d", rec.Code)
+	}
+	_, status = uploadStatus(t, handler, rec.Header().Get(SessionIDHeader))
+	if status.State != SessionFailed || status.Filename != "big.bin" {
+		t.Errorf("Неверное состояние неудачной загрузки: %+v", status)
+	}
+
+	if code, _ := uploadStatus(t, handler, "unknown"); code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404 для неизвестной сессии, получен %d", code)
+	}
+}
+
+func TestUploadSession_InProgress(t *testing.T) {
+	backend := &blockingBackend{
+		MemoryStorageBackend: NewMemoryStorageBackend(),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: backend, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	handler := s.Handler()
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newUploadRequest(t, "slow.bin", []byte("payload")))
+		done <- rec
+	}()
+	<-backend.started
+
+	// Пока хранилище не приняло файл, сессия находится в процессе загрузки
+	var sessionID string
+	s.sessions.Range(func(key, _ any) bool {
+		sessionID = key.(string)
+		return false
+	})
+	_, status := uploadStatus(t, handler, sessionID)
+	if status.State != SessionInProgress || status.Filename != "slow.bin" {
+		t.Errorf("Неверное состояние незавершенной загрузки: %+v", status)
+	}
+
+	close(backend.release)
+	rec := <-done
+	if got := rec.Header().Get(SessionIDHeader); got != sessionID {
+		t.Errorf("Идентификатор сессии в ответе %q не совпадает с %q", got, sessionID)
+	}
+	if _, status = uploadStatus(t, handler, sessionID); status.State != SessionComplete || status.BytesReceived != int64(len("payload")) {
+		t.Errorf("Неверное состояние завершенной загрузки: %+v", status)
+	}
+}
+
+func TestUploadStatus_MethodNotAllowed(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: NewMemoryStorageBackend(), Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload/status/id", strings.NewReader("")))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Ожидался статус 405, получен %d", rec.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultServeUploadsPrefix путь раздачи загруженных файлов, если ServerConfig.ServeUploadsPrefix не задан
+const DefaultServeUploadsPrefix = "/files/"
+
+// serveUploadsPrefix возвращает путь раздачи файлов с косой чертой в начале и в конце.
+// Пустой путь и корень заменяются DefaultServeUploadsPrefix: корень занят проверкой работы сервера
+func (s *HTTPServer) serveUploadsPrefix() string {
+	prefix := strings.Trim(s.config.ServeUploadsPrefix, "/")
+	if prefix == "" {
+		return DefaultServeUploadsPrefix
+	}
+	return "/" + prefix + "/"
+}
+
+// staticFiles раздает файлы UploadDir через http.FileServer по пути serveUploadsPrefix.
+// Листинг директорий запрещен (403), служебные файлы хранилища — временные, индекс
+// дедупликации и метаданные — не раздаются (404)
+func (s *HTTPServer) staticFiles() http.Handler {
+	fileServer := http.FileServer(http.Dir(s.config.UploadDir))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := path.Clean("/" + r.URL.Path)
+		if hiddenStaticFile(name) {
+			http.NotFound(w, r)
+			return
+		}
+		info, err := os.Stat(filepath.Join(s.config.UploadDir, filepath.FromSlash(name)))
+		if err == nil && info.IsDir() {
+			s.requestError(w, r, "Просмотр директорий запрещен", http.StatusForbidden)
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+	return http.StripPrefix(strings.TrimSuffix(s.serveUploadsPrefix(), "/"), handler)
+}
+
+// hiddenStaticFile сообщает, является ли путь служебным файлом хранилища: элемент пути,
+// начинающийся с точки (.tmp.*, .hash_index), или метаданные {name}.meta.json
+func hiddenStaticFile(name string) bool {
+	if strings.HasSuffix(name, metadataSuffix) {
+		return true
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if strings.HasPrefix(elem, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServeUploads(t *testing.T) {
+	for _, prefix := range []string{"", "/download/"} {
+		uploadDir := t.TempDir()
+		s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, ServeUploads: true, ServeUploadsPrefix: prefix})
+		if code := upload(t, s, "report.txt", []byte("report")); code != http.StatusOK {
+			t.Fatalf("Ошибка загрузки: %d", code)
+		}
+		os.MkdirAll(filepath.Join(uploadDir, "sub"), 0755)
+		os.WriteFile(filepath.Join(uploadDir, "sub", "a.txt"), []byte("nested"), 0644)
+		os.WriteFile(filepath.Join(uploadDir, ".tmp.123.b.txt"), []byte("partial"), 0644)
+		os.WriteFile(filepath.Join(uploadDir, "report.txt"+metadataSuffix), []byte("{}"), 0644)
+
+		base := s.serveUploadsPrefix()
+		tests := []struct {
+			method string
+			path   string
+			code   int
+			body   string
+		}{
+			{http.MethodGet, base + "report.txt", http.StatusOK, "report"},
+			{http.MethodGet, base + "sub/a.txt", http.StatusOK, "nested"},
+			{http.MethodGet, base + "sub/", http.StatusForbidden, ""},
+			{http.MethodGet, base + "sub", http.StatusForbidden, ""},
+			{http.MethodGet, base, http.StatusForbidden, ""},
+			{http.MethodGet, base + ".tmp.123.b.txt", http.StatusNotFound, ""},
+			{http.MethodGet, base + "report.txt" + metadataSuffix, http.StatusNotFound, ""},
+			{http.MethodGet, base + "missing.txt", http.StatusNotFound, ""},
+			{http.MethodPut, base + "report.txt", http.StatusMethodNotAllowed, ""},
+		}
+		for _, test := range tests {
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+			if rec.Code != test.code {
+				t.Errorf("%s %s: ожидался статус %d, получен %d", test.method, test.path, test.code, rec.Code)
+			}
+			if test.body != "" && rec.Body.String() != test.body {
+				t.Errorf("%s %s: получено %q вместо %q", test.method, test.path, rec.Body.String(), test.body)
+			}
+		}
+	}
+}
+
+func TestServeUploads_Disabled(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir()})
+	if code := upload(t, s, "report.txt", []byte("report")); code != http.StatusOK {
+		t.Fatalf("Ошибка загрузки: %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/files/report.txt", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Без ServeUploads ожидался статус 405, получен %d", rec.Code)
+	}
+}
+
+func TestServeUploadsPrefix(t *testing.T) {
+	tests := map[string]string{
+		"":          "/files/",
+		"/":         "/files/",
+		"download":  "/download/",
+		"/a/b/":     "/a/b/",
+		"/download": "/download/",
+	}
+	for prefix, expected := range tests {
+		s := &HTTPServer{config: &ServerConfig{ServeUploadsPrefix: prefix}}
+		if got := s.serveUploadsPrefix(); got != expected {
+			t.Errorf("Для %q ожидалось %q, получено %q", prefix, expected, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// uploadsPollInterval интервал проверки незавершенных загрузок при остановке
+const uploadsPollInterval = 10 * time.Millisecond
+
+// ServerStatus ответ GET /status
+type ServerStatus struct {
+	ActiveUploads int    `json:"active_uploads"` // Загрузки, обрабатываемые в данный момент
+	TotalUploads  uint64 `json:"total_uploads"`  // Успешно сохраненные файлы с момента запуска
+	UptimeSec     int64  `json:"uptime_sec"`
+}
+
+// ActiveUploads возвращает количество запросов, обрабатываемых в handleUpload
+func (s *HTTPServer) ActiveUploads() int {
+	return int(s.activeUploads.Load())
+}
+
+// trackUpload учитывает запрос в ActiveUploads; возвращает функцию завершения
+func (s *HTTPServer) trackUpload() func() {
+	s.activeUploads.Add(1)
+	return func() { s.activeUploads.Add(-1) }
+}
+
+// waitUploads ждет, пока ActiveUploads не станет равным нулю, или отмены ctx
+func (s *HTTPServer) waitUploads(ctx context.Context) error {
+	ticker := time.NewTicker(uploadsPollInterval)
+	defer ticker.Stop()
+
+	for s.ActiveUploads() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// handleStatus отдает состояние сервера: GET /status
+func (s *HTTPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := ServerStatus{
+		ActiveUploads: s.ActiveUploads(),
+		TotalUploads:  s.metrics.files(),
+		UptimeSec:     int64(time.Since(s.startTime).Seconds()),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
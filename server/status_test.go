@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActiveUploads(t *testing.T) {
+	backend := &blockingBackend{
+		MemoryStorageBackend: NewMemoryStorageBackend(),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: backend,
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	done := make(chan int)
+	go func() { done <- upload(t, s, "slow.bin", []byte("data")) }()
+	<-backend.started
+
+	if active := s.ActiveUploads(); active != 1 {
+		t.Errorf("Ожидалась 1 активная загрузка, получено %d", active)
+	}
+
+	// Остановка ждет незавершенную загрузку
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); err == nil {
+		t.Error("Shutdown не должен завершаться при активной загрузке")
+	}
+
+	close(backend.release)
+	if code := <-done; code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Ошибка остановки: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var status ServerStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("Ошибка разбора ответа: %v", err)
+	}
+	if status.ActiveUploads != 0 || status.TotalUploads != 1 || status.UptimeSec < 0 {
+		t.Errorf("Неверное состояние: %+v", status)
+	}
+}
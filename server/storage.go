@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Ключи метаданных, передаваемых в StorageBackend.Save
+const (
+	MetadataOriginalName = "original_name" // Имя файла, указанное клиентом
+	MetadataContentType  = "content_type"  // Content-Type части формы
+	MetadataRemoteAddr   = "remote_addr"   // Адрес клиента
+	MetadataStoredName   = "stored_name"   // Итоговое имя, если хранилище сохранило файл под другим именем
+)
+
+// ErrFileExists возвращается из StorageBackend.Save, если файл уже существует
+// и хранилище не стало его заменять
+var ErrFileExists = errors.New("файл уже существует")
+
+// ErrReservedName возвращается из StorageBackend.Save и FileDeleter.Delete, если имя
+// занято служебными файлами хранилища: файлами метаданных {filename}.meta.json
+// или индексом дедупликации .hash_index
+var ErrReservedName = errors.New("имя файла зарезервировано")
+
+// StorageBackend хранилище принятых файлов. Имя файла — очищенный относительный
+// путь с разделителем "/"
+type StorageBackend interface {
+	// Save сохраняет содержимое r под именем filename и возвращает количество
+	// записанных байт. Ошибки чтения r возвращаются обернутыми через %w.
+	// Если файл сохранен под другим именем, оно записывается в metadata[MetadataStoredName]
+	Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error)
+
+	// Exists проверяет, существует ли файл с именем filename
+	Exists(filename string) (bool, error)
+}
+
+// LocalStorageBackend сохраняет файлы в директории на локальном диске.
+// Файл пишется во временный файл рядом с итоговым и переносится на место
+// после записи на диск, поэтому при обрыве соединения усеченный файл не появляется
+type LocalStorageBackend struct {
+	Dir             string // Директория для сохранения файлов
+	CollisionPolicy string // overwrite, skip или rename
+
+	// Versioning при перезаписи (политика overwrite) сохраняет прежнее содержимое
+	// как версию {name}.v{timestamp}{ext}
+	Versioning bool
+
+	// Deduplicate сохраняет одинаковое содержимое один раз: файлы с совпадающим SHA-256
+	// становятся жесткими ссылками на копию в директории .hash_index. Копия удаляется,
+	// когда удален или перезаписан последний ссылающийся на нее файл
+	Deduplicate bool
+
+	index sync.Mutex // Сериализует добавление ссылок на индекс и удаление его записей
+}
+
+// NewLocalStorageBackend создает локальное хранилище
+func NewLocalStorageBackend(dir, collisionPolicy string) *LocalStorageBackend {
+	if collisionPolicy == "" {
+		collisionPolicy = CollisionOverwrite
+	}
+	return &LocalStorageBackend{Dir: dir, CollisionPolicy: collisionPolicy}
+}
+
+// Save реализует StorageBackend
+func (b *LocalStorageBackend) Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error) {
+	// Индекс резервируется и без дедупликации: записи, созданные клиентом,
+	// считались бы копиями содержимого после ее включения
+	if strings.HasSuffix(filename, metadataSuffix) || isReservedName(filename) {
+		return 0, fmt.Errorf("%w: %s", ErrReservedName, filename)
+	}
+	filePath := b.path(filename)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return 0, fmt.Errorf("ошибка создания директории: %w", err)
+	}
+
+	tmpPath, err := tempFilePath(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	var indexPath string
+	defer func() {
+		// Повторное закрытие безопасно; после переименования временного файла уже нет
+		dst.Close()
+		os.Remove(tmpPath)
+		// Если файл не сохранен, запись индекса могла остаться без ссылок
+		b.releaseIndexEntry(indexPath)
+	}()
+
+	// Контрольная сумма для дедупликации считается во время записи
+	var hasher hash.Hash
+	if b.Deduplicate {
+		hasher = sha256.New()
+		r = io.TeeReader(r, hasher)
+	}
+
+	written, err := io.CopyBuffer(dst, r, make([]byte, 64*1024))
+	if err != nil {
+		return written, fmt.Errorf("ошибка передачи данных в файл: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return written, err
+	}
+
+	// Сбрасываем данные на диск и атомарно переносим файл
+	if err := dst.Sync(); err != nil {
+		return written, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return written, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+
+	if hasher != nil {
+		sum := hex.EncodeToString(hasher.Sum(nil))
+		duplicate, err := b.deduplicate(tmpPath, sum)
+		if err != nil {
+			return written, err
+		}
+		indexPath = filepath.Join(b.Dir, hashIndexDir, sum)
+		if duplicate && metadata != nil {
+			metadata[MetadataDuplicate] = "true"
+		}
+	}
+
+	savedPath, err := b.commit(tmpPath, filePath)
+	if err != nil {
+		return written, err
+	}
+	if savedPath != filePath && metadata != nil {
+		rel, _ := filepath.Rel(b.Dir, savedPath)
+		metadata[MetadataStoredName] = filepath.ToSlash(rel)
+	}
+	return written, nil
+}
+
+// Exists реализует StorageBackend
+func (b *LocalStorageBackend) Exists(filename string) (bool, error) {
+	_, err := os.Stat(b.path(filename))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// path возвращает путь файла на диске
+func (b *LocalStorageBackend) path(filename string) string {
+	return filepath.Join(b.Dir, filepath.FromSlash(filename))
+}
+
+// commit переносит временный файл на итоговое место согласно политике коллизий
+// и возвращает путь сохраненного файла. Для skip и rename используется os.Link,
+// который атомарно завершается ошибкой, если файл уже существует, поэтому
+// параллельные загрузки не затирают друг друга
+func (b *LocalStorageBackend) commit(tmpPath, filePath string) (string, error) {
+	switch b.CollisionPolicy {
+	case CollisionSkip:
+		err := os.Link(tmpPath, filePath)
+		if errors.Is(err, fs.ErrExist) {
+			return "", ErrFileExists
+		}
+		if err != nil {
+			return "", fmt.Errorf("ошибка сохранения файла: %w", err)
+		}
+		return filePath, nil
+	case CollisionRename:
+		ext := filepath.Ext(filePath)
+		base := strings.TrimSuffix(filePath, ext)
+		candidate := filePath
+		for i := 1; i <= maxRenameAttempts; i++ {
+			err := os.Link(tmpPath, candidate)
+			if err == nil {
+				return candidate, nil
+			}
+			if !errors.Is(err, fs.ErrExist) {
+				return "", fmt.Errorf("ошибка сохранения файла: %w", err)
+			}
+			candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+		}
+		return "", fmt.Errorf("не удалось подобрать свободное имя для %s", filePath)
+	default:
+		if b.Versioning {
+			if err := b.keepVersion(filePath); err != nil {
+				return "", err
+			}
+		}
+		// Перезаписанное содержимое может остаться только в индексе дедупликации
+		replaced := b.indexEntry(filePath)
+		if err := os.Rename(tmpPath, filePath); err != nil {
+			return "", fmt.Errorf("ошибка сохранения файла: %w", err)
+		}
+		if err := b.releaseIndexEntry(replaced); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+}
+
+// MemoryStorageBackend хранит файлы в памяти. Предназначено для тестов:
+// позволяет проверить принятые байты без временных файлов на диске
+type MemoryStorageBackend struct {
+	mu       sync.RWMutex
+	files    map[string][]byte
+	metadata map[string]map[string]string
+}
+
+// NewMemoryStorageBackend создает пустое хранилище в памяти
+func NewMemoryStorageBackend() *MemoryStorageBackend {
+	return &MemoryStorageBackend{files: make(map[string][]byte), metadata: make(map[string]map[string]string)}
+}
+
+// Save реализует StorageBackend. Существующий файл перезаписывается
+func (b *MemoryStorageBackend) Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), fmt.Errorf("ошибка чтения данных: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return int64(len(data)), err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[filename] = data
+	return int64(len(data)), nil
+}
+
+// Exists реализует StorageBackend
+func (b *MemoryStorageBackend) Exists(filename string) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.files[filename]
+	return ok, nil
+}
+
+// Contents возвращает содержимое сохраненного файла
+func (b *MemoryStorageBackend) Contents(filename string) ([]byte, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	data, ok := b.files[filename]
+	return data, ok
+}
+
+// tempFilePath возвращает путь временного файла вида .tmp.{id}.{имя} рядом с итоговым файлом
+func tempFilePath(filePath string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	dir, name := filepath.Split(filePath)
+	return filepath.Join(dir, ".tmp."+hex.EncodeToString(id)+"."+name), nil
+}
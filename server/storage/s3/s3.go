@@ -0,0 +1,324 @@
+// Package s3 реализует server.StorageBackend поверх S3-совместимого хранилища.
+// Запросы подписываются AWS Signature Version 4 без внешних зависимостей
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"httpBinaryClient/server"
+)
+
+// Размер части multipart-загрузки
+const (
+	defaultPartSize = 8 * 1024 * 1024
+	minPartSize     = 5 * 1024 * 1024 // Минимальный размер части, кроме последней, допустимый в S3
+)
+
+// defaultRegion регион по умолчанию
+const defaultRegion = "us-east-1"
+
+// S3Config параметры подключения к S3-совместимому хранилищу
+type S3Config struct {
+	Endpoint  string // Адрес сервиса host[:port], например s3.amazonaws.com или localhost:9000
+	Bucket    string
+	Region    string // По умолчанию us-east-1
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+
+	PartSize int64                   // Размер части multipart-загрузки (0 — 8MB, не меньше 5MB)
+	Progress server.ProgressCallback // Вызывается по мере чтения данных; размер заранее неизвестен, поэтому totalBytes и percentage равны 0
+	Client   *http.Client            // HTTP-клиент для запросов (nil — http.DefaultClient)
+}
+
+// S3StorageBackend сохраняет файлы в бакет S3. Файл не буферизуется целиком:
+// содержимое читается частями по PartSize, и каждая часть отправляется
+// отдельным запросом multipart-загрузки. Файл не больше одной части
+// отправляется одним запросом PutObject
+type S3StorageBackend struct {
+	config   S3Config
+	client   *http.Client
+	partSize int64
+}
+
+// NewS3StorageBackend создает хранилище S3
+func NewS3StorageBackend(config S3Config) (*S3StorageBackend, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("не указан адрес S3")
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("не указан бакет S3")
+	}
+	if config.Region == "" {
+		config.Region = defaultRegion
+	}
+
+	partSize := config.PartSize
+	if partSize == 0 {
+		partSize = defaultPartSize
+	}
+	if partSize < minPartSize {
+		return nil, fmt.Errorf("размер части %d меньше минимального %d", partSize, minPartSize)
+	}
+
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &S3StorageBackend{config: config, client: client, partSize: partSize}, nil
+}
+
+// Save реализует server.StorageBackend
+func (b *S3StorageBackend) Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error) {
+	body := &countingReader{r: r, progress: b.config.Progress}
+
+	buffer := make([]byte, b.partSize)
+	n, err := io.ReadFull(body, buffer)
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		// Файл поместился в одну часть
+		if err := b.putObject(ctx, filename, buffer[:n], metadata); err != nil {
+			return body.n, err
+		}
+		return body.n, nil
+	case nil:
+	default:
+		return body.n, fmt.Errorf("ошибка чтения данных: %w", err)
+	}
+
+	if err := b.multipartUpload(ctx, filename, buffer, body, metadata); err != nil {
+		return body.n, err
+	}
+	return body.n, nil
+}
+
+// Exists реализует server.StorageBackend
+func (b *S3StorageBackend) Exists(filename string) (bool, error) {
+	resp, err := b.do(context.Background(), http.MethodHead, filename, nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("S3 вернул статус %d при проверке %s", resp.StatusCode, filename)
+	}
+}
+
+// putObject отправляет файл одним запросом
+func (b *S3StorageBackend) putObject(ctx context.Context, key string, data []byte, metadata map[string]string) error {
+	resp, err := b.do(ctx, http.MethodPut, key, nil, data, metadataHeaders(metadata))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp, "PutObject")
+}
+
+// multipartUpload отправляет файл частями. first — уже прочитанная первая часть;
+// буфер переиспользуется для следующих частей. При ошибке загрузка отменяется
+func (b *S3StorageBackend) multipartUpload(ctx context.Context, key string, first []byte, r io.Reader, metadata map[string]string) error {
+	uploadID, err := b.createMultipartUpload(ctx, key, metadata)
+	if err != nil {
+		return err
+	}
+
+	var parts []completedPart
+	buffer := first
+	n := len(first)
+	for partNumber := 1; ; partNumber++ {
+		etag, err := b.uploadPart(ctx, key, uploadID, partNumber, buffer[:n])
+		if err != nil {
+			b.abortMultipartUpload(key, uploadID)
+			return err
+		}
+		parts = append(parts, completedPart{PartNumber: partNumber, ETag: etag})
+
+		n, err = io.ReadFull(r, buffer)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			b.abortMultipartUpload(key, uploadID)
+			return fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+	}
+
+	if err := b.completeMultipartUpload(ctx, key, uploadID, parts); err != nil {
+		b.abortMultipartUpload(key, uploadID)
+		return err
+	}
+	return nil
+}
+
+// completedPart часть в запросе CompleteMultipartUpload
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// createMultipartUpload начинает multipart-загрузку и возвращает ее идентификатор
+func (b *S3StorageBackend) createMultipartUpload(ctx context.Context, key string, metadata map[string]string) (string, error) {
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, metadataHeaders(metadata))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, "CreateMultipartUpload"); err != nil {
+		return "", err
+	}
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("ошибка разбора ответа CreateMultipartUpload: %w", err)
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("S3 не вернул идентификатор multipart-загрузки")
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart отправляет одну часть и возвращает ее ETag
+func (b *S3StorageBackend) uploadPart(ctx context.Context, key, uploadID string, partNumber int, data []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+	resp, err := b.do(ctx, http.MethodPut, key, query, data, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, "UploadPart"); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// completeMultipartUpload собирает объект из отправленных частей
+func (b *S3StorageBackend) completeMultipartUpload(ctx context.Context, key, uploadID string, parts []completedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, "CompleteMultipartUpload"); err != nil {
+		return err
+	}
+
+	// S3 может вернуть ошибку в теле ответа со статусом 200
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if bytes.Contains(respBody, []byte("<Error>")) {
+		return fmt.Errorf("S3 вернул ошибку CompleteMultipartUpload: %s", respBody)
+	}
+	return nil
+}
+
+// abortMultipartUpload отменяет незавершенную загрузку, чтобы S3 не хранил отправленные части.
+// Выполняется с отдельным контекстом, так как контекст запроса может быть уже отменен
+func (b *S3StorageBackend) abortMultipartUpload(key, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := b.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+// do выполняет подписанный запрос к объекту key
+func (b *S3StorageBackend) do(ctx context.Context, method, key string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	scheme := "http"
+	if b.config.UseSSL {
+		scheme = "https"
+	}
+	u := &url.URL{
+		Scheme:   scheme,
+		Host:     b.config.Endpoint,
+		Path:     "/" + b.config.Bucket + "/" + key,
+		RawPath:  "/" + uriEncode(b.config.Bucket, false) + "/" + uriEncode(key, false),
+		RawQuery: canonicalQuery(query),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса к S3: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.ContentLength = int64(len(body))
+
+	signRequest(req, b.config, hashHex(body), time.Now())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к S3: %w", err)
+	}
+	return resp, nil
+}
+
+// checkResponse возвращает ошибку для статусов, отличных от 2xx
+func checkResponse(resp *http.Response, operation string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("S3 вернул статус %d для %s: %s", resp.StatusCode, operation, strings.TrimSpace(string(body)))
+}
+
+// metadataHeaders преобразует метаданные в заголовки x-amz-meta-*.
+// Значения вне ASCII кодируются по RFC 2047
+func metadataHeaders(metadata map[string]string) http.Header {
+	header := http.Header{}
+	for key, value := range metadata {
+		if value == "" {
+			continue
+		}
+		name := "X-Amz-Meta-" + strings.ReplaceAll(key, "_", "-")
+		header.Set(name, mime.QEncoding.Encode("utf-8", value))
+	}
+	return header
+}
+
+// countingReader считает прочитанные байты и сообщает о прогрессе
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	progress server.ProgressCallback
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if n > 0 && c.progress != nil {
+		c.progress(c.n, 0, 0)
+	}
+	return n, err
+}
+
+// Проверка соответствия интерфейсу на этапе компиляции
+var _ server.StorageBackend = (*S3StorageBackend)(nil)
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// mapBackend хранилище в памяти для проверки подключения StorageBackend
+type mapBackend struct {
+	mu       sync.Mutex
+	files    map[string]string
+	metadata map[string]map[string]string
+}
+
+func (b *mapBackend) Save(ctx context.Context, filename string, r io.Reader, metadata map[string]string) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[filename] = string(data)
+	b.metadata[filename] = metadata
+	return int64(len(data)), nil
+}
+
+func (b *mapBackend) Exists(filename string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.files[filename]
+	return ok, nil
+}
+
+func TestStorageBackend_Custom(t *testing.T) {
+	backend := &mapBackend{files: map[string]string{}, metadata: map[string]map[string]string{}}
+	uploadDir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: uploadDir, Backend: backend})
+
+	if code := upload(t, s, "report.bin", []byte("content")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	if backend.files["report.bin"] != "content" {
+		t.Errorf("Файл не сохранен в хранилище: %v", backend.files)
+	}
+	if backend.metadata["report.bin"][MetadataOriginalName] != "report.bin" {
+		t.Errorf("Неверные метаданные: %v", backend.metadata["report.bin"])
+	}
+	if files := listFiles(t, uploadDir); len(files) != 0 {
+		t.Errorf("Файлы не должны записываться в UploadDir: %v", files)
+	}
+}
+
+func TestStorageBackend_SizeLimit(t *testing.T) {
+	backend := &mapBackend{files: map[string]string{}, metadata: map[string]map[string]string{}}
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), Backend: backend, MaxFileSizeBytes: 4})
+
+	if code := upload(t, s, "big.bin", []byte("too large")); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Ожидался статус 413, получен %d", code)
+	}
+	if len(backend.files) != 0 {
+		t.Errorf("Слишком большой файл не должен сохраняться: %v", backend.files)
+	}
+}
+
+func TestLocalStorageBackend_Rename(t *testing.T) {
+	backend := NewLocalStorageBackend(t.TempDir(), CollisionRename)
+
+	for i := 0; i < 2; i++ {
+		metadata := map[string]string{}
+		written, err := backend.Save(context.Background(), "dir/file.bin", strings.NewReader("data"), metadata)
+		if err != nil || written != 4 {
+			t.Fatalf("Ошибка сохранения: %v (записано %d)", err, written)
+		}
+		if i == 1 && metadata[MetadataStoredName] != "dir/file_1.bin" {
+			t.Errorf("Ожидалось итоговое имя dir/file_1.bin, получено %q", metadata[MetadataStoredName])
+		}
+	}
+
+	for _, name := range []string{"dir/file.bin", "dir/file_1.bin"} {
+		if exists, err := backend.Exists(name); err != nil || !exists {
+			t.Errorf("Файл %s не найден: %v", name, err)
+		}
+	}
+	if exists, _ := backend.Exists("missing.bin"); exists {
+		t.Error("Несуществующий файл найден")
+	}
+}
+
+func TestHandleUpload_RenamedResponse(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{UploadDir: t.TempDir(), CollisionPolicy: CollisionRename})
+	upload(t, s, "same.bin", []byte("one"))
+
+	rec := httptest.NewRecorder()
+	s.handleUpload(rec, newUploadRequest(t, "same.bin", []byte("two")))
+	if !strings.Contains(rec.Body.String(), "как same_1.bin") {
+		t.Errorf("Ответ не содержит нового имени: %s", rec.Body.String())
+	}
+}
+
+func TestMemoryStorageBackend(t *testing.T) {
+	backend := NewMemoryStorageBackend()
+	s := NewHTTPServerWithConfig(&ServerConfig{Backend: backend})
+
+	if code := upload(t, s, "memory.bin", []byte("in memory")); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	data, ok := backend.Contents("memory.bin")
+	if !ok || string(data) != "in memory" {
+		t.Errorf("Неверное содержимое: %q (найден: %v)", data, ok)
+	}
+	if exists, _ := backend.Exists("memory.bin"); !exists {
+		t.Error("Файл не найден через Exists")
+	}
+	if _, ok := backend.Contents("missing.bin"); ok {
+		t.Error("Несуществующий файл найден")
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// errBodyReadTimeout возвращается при чтении тела запроса после истечения BodyReadTimeout
+var errBodyReadTimeout = errors.New("истекло время чтения тела запроса")
+
+// deadlineBody тело запроса, чтение которого прекращается после истечения ctx
+type deadlineBody struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	if b.ctx.Err() != nil {
+		return 0, errBodyReadTimeout
+	}
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.ctx.Err() != nil {
+		// Ошибка чтения вызвана дедлайном соединения
+		return n, errBodyReadTimeout
+	}
+	return n, err
+}
+
+// limitBodyReadTime ограничивает время чтения тела запроса значением BodyReadTimeout.
+// В отличие от http.Server.ReadTimeout отсчет идет от начала обработки запроса,
+// а не от приема соединения, и действует только для обработчиков загрузки.
+// Дедлайн также выставляется на соединение, чтобы прервать заблокированное чтение.
+// Возвращаемая функция снимает ограничение и должна быть вызвана по завершении обработки
+func (s *HTTPServer) limitBodyReadTime(w http.ResponseWriter, r *http.Request) func() {
+	timeout := s.config.BodyReadTimeout
+	if timeout <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	deadline, _ := ctx.Deadline()
+	rc := http.NewResponseController(w)
+	// Для ResponseWriter без поддержки дедлайнов остается проверка ctx перед каждым чтением
+	rc.SetReadDeadline(deadline)
+	r.Body = &deadlineBody{ReadCloser: r.Body, ctx: ctx}
+
+	return func() {
+		cancel()
+		rc.SetReadDeadline(time.Time{})
+	}
+}
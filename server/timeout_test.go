@@ -0,0 +1,58 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBodyReadTimeout_SlowClient(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:         NewMemoryStorageBackend(),
+		BodyReadTimeout: 100 * time.Millisecond,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Ошибка подключения: %v", err)
+	}
+	defer conn.Close()
+
+	// Клиент объявляет тело в 1MB, но отправляет только начало формы и замолкает
+	fmt.Fprintf(conn, "POST /upload HTTP/1.1\r\nHost: localhost\r\n"+
+		"Content-Type: multipart/form-data; boundary=b\r\nContent-Length: 1048576\r\n\r\n"+
+		"--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"slow.bin\"\r\n\r\ndata")
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("Ошибка чтения ответа: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestTimeout {
+		t.Errorf("Ожидался статус 408, получен %d", resp.StatusCode)
+	}
+	if _, ok := s.storage.(*MemoryStorageBackend).Contents("slow.bin"); ok {
+		t.Error("Файл медленного клиента не должен сохраняться")
+	}
+}
+
+func TestBodyReadTimeout_FastClient(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:         NewMemoryStorageBackend(),
+		BodyReadTimeout: time.Minute,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if code := upload(t, s, "fast.bin", []byte("data")); code != http.StatusOK {
+		t.Errorf("Ожидался статус 200, получен %d", code)
+	}
+}
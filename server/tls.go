@@ -0,0 +1,55 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig возвращает настройки TLS сервера или nil, если TLSCertFile не задан.
+// При заданном ClientCA сервер требует сертификат клиента, подписанный этим CA.
+// Используется в Start и позволяет запустить Handler() в собственном http.Server
+func (c *ServerConfig) TLSConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		if c.ClientCA != "" {
+			return nil, fmt.Errorf("для проверки сертификатов клиентов нужен сертификат сервера (TLSCertFile и TLSKeyFile)")
+		}
+		return nil, nil
+	}
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("сертификат и ключ сервера должны быть указаны вместе")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки сертификата сервера: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCA != "" {
+		pool, err := loadCertPool(c.ClientCA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// loadCertPool загружает сертификаты CA из PEM-файла
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения сертификата CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("файл %s не содержит сертификатов в формате PEM", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSConfig_Errors(t *testing.T) {
+	if config, err := (&ServerConfig{}).TLSConfig(); config != nil || err != nil {
+		t.Errorf("Без сертификата TLS не должен настраиваться: %v, %v", config, err)
+	}
+
+	tests := []struct {
+		name   string
+		config ServerConfig
+	}{
+		{"CA без сертификата сервера", ServerConfig{ClientCA: "ca.pem"}},
+		{"сертификат без ключа", ServerConfig{TLSCertFile: "server.pem"}},
+		{"отсутствующие файлы", ServerConfig{TLSCertFile: "missing.pem", TLSKeyFile: "missing-key.pem"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.config.TLSConfig(); err == nil {
+				t.Error("Ожидалась ошибка")
+			}
+		})
+	}
+}
+
+func TestLoadCertPool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0644); err != nil {
+		t.Fatalf("Ошибка создания файла: %v", err)
+	}
+	if _, err := loadCertPool(path); err == nil {
+		t.Error("Ожидалась ошибка для файла без сертификатов")
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName имя трассировщика сервера
+const tracerName = "httpBinaryClient/server"
+
+// traced извлекает контекст трассировки из заголовка traceparent и выполняет
+// обработчик внутри дочернего span "handle_upload"
+func (s *HTTPServer) traced(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.config.TracingEnabled {
+			next(w, r)
+			return
+		}
+
+		ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, "handle_upload",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("client.address", r.RemoteAddr),
+			))
+		defer span.End()
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// recordSpanError отмечает ошибку в span запроса, если он есть
+func recordSpanError(r *http.Request, msg string, status int) {
+	span := trace.SpanFromContext(r.Context())
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(attribute.Int("http.status_code", status))
+	span.SetStatus(codes.Error, msg)
+}
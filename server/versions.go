@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileVersion сохраненная предыдущая версия файла
+type FileVersion struct {
+	Name      string    `json:"name"`      // Имя файла версии в хранилище
+	Timestamp int64     `json:"timestamp"` // Время замены версии, наносекунды Unix; используется в URL
+	Size      int64     `json:"size"`
+	Created   time.Time `json:"created"`
+}
+
+// FileVersioner хранилище, сохраняющее предыдущие версии перезаписанных файлов
+type FileVersioner interface {
+	// Versions возвращает версии файла, новые первыми
+	Versions(filename string) ([]FileVersion, error)
+
+	// OpenVersion открывает версию файла; для отсутствующей версии возвращает ошибку,
+	// для которой errors.Is(err, fs.ErrNotExist) истинно
+	OpenVersion(filename string, timestamp int64) (io.ReadSeekCloser, error)
+}
+
+// versionPath возвращает путь версии файла: {name}.v{timestamp}{ext}
+func versionPath(filePath string, timestamp int64) string {
+	ext := filepath.Ext(filePath)
+	return fmt.Sprintf("%s.v%d%s", strings.TrimSuffix(filePath, ext), timestamp, ext)
+}
+
+// parseVersionName возвращает метку времени, если name — имя версии файла base
+func parseVersionName(name, base string) (int64, bool) {
+	ext := filepath.Ext(base)
+	rest, ok := strings.CutPrefix(name, strings.TrimSuffix(base, ext)+".v")
+	if !ok {
+		return 0, false
+	}
+	digits, ok := strings.CutSuffix(rest, ext)
+	if !ok || digits == "" {
+		return 0, false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	timestamp, err := strconv.ParseInt(digits, 10, 64)
+	return timestamp, err == nil
+}
+
+// isVersionName проверяет, является ли name именем версии какого-либо файла
+func isVersionName(name string) bool {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	// Имя версии файла без расширения выглядит как name.v123: расширение — сама метка
+	if strings.HasPrefix(ext, ".v") {
+		if _, ok := parseVersionName(name, stem); ok {
+			return true
+		}
+	}
+	i := strings.LastIndex(stem, ".v")
+	if i <= 0 {
+		return false
+	}
+	_, ok := parseVersionName(name, stem[:i]+ext)
+	return ok
+}
+
+// keepVersion сохраняет существующий файл как версию перед заменой. Используется
+// жесткая ссылка, поэтому файл под исходным именем не пропадает ни на мгновение,
+// а os.Link атомарно завершается ошибкой, если имя версии уже занято
+func (b *LocalStorageBackend) keepVersion(filePath string) error {
+	timestamp := time.Now().UnixNano()
+	for {
+		err := os.Link(filePath, versionPath(filePath, timestamp))
+		switch {
+		case err == nil, errors.Is(err, fs.ErrNotExist):
+			// Файла еще нет — сохранять нечего
+			return nil
+		case errors.Is(err, fs.ErrExist):
+			timestamp++
+		default:
+			return fmt.Errorf("ошибка сохранения версии файла: %w", err)
+		}
+	}
+}
+
+// Versions реализует FileVersioner
+func (b *LocalStorageBackend) Versions(filename string) ([]FileVersion, error) {
+	filePath := b.path(filename)
+	entries, err := os.ReadDir(filepath.Dir(filePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения директории: %w", err)
+	}
+
+	base := filepath.Base(filePath)
+	var versions []FileVersion
+	for _, entry := range entries {
+		timestamp, ok := parseVersionName(entry.Name(), base)
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		versions = append(versions, FileVersion{
+			Name:      entry.Name(),
+			Timestamp: timestamp,
+			Size:      info.Size(),
+			Created:   time.Unix(0, timestamp).UTC(),
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Timestamp > versions[j].Timestamp })
+	return versions, nil
+}
+
+// OpenVersion реализует FileVersioner
+func (b *LocalStorageBackend) OpenVersion(filename string, timestamp int64) (io.ReadSeekCloser, error) {
+	return os.Open(versionPath(b.path(filename), timestamp))
+}
+
+// handleVersions обрабатывает запросы к версиям файла:
+// GET /files/{filename}/versions — список версий,
+// GET /files/{filename}/versions/{timestamp} — содержимое версии
+func (s *HTTPServer) handleVersions(w http.ResponseWriter, r *http.Request, filename, timestamp string) {
+	if r.Method != http.MethodGet {
+		s.requestError(w, r, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
+	}
+
+	versioner, ok := s.storage.(FileVersioner)
+	if !ok {
+		s.requestError(w, r, "Хранилище не поддерживает версии файлов", http.StatusNotImplemented)
+		return
+	}
+
+	if timestamp == "" {
+		versions, err := versioner.Versions(filename)
+		if err != nil {
+			s.requestError(w, r, fmt.Sprintf("Ошибка получения списка версий: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if versions == nil {
+			versions = []FileVersion{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(versions)
+		return
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || ts < 0 {
+		s.requestError(w, r, "Некорректная метка времени версии", http.StatusBadRequest)
+		return
+	}
+	version, err := versioner.OpenVersion(filename, ts)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.requestError(w, r, fmt.Sprintf("Версия %s файла %s не найдена", timestamp, filename), http.StatusNotFound)
+			return
+		}
+		s.requestError(w, r, fmt.Sprintf("Ошибка открытия версии: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer version.Close()
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(versionPath(filename, ts))))
+	http.ServeContent(w, r, filename, time.Unix(0, ts), version)
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// getFile выполняет GET-запрос к обработчику сервера
+func getFile(t *testing.T, s *HTTPServer, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestVersioning_KeepsOverwrittenFiles(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:        dir,
+		EnableVersioning: true,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	for _, content := range []string{"first", "second", "third"} {
+		if code := upload(t, s, "data.bin", []byte(content)); code != http.StatusOK {
+			t.Fatalf("Ожидался статус 200, получен %d", code)
+		}
+	}
+
+	if current, _ := os.ReadFile(filepath.Join(dir, "data.bin")); string(current) != "third" {
+		t.Errorf("Текущая версия: %q, ожидалось third", current)
+	}
+
+	rec := getFile(t, s, "/files/data.bin/versions")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", rec.Code)
+	}
+	var versions []FileVersion
+	if err := json.Unmarshal(rec.Body.Bytes(), &versions); err != nil {
+		t.Fatalf("Ошибка разбора ответа: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("Ожидалось 2 версии, получено %d", len(versions))
+	}
+
+	// Версии отсортированы от новых к старым
+	for i, want := range []string{"second", "first"} {
+		rec := getFile(t, s, fmt.Sprintf("/files/data.bin/versions/%d", versions[i].Timestamp))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("Версия %d: статус %d, содержимое %q, ожидалось %q", i, rec.Code, rec.Body.String(), want)
+		}
+		if versions[i].Name != filepath.Base(versionPath("data.bin", versions[i].Timestamp)) {
+			t.Errorf("Неверное имя версии: %s", versions[i].Name)
+		}
+	}
+
+	// Версии не попадают в список файлов
+	var list FileList
+	json.Unmarshal(getFile(t, s, "/files").Body.Bytes(), &list)
+	if list.Total != 1 || list.Files[0].Name != "data.bin" {
+		t.Errorf("Список файлов содержит версии: %+v", list.Files)
+	}
+}
+
+func TestVersioning_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir: dir,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	upload(t, s, "data.bin", []byte("first"))
+	upload(t, s, "data.bin", []byte("second"))
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Без версионирования ожидался 1 файл, получено %d", len(entries))
+	}
+}
+
+func TestHandleVersions_Errors(t *testing.T) {
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		UploadDir:        t.TempDir(),
+		EnableVersioning: true,
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{"/files/missing.bin/versions", http.StatusOK},
+		{"/files/missing.bin/versions/123", http.StatusNotFound},
+		{"/files/missing.bin/versions/abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := getFile(t, s, tt.target); rec.Code != tt.wantStatus {
+			t.Errorf("%s: ожидался статус %d, получен %d", tt.target, tt.wantStatus, rec.Code)
+		}
+	}
+
+	memory := NewHTTPServerWithConfig(&ServerConfig{
+		Backend: NewMemoryStorageBackend(),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if rec := getFile(t, memory, "/files/a.bin/versions"); rec.Code != http.StatusNotImplemented {
+		t.Errorf("Ожидался статус 501, получен %d", rec.Code)
+	}
+}
+
+func TestVersionNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		want    int64
+		wantOK  bool
+		version bool
+	}{
+		{"data.v123.bin", "data.bin", 123, true, true},
+		{"archive.v5", "archive", 5, true, true},
+		{"data.bin", "data.bin", 0, false, false},
+		{"data.vx.bin", "data.bin", 0, false, false},
+		{"data.v.bin", "data.bin", 0, false, false},
+		{"other.v1.bin", "data.bin", 0, false, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseVersionName(tt.name, tt.base)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseVersionName(%q, %q) = %d, %v", tt.name, tt.base, got, ok)
+		}
+		if isVersionName(tt.name) != tt.version {
+			t.Errorf("isVersionName(%q) = %v, ожидалось %v", tt.name, !tt.version, tt.version)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSignatureHeader заголовок с подписью тела webhook: sha256=<HMAC-SHA256 в hex>
+const WebhookSignatureHeader = "X-Signature"
+
+// webhookTimeout ограничивает время доставки одного webhook
+const webhookTimeout = 10 * time.Second
+
+// UploadEvent тело webhook, отправляемого после успешной загрузки
+type UploadEvent struct {
+	Filename         string `json:"filename"`
+	SizeBytes        int64  `json:"size_bytes"`
+	SHA256           string `json:"sha256"`
+	SavedPath        string `json:"saved_path"` // Имя файла в хранилище
+	UploadDurationMs int64  `json:"upload_duration_ms"`
+}
+
+// uploadHasher возвращает reader, вычисляющий SHA-256 прочитанных данных, если
+// включен webhook или журнал аудита. Иначе r возвращается без изменений, а хеш равен nil
+func (s *HTTPServer) uploadHasher(r io.Reader) (io.Reader, hash.Hash) {
+	if s.config.WebhookURL == "" && s.audit == nil {
+		return r, nil
+	}
+	hasher := sha256.New()
+	return io.TeeReader(r, hasher), hasher
+}
+
+// notifyUpload отправляет webhook о загрузке в отдельной горутине: ответ клиенту
+// не ждет доставки, а ошибки доставки только записываются в лог
+func (s *HTTPServer) notifyUpload(event UploadEvent, hasher hash.Hash) {
+	if s.config.WebhookURL == "" {
+		return
+	}
+	if hasher != nil {
+		event.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	s.webhooks.Add(1)
+	go func() {
+		defer s.webhooks.Done()
+		if err := s.sendWebhook(event); err != nil {
+			s.logger().Warn("Не удалось доставить webhook", "url", s.config.WebhookURL, "file", event.Filename, "error", err)
+		}
+	}()
+}
+
+// sendWebhook выполняет POST на WebhookURL с подписанным телом
+func (s *HTTPServer) sendWebhook(event UploadEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(body, s.config.WebhookSecret))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("получатель вернул статус %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook возвращает значение заголовка X-Signature для тела webhook.
+// Получатель проверяет подпись, вычисляя HMAC-SHA256 тела с тем же секретом
+func SignWebhook(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
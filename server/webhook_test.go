@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// webhookDelivery принятый получателем webhook
+type webhookDelivery struct {
+	body      []byte
+	signature string
+}
+
+// newWebhookReceiver запускает получателя webhook, отвечающего статусом status
+func newWebhookReceiver(t *testing.T, status int) (*httptest.Server, chan webhookDelivery) {
+	t.Helper()
+
+	deliveries := make(chan webhookDelivery, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- webhookDelivery{body: body, signature: r.Header.Get(WebhookSignatureHeader)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver, deliveries
+}
+
+func TestWebhook_SignedEvent(t *testing.T) {
+	receiver, deliveries := newWebhookReceiver(t, http.StatusOK)
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:       NewMemoryStorageBackend(),
+		WebhookURL:    receiver.URL,
+		WebhookSecret: "secret",
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	content := []byte("webhook payload")
+	if code := upload(t, s, "event.bin", content); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+
+	var delivery webhookDelivery
+	select {
+	case delivery = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook не доставлен")
+	}
+
+	if delivery.signature != SignWebhook(delivery.body, "secret") {
+		t.Errorf("Неверная подпись: %s", delivery.signature)
+	}
+
+	var event UploadEvent
+	if err := json.Unmarshal(delivery.body, &event); err != nil {
+		t.Fatalf("Ошибка разбора тела webhook: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if event.Filename != "event.bin" || event.SavedPath != "event.bin" || event.SizeBytes != int64(len(content)) ||
+		event.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Неверное событие: %+v", event)
+	}
+}
+
+func TestWebhook_FailureDoesNotAffectUpload(t *testing.T) {
+	receiver, deliveries := newWebhookReceiver(t, http.StatusInternalServerError)
+	s := NewHTTPServerWithConfig(&ServerConfig{
+		Backend:    NewMemoryStorageBackend(),
+		WebhookURL: receiver.URL,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	if code := upload(t, s, "event.bin", []byte("data")); code != http.StatusOK {
+		t.Errorf("Ошибка webhook повлияла на ответ: статус %d", code)
+	}
+	<-deliveries
+
+	// Shutdown дожидается завершения отправки
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Ошибка остановки: %v", err)
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// Пример из документации GitHub webhooks
+	got := SignWebhook([]byte("Hello, World!"), "It's a Secret to Everybody")
+	want := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if got != want {
+		t.Errorf("Ожидалось %s, получено %s", want, got)
+	}
+}